@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/gofiber/fiber/v2"
@@ -47,12 +48,22 @@ func main() {
 	// Middleware
 	app.Use(recover.New())
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Idempotency-Key",
+		AllowMethods:  "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		ExposeHeaders: "Retry-After",
 	}))
 	app.Use(logger.New(logger.Config{
 		Format: "[${ip}]:${port} ${status} - ${method} ${path}\n",
+		CustomTags: map[string]logger.LogFunc{
+			// Incoming webhook tokens are credentials, keep them out of the logs
+			logger.TagPath: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+				if strings.HasPrefix(c.Path(), "/api/v1/hooks/") {
+					return output.WriteString("/api/v1/hooks/:token")
+				}
+				return output.WriteString(c.Path())
+			},
+		},
 	}))
 
 	// Public routes
@@ -61,6 +72,9 @@ func main() {
 	public.Post("/auth/register", httpHandler.Register)
 	public.Post("/auth/login", httpHandler.Login)
 	public.Post("/auth/validate", httpHandler.ValidateToken)
+	public.Post("/hooks/:token", httpHandler.PostIncomingWebhook)
+	public.Get("/unsubscribe", httpHandler.Unsubscribe)
+	public.Post("/unsubscribe", httpHandler.Unsubscribe)
 
 	// Protected routes (require authentication)
 	protected := app.Group("/api/v1")
@@ -69,12 +83,72 @@ func main() {
 	// Auth routes
 	protected.Get("/auth/profile", httpHandler.GetProfile)
 
+	// Organization routes
+	protected.Post("/orgs", httpHandler.CreateOrganization)
+	protected.Get("/orgs", httpHandler.ListOrganizations)
+	protected.Post("/orgs/:orgId/switch", httpHandler.SwitchOrganization)
+	protected.Get("/orgs/:orgId/members", httpHandler.ListOrganizationMembers)
+	protected.Post("/orgs/:orgId/members", httpHandler.AddOrganizationMember)
+	protected.Put("/orgs/:orgId/members/:userId/role", httpHandler.UpdateOrganizationMemberRole)
+	protected.Delete("/orgs/:orgId/members/:userId", httpHandler.RemoveOrganizationMember)
+
 	// Chat routes
 	protected.Post("/chat/rooms", httpHandler.CreateRoom)
 	protected.Get("/chat/rooms", httpHandler.GetRooms)
+	protected.Patch("/chat/rooms/:roomId", httpHandler.UpdateRoom)
+	protected.Delete("/chat/rooms/:roomId", httpHandler.DeleteRoom)
 	protected.Get("/chat/rooms/:roomId/messages", httpHandler.GetRoomMessages)
 	protected.Post("/chat/messages", httpHandler.SendMessage)
+	protected.Patch("/chat/messages/:messageId", httpHandler.EditMessage)
+	protected.Delete("/chat/messages/:messageId", httpHandler.DeleteMessage)
+	protected.Post("/chat/messages/:messageId/forward", httpHandler.ForwardMessage)
+	protected.Put("/chat/messages/:messageId/bookmark", httpHandler.BookmarkMessage)
+	protected.Delete("/chat/messages/:messageId/bookmark", httpHandler.RemoveBookmark)
+	protected.Get("/chat/bookmarks", httpHandler.ListBookmarks)
+	protected.Get("/chat/rooms/:roomId/notifications", httpHandler.GetRoomNotifications)
+	protected.Put("/chat/rooms/:roomId/notifications", httpHandler.SetRoomNotifications)
+	protected.Get("/chat/notification-settings", httpHandler.GetNotificationSettings)
+	protected.Put("/chat/notification-settings/dnd", httpHandler.SetDNDSchedule)
+	protected.Put("/chat/notification-settings/email-digests", httpHandler.SetEmailDigests)
+	protected.Post("/chat/push-devices", httpHandler.RegisterPushDevice)
+	protected.Get("/chat/push-devices", httpHandler.ListPushDevices)
+	protected.Delete("/chat/push-devices/:deviceId", httpHandler.UnregisterPushDevice)
 	protected.Post("/chat/rooms/join", httpHandler.JoinRoom)
+	protected.Get("/chat/rooms/:roomId/members", httpHandler.GetRoomMembers)
+	protected.Put("/chat/rooms/:roomId/members/:userId/role", httpHandler.SetMemberRole)
+	protected.Get("/chat/rooms/:roomId/pins", httpHandler.ListPinnedMessages)
+	protected.Post("/chat/rooms/:roomId/pins", httpHandler.PinMessage)
+	protected.Delete("/chat/rooms/:roomId/pins/:messageId", httpHandler.UnpinMessage)
+	protected.Put("/chat/rooms/:roomId/retention", httpHandler.SetRoomRetention)
+	protected.Get("/chat/rooms/:roomId/purges", httpHandler.GetPurgeRecords)
+	protected.Get("/chat/rooms/:roomId/moderation", httpHandler.GetRoomModeration)
+	protected.Put("/chat/rooms/:roomId/moderation", httpHandler.SetRoomModeration)
+	protected.Get("/chat/rooms/:roomId/moderation/queue", httpHandler.ListModerationQueue)
+	protected.Put("/chat/rooms/:roomId/slow-mode", httpHandler.SetSlowMode)
+	protected.Put("/chat/rooms/:roomId/message-ttl", httpHandler.SetMessageTTL)
+	protected.Post("/chat/rooms/:roomId/polls", httpHandler.CreatePoll)
+	protected.Post("/chat/polls/:pollId/votes", httpHandler.VotePoll)
+	protected.Post("/chat/polls/:pollId/close", httpHandler.ClosePoll)
+	protected.Get("/chat/scheduled-messages", httpHandler.ListScheduledMessages)
+	protected.Patch("/chat/scheduled-messages/:scheduledId", httpHandler.UpdateScheduledMessage)
+	protected.Delete("/chat/scheduled-messages/:scheduledId", httpHandler.CancelScheduledMessage)
+	protected.Post("/chat/moderation/reports", httpHandler.ReportMessage)
+	protected.Get("/chat/moderation/queue", httpHandler.ListModerationQueue)
+	protected.Post("/chat/moderation/queue/:itemId/claim", httpHandler.ClaimModerationItem)
+	protected.Post("/chat/moderation/queue/:itemId/resolve", httpHandler.ResolveModerationItem)
+	protected.Get("/chat/moderation/actions", httpHandler.ListModerationActions)
+	protected.Post("/chat/webhooks", httpHandler.CreateWebhook)
+	protected.Get("/chat/webhooks", httpHandler.ListWebhooks)
+	protected.Patch("/chat/webhooks/:webhookId", httpHandler.UpdateWebhook)
+	protected.Delete("/chat/webhooks/:webhookId", httpHandler.DeleteWebhook)
+	protected.Get("/chat/webhooks/:webhookId/deliveries", httpHandler.ListWebhookDeliveries)
+	protected.Post("/chat/rooms/:roomId/incoming-webhooks", httpHandler.CreateIncomingWebhook)
+	protected.Get("/chat/rooms/:roomId/incoming-webhooks", httpHandler.ListIncomingWebhooks)
+	protected.Delete("/chat/incoming-webhooks/:hookId", httpHandler.RevokeIncomingWebhook)
+	protected.Post("/chat/rooms/:roomId/exports", httpHandler.CreateExport)
+	protected.Get("/chat/rooms/:roomId/exports", httpHandler.ListExports)
+	protected.Get("/chat/exports/:exportId", httpHandler.GetExport)
+	protected.Get("/chat/exports/:exportId/download", httpHandler.DownloadExport)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// moderationErrorDomain matches the domain chat-service attaches to
+// moderation rejections.
+const moderationErrorDomain = "chat.moderation"
+
+// moderationRejection reports whether err is a moderation rejection and, if
+// so, the machine-readable reason the filter gave.
+func moderationRejection(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", false
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == moderationErrorDomain {
+			return info.Reason, true
+		}
+	}
+	return "", false
+}
+
+// rateLimited reports whether err is a rate limit and, if so, how long the
+// client should wait and why.
+func rateLimited(err error) (time.Duration, string, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return 0, "", false
+	}
+
+	var retryAfter time.Duration
+	var reason string
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.RetryInfo:
+			retryAfter = d.GetRetryDelay().AsDuration()
+		case *errdetails.ErrorInfo:
+			reason = d.Reason
+		}
+	}
+	return retryAfter, reason, true
+}
+
+// retryAfterSeconds rounds up so clients never retry too early.
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
\ No newline at end of file
@@ -2,10 +2,16 @@ package handler
 
 import (
 	"api-gateway/internal/models"
+	"api-gateway/internal/proto"
 	"api-gateway/pkg/clients"
+	"context"
+	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type HTTPHandler struct {
@@ -41,8 +47,14 @@ func (h *HTTPHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
+	if len(req.OrganizationName) > 100 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Organization name too long (max 100 characters)",
+		})
+	}
+
 	// Call auth service
-	resp, err := h.authClient.Register(c.Context(), req.Username, req.Email, req.Password)
+	resp, err := h.authClient.Register(c.Context(), req.Username, req.Email, req.Password, req.OrganizationName)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Error:   "Registration failed",
@@ -58,7 +70,8 @@ func (h *HTTPHandler) Register(c *fiber.Ctx) error {
 			CreatedAt: resp.User.CreatedAt,
 			UpdatedAt: resp.User.UpdatedAt,
 		},
-		Token: resp.Token,
+		Token:        resp.Token,
+		Organization: toOrganizationMembershipResponse(resp.Membership),
 	})
 }
 
@@ -78,8 +91,14 @@ func (h *HTTPHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Call auth service
-	resp, err := h.authClient.Login(c.Context(), req.Email, req.Password)
+	resp, err := h.authClient.Login(c.Context(), req.Email, req.Password, req.OrgID)
 	if err != nil {
+		if req.OrgID != "" && status.Code(err) == codes.NotFound {
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error:   "Login failed",
+				Message: "Not a member of this organization",
+			})
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
 			Error:   "Login failed",
 			Message: "Invalid email or password",
@@ -94,7 +113,8 @@ func (h *HTTPHandler) Login(c *fiber.Ctx) error {
 			CreatedAt: resp.User.CreatedAt,
 			UpdatedAt: resp.User.UpdatedAt,
 		},
-		Token: resp.Token,
+		Token:        resp.Token,
+		Organization: toOrganizationMembershipResponse(resp.Membership),
 	})
 }
 
@@ -125,6 +145,8 @@ func (h *HTTPHandler) ValidateToken(c *fiber.Ctx) error {
 		Valid:    resp.Valid,
 		UserID:   resp.UserId,
 		Username: resp.Username,
+		OrgID:    resp.OrgId,
+		OrgRole:  resp.OrgRole,
 	})
 }
 
@@ -138,12 +160,10 @@ func (h *HTTPHandler) GetProfile(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(models.UserResponse{
-		ID:        resp.User.Id,
-		Username:  resp.User.Username,
-		Email:     resp.User.Email,
-		CreatedAt: resp.User.CreatedAt,
-		UpdatedAt: resp.User.UpdatedAt,
+	return c.JSON(models.ProfileResponse{
+		UserResponse: toUserResponse(resp.User),
+		OrgID:        c.Locals("orgID").(string),
+		OrgRole:      c.Locals("orgRole").(string),
 	})
 }
 
@@ -168,7 +188,7 @@ func (h *HTTPHandler) CreateRoom(c *fiber.Ctx) error {
 
 	userID := c.Locals("userID").(string)
 
-	resp, err := h.chatClient.CreateRoom(c.Context(), req.Name, req.Description, userID)
+	resp, err := h.chatClient.CreateRoom(chatContext(c), req.Name, req.Description, userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Error:   "Failed to create room",
@@ -182,7 +202,7 @@ func (h *HTTPHandler) CreateRoom(c *fiber.Ctx) error {
 }
 
 func (h *HTTPHandler) GetRooms(c *fiber.Ctx) error {
-	resp, err := h.chatClient.GetRooms(c.Context())
+	resp, err := h.chatClient.GetRooms(chatContext(c), c.QueryBool("include_pinned_count", false))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Error:   "Failed to get rooms",
@@ -195,11 +215,115 @@ func (h *HTTPHandler) GetRooms(c *fiber.Ctx) error {
 	})
 }
 
+func (h *HTTPHandler) UpdateRoom(c *fiber.Ctx) error {
+	req := models.UpdateRoomRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Name == nil && req.Description == nil && !req.Archived {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Nothing to update",
+		})
+	}
+
+	roomID := c.Params("roomId")
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.UpdateRoom(chatContext(c), roomID, userID, req.Name, req.Description, req.Archived)
+	if err != nil {
+		return roomError(c, err, "Failed to update room")
+	}
+
+	return c.JSON(fiber.Map{
+		"room": resp.Room,
+	})
+}
+
+func (h *HTTPHandler) DeleteRoom(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.DeleteRoom(chatContext(c), c.Params("roomId"), userID)
+	if err != nil {
+		return roomError(c, err, "Failed to delete room")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+// roomError maps the chat service's errors for room owner actions to HTTP
+// statuses.
+func roomError(c *fiber.Ctx, err error, message string) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   message,
+			Message: status.Convert(err).Message(),
+		})
+	case codes.NotFound:
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error: "Room not found",
+		})
+	case codes.PermissionDenied:
+		return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+			Error: "Only the room owner can do this",
+		})
+	case codes.FailedPrecondition:
+		return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+			Error:   message,
+			Message: status.Convert(err).Message(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
 func (h *HTTPHandler) GetRoomMessages(c *fiber.Ctx) error {
 	roomID := c.Params("roomId")
+	userID := c.Locals("userID").(string)
 	limit := c.QueryInt("limit", 50)
 
-	resp, err := h.chatClient.GetRoomMessages(c.Context(), roomID, int32(limit))
+	// Clients filling a gap after a reconnect pass the last sequence number they saw
+	if c.Query("since_seq") != "" {
+		sinceSeq, err := strconv.ParseInt(c.Query("since_seq"), 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Invalid since_seq",
+			})
+		}
+
+		resp, err := h.chatClient.GetMessagesSince(chatContext(c), roomID, userID, sinceSeq, int32(limit))
+		if err != nil {
+			switch status.Code(err) {
+			case codes.NotFound:
+				return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+					Error: "Room not found",
+				})
+			case codes.PermissionDenied:
+				return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+					Error: "Only room members can read its messages",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Error:   "Failed to get room messages",
+				Message: err.Error(),
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"messages": resp.Messages,
+			"last_seq": resp.LastSeq,
+		})
+	}
+
+	resp, err := h.chatClient.GetRoomMessages(chatContext(c), roomID, userID, int32(limit))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Error:   "Failed to get room messages",
@@ -233,28 +357,175 @@ func (h *HTTPHandler) SendMessage(c *fiber.Ctx) error {
 		})
 	}
 
+	// Retries may carry the client ID in the body or as an Idempotency-Key header
+	clientMessageID := req.ClientMessageID
+	if clientMessageID == "" {
+		clientMessageID = c.Get("Idempotency-Key")
+	}
+
+	if len(clientMessageID) > 64 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Client message ID too long (max 64 characters)",
+		})
+	}
+
 	userID := c.Locals("userID").(string)
 
-	resp, err := h.chatClient.SendMessage(c.Context(), req.RoomID, userID, req.Content)
+	resp, err := h.chatClient.SendMessage(chatContext(c), req.RoomID, userID, req.Content, clientMessageID, req.ScheduledAt, req.QuoteMessageID, req.TTLSeconds)
 	if err != nil {
+		if reason, ok := moderationRejection(err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Message rejected by moderation",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		if retryAfter, reason, ok := rateLimited(err); ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(models.ErrorResponse{
+				Error:   "Too many messages",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		if status.Code(err) == codes.InvalidArgument {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Invalid message",
+				Message: status.Convert(err).Message(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Error:   "Failed to send message",
 			Message: err.Error(),
 		})
 	}
 
+	if resp.ScheduledMessage != nil {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"scheduled_message": resp.ScheduledMessage,
+		})
+	}
+
 	return c.JSON(models.SendMessageResponse{
-		Message: models.MessageResponse{
-			ID:        resp.Message.Id,
-			RoomID:    resp.Message.RoomId,
-			UserID:    resp.Message.UserId,
-			Username:  resp.Message.Username,
-			Content:   resp.Message.Content,
-			CreatedAt: resp.Message.CreatedAt,
-		},
+		Message:   toMessageResponse(resp.Message),
+		Duplicate: resp.Duplicate,
+	})
+}
+
+// ForwardMessage posts a copy of a message to another room.
+func (h *HTTPHandler) ForwardMessage(c *fiber.Ctx) error {
+	req := models.ForwardMessageRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.RoomID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Room ID is required",
+		})
+	}
+
+	clientMessageID := req.ClientMessageID
+	if clientMessageID == "" {
+		clientMessageID = c.Get("Idempotency-Key")
+	}
+
+	if len(clientMessageID) > 64 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Client message ID too long (max 64 characters)",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ForwardMessage(chatContext(c), c.Params("messageId"), req.RoomID, userID, clientMessageID)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error:   "Message or room not found",
+				Message: status.Convert(err).Message(),
+			})
+		case codes.PermissionDenied:
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error:   "Not allowed to forward this message",
+				Message: status.Convert(err).Message(),
+			})
+		}
+		if reason, ok := moderationRejection(err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Message rejected by moderation",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		if retryAfter, reason, ok := rateLimited(err); ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(models.ErrorResponse{
+				Error:   "Too many messages",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to forward message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(models.SendMessageResponse{
+		Message:   toMessageResponse(resp.Message),
+		Duplicate: resp.Duplicate,
 	})
 }
 
+func toMessageResponse(message *proto.Message) models.MessageResponse {
+	resp := models.MessageResponse{
+		ID:              message.Id,
+		RoomID:          message.RoomId,
+		Seq:             message.Seq,
+		Type:            message.Type,
+		UserID:          message.UserId,
+		Username:        message.Username,
+		Content:         message.Content,
+		PlainText:       message.PlainText,
+		Entities:        messageEntities(message.Entities),
+		ClientMessageID: message.ClientMessageId,
+		CreatedAt:       message.CreatedAt,
+		ExpiresAt:       message.ExpiresAt,
+	}
+	if reference := message.Reference; reference != nil {
+		resp.Reference = &models.MessageReference{
+			Type:      reference.Type,
+			MessageID: reference.MessageId,
+			RoomID:    reference.RoomId,
+			UserID:    reference.UserId,
+			Username:  reference.Username,
+			CreatedAt: reference.CreatedAt,
+			Content:   reference.Content,
+			Available: reference.Available,
+		}
+	}
+	return resp
+}
+
+func messageEntities(entities []*proto.MessageEntity) []models.MessageEntity {
+	result := make([]models.MessageEntity, len(entities))
+	for i, entity := range entities {
+		result[i] = models.MessageEntity{
+			Type:     entity.Type,
+			Offset:   entity.Offset,
+			Length:   entity.Length,
+			URL:      entity.Url,
+			Language: entity.Language,
+		}
+	}
+	return result
+}
+
 func (h *HTTPHandler) JoinRoom(c *fiber.Ctx) error {
 	req := models.JoinRoomRequest{}
 	
@@ -272,7 +543,7 @@ func (h *HTTPHandler) JoinRoom(c *fiber.Ctx) error {
 
 	userID := c.Locals("userID").(string)
 
-	resp, err := h.chatClient.JoinRoom(c.Context(), req.RoomID, userID)
+	resp, err := h.chatClient.JoinRoom(chatContext(c), req.RoomID, userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
 			Error:   "Failed to join room",
@@ -284,4 +555,1420 @@ func (h *HTTPHandler) JoinRoom(c *fiber.Ctx) error {
 		Success: resp.Success,
 		RoomID:  resp.RoomId,
 	})
+}
+
+func (h *HTTPHandler) SetMemberRole(c *fiber.Ctx) error {
+	req := models.SetMemberRoleRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Role is required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetMemberRole(chatContext(c), c.Params("roomId"), userID, c.Params("userId"), req.Role)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to set member role",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+func (h *HTTPHandler) PinMessage(c *fiber.Ctx) error {
+	req := models.PinMessageRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.MessageID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message ID is required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.PinMessage(chatContext(c), c.Params("roomId"), req.MessageID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to pin message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"pinned_message": resp.PinnedMessage,
+	})
+}
+
+func (h *HTTPHandler) UnpinMessage(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.UnpinMessage(chatContext(c), c.Params("roomId"), c.Params("messageId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to unpin message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+func (h *HTTPHandler) GetRoomMembers(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 50)
+
+	resp, err := h.chatClient.GetRoomMembers(chatContext(c), c.Params("roomId"), userID, c.Query("cursor"), int32(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get room members",
+			Message: err.Error(),
+		})
+	}
+
+	members := make([]models.RoomMemberResponse, len(resp.Members))
+	for i, member := range resp.Members {
+		members[i] = models.RoomMemberResponse{
+			UserID:     member.UserId,
+			Username:   member.Username,
+			Role:       member.Role,
+			JoinedAt:   member.JoinedAt,
+			Online:     member.Online,
+			MutedUntil: member.MutedUntil,
+		}
+	}
+
+	return c.JSON(models.RoomMembersResponse{
+		Members:     members,
+		NextCursor:  resp.NextCursor,
+		OnlineCount: resp.OnlineCount,
+	})
+}
+
+func (h *HTTPHandler) ListPinnedMessages(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ListPinnedMessages(chatContext(c), c.Params("roomId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get pinned messages",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"pinned_messages": resp.PinnedMessages,
+	})
+}
+
+func (h *HTTPHandler) SetRoomRetention(c *fiber.Ctx) error {
+	req := models.SetRoomRetentionRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetRoomRetention(chatContext(c), c.Params("roomId"), userID, req.Type, req.Value)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to set room retention",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"room": resp.Room,
+	})
+}
+
+func (h *HTTPHandler) GetPurgeRecords(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 20)
+
+	resp, err := h.chatClient.GetPurgeRecords(chatContext(c), c.Params("roomId"), userID, int32(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get purge records",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"records": resp.Records,
+	})
+}
+
+func (h *HTTPHandler) SetRoomModeration(c *fiber.Ctx) error {
+	req := models.SetRoomModerationRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	filters := make([]*proto.ModerationFilter, len(req.Filters))
+	for i, f := range req.Filters {
+		filters[i] = &proto.ModerationFilter{
+			Type:           f.Type,
+			Action:         f.Action,
+			Words:          f.Words,
+			Patterns:       f.Patterns,
+			MaxLinks:       f.MaxLinks,
+			AllowedDomains: f.AllowedDomains,
+			BlockedDomains: f.BlockedDomains,
+			MaxRepeat:      f.MaxRepeat,
+			MaxCapsRatio:   f.MaxCapsRatio,
+			MinLetters:     f.MinLetters,
+		}
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetRoomModeration(chatContext(c), c.Params("roomId"), userID, filters, req.UseDefault)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to set room moderation",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"filters":    resp.Filters,
+		"is_default": resp.IsDefault,
+	})
+}
+
+func (h *HTTPHandler) GetRoomModeration(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.GetRoomModeration(chatContext(c), c.Params("roomId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get room moderation",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"filters":    resp.Filters,
+		"is_default": resp.IsDefault,
+	})
+}
+
+func (h *HTTPHandler) ListModerationQueue(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 50)
+
+	// The global queue takes an optional room_id filter instead of a path parameter
+	roomID := c.Params("roomId", c.Query("room_id"))
+
+	resp, err := h.chatClient.ListModerationQueue(chatContext(c), roomID, userID, c.Query("status"), int32(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list moderation queue",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"items": resp.Items,
+	})
+}
+
+func (h *HTTPHandler) SetSlowMode(c *fiber.Ctx) error {
+	req := models.SetSlowModeRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetSlowMode(chatContext(c), c.Params("roomId"), userID, req.Seconds)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to set slow mode",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"room": resp.Room,
+	})
+}
+
+func (h *HTTPHandler) SetMessageTTL(c *fiber.Ctx) error {
+	req := models.SetMessageTTLRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetMessageTTL(chatContext(c), c.Params("roomId"), userID, req.Seconds)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to set message time-to-live",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"room": resp.Room,
+	})
+}
+
+func (h *HTTPHandler) ReportMessage(c *fiber.Ctx) error {
+	req := models.ReportMessageRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.MessageID == "" || req.Reason == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message ID and reason are required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ReportMessage(chatContext(c), req.MessageID, userID, req.Reason, req.Detail)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to report message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"item": resp.Item,
+	})
+}
+
+func (h *HTTPHandler) ClaimModerationItem(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ClaimModerationItem(chatContext(c), c.Params("itemId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to claim moderation item",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"item": resp.Item,
+	})
+}
+
+func (h *HTTPHandler) ResolveModerationItem(c *fiber.Ctx) error {
+	req := models.ResolveModerationItemRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ResolveModerationItem(chatContext(c), c.Params("itemId"), userID, req.Action, req.Note, req.MuteSeconds)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to resolve moderation item",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"item": resp.Item,
+	})
+}
+
+func (h *HTTPHandler) ListModerationActions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 50)
+
+	resp, err := h.chatClient.ListModerationActions(chatContext(c), c.Query("room_id"), userID, int32(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list moderation actions",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"actions": resp.Actions,
+	})
+}
+
+func (h *HTTPHandler) ListScheduledMessages(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ListScheduledMessages(chatContext(c), userID, c.Query("room_id"), c.Query("status"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list scheduled messages",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"scheduled_messages": resp.ScheduledMessages,
+	})
+}
+
+func (h *HTTPHandler) UpdateScheduledMessage(c *fiber.Ctx) error {
+	req := models.UpdateScheduledMessageRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Content != nil && len(*req.Content) > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message content too long (max 1000 characters)",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.UpdateScheduledMessage(chatContext(c), c.Params("scheduledId"), userID, req.Content, req.ScheduledAt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to update scheduled message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"scheduled_message": resp.ScheduledMessage,
+	})
+}
+
+func (h *HTTPHandler) CancelScheduledMessage(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.CancelScheduledMessage(chatContext(c), c.Params("scheduledId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to cancel scheduled message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"scheduled_message": resp.ScheduledMessage,
+	})
+}
+
+func (h *HTTPHandler) CreatePoll(c *fiber.Ctx) error {
+	req := models.CreatePollRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Question == "" || len(req.Options) < 2 || len(req.Options) > 10 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "A question and between 2 and 10 options are required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.CreatePoll(chatContext(c), &proto.CreatePollRequest{
+		RoomId:         c.Params("roomId"),
+		UserId:         userID,
+		Question:       req.Question,
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		ClosesAt:       req.ClosesAt,
+	})
+	if err != nil {
+		if retryAfter, reason, ok := rateLimited(err); ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(models.ErrorResponse{
+				Error:   "Too many messages",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to create poll",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": resp.Message,
+	})
+}
+
+func (h *HTTPHandler) VotePoll(c *fiber.Ctx) error {
+	req := models.VotePollRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.VotePoll(chatContext(c), c.Params("pollId"), userID, req.OptionIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to vote",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"poll": resp.Poll,
+	})
+}
+
+func (h *HTTPHandler) ClosePoll(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ClosePoll(chatContext(c), c.Params("pollId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to close poll",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"poll": resp.Poll,
+	})
+}
+
+func (h *HTTPHandler) EditMessage(c *fiber.Ctx) error {
+	req := models.EditMessageRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Content == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message content is required",
+		})
+	}
+
+	if len(req.Content) > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message content too long (max 1000 characters)",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.EditMessage(chatContext(c), c.Params("messageId"), userID, req.Content)
+	if err != nil {
+		if reason, ok := moderationRejection(err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Message rejected by moderation",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to edit message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": resp.Message,
+	})
+}
+
+func (h *HTTPHandler) DeleteMessage(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.DeleteMessage(chatContext(c), c.Params("messageId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to delete message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+func (h *HTTPHandler) CreateWebhook(c *fiber.Ctx) error {
+	req := models.CreateWebhookRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.URL == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Webhook URL is required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.CreateWebhook(chatContext(c), &proto.CreateWebhookRequest{
+		UserId: userID,
+		RoomId: req.RoomID,
+		Url:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to create webhook",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"webhook": resp.Webhook,
+	})
+}
+
+func (h *HTTPHandler) ListWebhooks(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ListWebhooks(chatContext(c), c.Query("room_id"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list webhooks",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": resp.Webhooks,
+	})
+}
+
+func (h *HTTPHandler) UpdateWebhook(c *fiber.Ctx) error {
+	req := models.UpdateWebhookRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	update := &proto.UpdateWebhookRequest{
+		WebhookId: c.Params("webhookId"),
+		UserId:    userID,
+		Url:       req.URL,
+		Secret:    req.Secret,
+		Active:    req.Active,
+	}
+	if req.Events != nil {
+		update.Events = *req.Events
+		update.UpdateEvents = true
+	}
+
+	resp, err := h.chatClient.UpdateWebhook(chatContext(c), update)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to update webhook",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"webhook": resp.Webhook,
+	})
+}
+
+func (h *HTTPHandler) DeleteWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.DeleteWebhook(chatContext(c), c.Params("webhookId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to delete webhook",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+func (h *HTTPHandler) ListWebhookDeliveries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 50)
+
+	resp, err := h.chatClient.ListWebhookDeliveries(chatContext(c), c.Params("webhookId"), userID, c.Query("status"), int32(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list webhook deliveries",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"deliveries": resp.Deliveries,
+	})
+}
+
+func (h *HTTPHandler) CreateIncomingWebhook(c *fiber.Ctx) error {
+	req := models.CreateIncomingWebhookRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Webhook name is required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.CreateIncomingWebhook(chatContext(c), c.Params("roomId"), userID, req.Name)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to create incoming webhook",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"webhook": resp.Webhook,
+		"token":   resp.Token,
+	})
+}
+
+func (h *HTTPHandler) ListIncomingWebhooks(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ListIncomingWebhooks(chatContext(c), c.Params("roomId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list incoming webhooks",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"webhooks": resp.Webhooks,
+	})
+}
+
+func (h *HTTPHandler) RevokeIncomingWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.RevokeIncomingWebhook(chatContext(c), c.Params("hookId"), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to revoke incoming webhook",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"webhook": resp.Webhook,
+	})
+}
+
+func (h *HTTPHandler) CreateExport(c *fiber.Ctx) error {
+	req := models.CreateExportRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Format != "jsonl" && req.Format != "csv" && req.Format != "html" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Format must be jsonl, csv or html",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.CreateExport(chatContext(c), c.Params("roomId"), userID, req.Format)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to create export",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"export": toExportResponse(resp.Export),
+	})
+}
+
+func (h *HTTPHandler) ListExports(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 20)
+
+	resp, err := h.chatClient.ListExports(chatContext(c), c.Params("roomId"), userID, int32(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list exports",
+			Message: err.Error(),
+		})
+	}
+
+	exports := make([]models.ExportResponse, len(resp.Exports))
+	for i, job := range resp.Exports {
+		exports[i] = toExportResponse(job)
+	}
+
+	return c.JSON(fiber.Map{
+		"exports": exports,
+	})
+}
+
+func (h *HTTPHandler) GetExport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.GetExport(chatContext(c), c.Params("exportId"), userID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error: "Export not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get export",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"export": toExportResponse(resp.Export),
+	})
+}
+
+// DownloadExport streams the file of a completed export from the chat
+// service to the client without buffering it.
+func (h *HTTPHandler) DownloadExport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	// The body is written after the handler returns, so the stream cannot
+	// use the request's context
+	ctx, cancel := context.WithCancel(withOrg(context.Background(), c))
+	stream, err := h.chatClient.DownloadExport(ctx, c.Params("exportId"), userID)
+	var first *proto.ExportChunk
+	if err == nil {
+		first, err = stream.Recv()
+	}
+	if err != nil {
+		cancel()
+		switch status.Code(err) {
+		case codes.NotFound:
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error: "Export not found",
+			})
+		case codes.PermissionDenied:
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error:   "Not allowed to download this export",
+				Message: status.Convert(err).Message(),
+			})
+		case codes.FailedPrecondition:
+			return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+				Error:   "Export is not ready",
+				Message: status.Convert(err).Message(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to download export",
+			Message: err.Error(),
+		})
+	}
+
+	c.Attachment(first.FileName)
+	c.Set(fiber.HeaderContentType, first.ContentType)
+	c.Context().SetBodyStream(&exportReader{stream: stream, data: first.Data, cancel: cancel}, int(first.Size))
+	return nil
+}
+
+// exportReader reads the chunks of an export download. fasthttp closes it
+// once the body is written, which ends the stream.
+type exportReader struct {
+	stream proto.ChatService_DownloadExportClient
+	data   []byte
+	cancel context.CancelFunc
+}
+
+func (r *exportReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.data = chunk.Data
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (r *exportReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+func toExportResponse(job *proto.ExportJob) models.ExportResponse {
+	resp := models.ExportResponse{
+		ID:               job.Id,
+		RoomID:           job.RoomId,
+		RequestedBy:      job.RequestedBy,
+		Format:           job.Format,
+		Status:           job.Status,
+		TotalMessages:    job.TotalMessages,
+		ExportedMessages: job.ExportedMessages,
+		FileName:         job.FileName,
+		FileSize:         job.FileSize,
+		Error:            job.Error,
+		CreatedAt:        job.CreatedAt,
+		StartedAt:        job.StartedAt,
+		CompletedAt:      job.CompletedAt,
+		ExpiresAt:        job.ExpiresAt,
+	}
+	if job.Status == "completed" {
+		resp.DownloadURL = "/api/v1/chat/exports/" + job.Id + "/download"
+	}
+	return resp
+}
+
+// PostIncomingWebhook posts a message with an incoming webhook token instead
+// of a user JWT. Like Slack, it also accepts the JSON body as a form-encoded
+// payload field.
+func (h *HTTPHandler) PostIncomingWebhook(c *fiber.Ctx) error {
+	payload := models.IncomingWebhookPayload{}
+
+	var err error
+	if form := c.FormValue("payload"); form != "" {
+		err = json.Unmarshal([]byte(form), &payload)
+	} else {
+		err = c.BodyParser(&payload)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	content := incomingWebhookContent(payload)
+	if content == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message content is required",
+		})
+	}
+
+	if len(content) > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Message content too long (max 1000 characters)",
+		})
+	}
+
+	clientMessageID := payload.ClientMessageID
+	if clientMessageID == "" {
+		clientMessageID = c.Get("Idempotency-Key")
+	}
+
+	if len(clientMessageID) > 64 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Client message ID too long (max 64 characters)",
+		})
+	}
+
+	resp, err := h.chatClient.PostIncomingWebhook(c.Context(), c.Params("token"), content, clientMessageID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error: "Unknown or revoked webhook token",
+			})
+		}
+		if reason, ok := moderationRejection(err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Message rejected by moderation",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		if retryAfter, reason, ok := rateLimited(err); ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(models.ErrorResponse{
+				Error:   "Too many messages",
+				Message: status.Convert(err).Message(),
+				Reason:  reason,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to post message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":        true,
+		"message":   resp.Message,
+		"duplicate": resp.Duplicate,
+	})
+}
+
+func (h *HTTPHandler) BookmarkMessage(c *fiber.Ctx) error {
+	req := models.BookmarkMessageRequest{}
+
+	// The body is optional
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error: "Invalid request body",
+			})
+		}
+	}
+
+	if len(req.Folder) > 64 || len(req.Note) > 1000 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Folder too long (max 64 characters) or note too long (max 1000 characters)",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.BookmarkMessage(chatContext(c), c.Params("messageId"), userID, req.Folder, req.Note)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error: "Message not found",
+			})
+		case codes.PermissionDenied:
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error:   "Not allowed to bookmark this message",
+				Message: status.Convert(err).Message(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to bookmark message",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"bookmark": toBookmarkResponse(resp.Bookmark),
+	})
+}
+
+func (h *HTTPHandler) RemoveBookmark(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.RemoveBookmark(chatContext(c), c.Params("messageId"), userID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error: "Bookmark not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to remove bookmark",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+func (h *HTTPHandler) ListBookmarks(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit := c.QueryInt("limit", 50)
+
+	resp, err := h.chatClient.ListBookmarks(chatContext(c), userID, c.Query("folder"), c.Query("cursor"), int32(limit))
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Invalid request",
+				Message: status.Convert(err).Message(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list bookmarks",
+			Message: err.Error(),
+		})
+	}
+
+	bookmarks := make([]models.BookmarkResponse, len(resp.Bookmarks))
+	for i, bookmark := range resp.Bookmarks {
+		bookmarks[i] = toBookmarkResponse(bookmark)
+	}
+	folders := make([]models.BookmarkFolder, len(resp.Folders))
+	for i, folder := range resp.Folders {
+		folders[i] = models.BookmarkFolder{
+			Name:  folder.Name,
+			Count: folder.Count,
+		}
+	}
+
+	return c.JSON(models.BookmarksResponse{
+		Bookmarks:  bookmarks,
+		NextCursor: resp.NextCursor,
+		Folders:    folders,
+	})
+}
+
+func toBookmarkResponse(bookmark *proto.Bookmark) models.BookmarkResponse {
+	resp := models.BookmarkResponse{
+		ID:        bookmark.Id,
+		MessageID: bookmark.MessageId,
+		RoomID:    bookmark.RoomId,
+		RoomName:  bookmark.RoomName,
+		Folder:    bookmark.Folder,
+		Note:      bookmark.Note,
+		CreatedAt: bookmark.CreatedAt,
+		UpdatedAt: bookmark.UpdatedAt,
+		Available: bookmark.Available,
+	}
+	if bookmark.Message != nil {
+		message := toMessageResponse(bookmark.Message)
+		resp.Message = &message
+	}
+	return resp
+}
+
+func (h *HTTPHandler) GetRoomNotifications(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.GetRoomNotifications(chatContext(c), c.Params("roomId"), userID)
+	if err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error: "Not a member of this room",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get notification settings",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(toRoomNotificationsResponse(resp.Notifications))
+}
+
+func (h *HTTPHandler) SetRoomNotifications(c *fiber.Ctx) error {
+	req := models.RoomNotificationsRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.NotifyLevel != "all" && req.NotifyLevel != "mentions" && req.NotifyLevel != "none" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Notify level must be all, mentions or none",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetRoomNotifications(chatContext(c), c.Params("roomId"), userID, req.NotifyLevel, req.MutedUntil)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Invalid notification settings",
+				Message: status.Convert(err).Message(),
+			})
+		case codes.PermissionDenied:
+			return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+				Error: "Not a member of this room",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to update notification settings",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(toRoomNotificationsResponse(resp.Notifications))
+}
+
+func (h *HTTPHandler) GetNotificationSettings(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.GetNotificationSettings(chatContext(c), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to get notification settings",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"dnd":           toDNDSchedule(resp.Dnd),
+		"email_digests": resp.EmailDigests,
+	})
+}
+
+func (h *HTTPHandler) SetDNDSchedule(c *fiber.Ctx) error {
+	req := models.DNDSchedule{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetDNDSchedule(chatContext(c), userID, &proto.DNDSchedule{
+		Enabled:  req.Enabled,
+		Start:    req.Start,
+		End:      req.End,
+		TimeZone: req.TimeZone,
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Invalid do-not-disturb schedule",
+				Message: status.Convert(err).Message(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to update do-not-disturb schedule",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"dnd": toDNDSchedule(resp.Dnd),
+	})
+}
+
+func (h *HTTPHandler) SetEmailDigests(c *fiber.Ctx) error {
+	req := models.EmailDigestsRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.SetEmailDigests(chatContext(c), userID, req.Enabled)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to update email digests",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"email_digests": resp.EmailDigests,
+	})
+}
+
+// Unsubscribe serves the unsubscribe links of emails. The token, signed by
+// auth-service, identifies the user, so no sign-in is needed. POST supports
+// one-click unsubscribing from mail clients.
+func (h *HTTPHandler) Unsubscribe(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Token is required",
+		})
+	}
+
+	validated, err := h.authClient.ValidateUnsubscribeToken(c.Context(), token)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to validate token",
+			Message: err.Error(),
+		})
+	}
+	if !validated.Valid {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid unsubscribe link",
+		})
+	}
+	if validated.List != "digest" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Unknown mailing",
+		})
+	}
+
+	if _, err := h.chatClient.SetEmailDigests(c.Context(), validated.UserId, false); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to unsubscribe",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "You will no longer receive email digests",
+	})
+}
+
+func toRoomNotificationsResponse(notifications *proto.RoomNotifications) models.RoomNotificationsResponse {
+	return models.RoomNotificationsResponse{
+		RoomID:      notifications.RoomId,
+		NotifyLevel: notifications.NotifyLevel,
+		MutedUntil:  notifications.MutedUntil,
+	}
+}
+
+func toDNDSchedule(dnd *proto.DNDSchedule) models.DNDSchedule {
+	return models.DNDSchedule{
+		Enabled:  dnd.Enabled,
+		Start:    dnd.Start,
+		End:      dnd.End,
+		TimeZone: dnd.TimeZone,
+	}
+}
+
+func (h *HTTPHandler) RegisterPushDevice(c *fiber.Ctx) error {
+	req := models.RegisterPushDeviceRequest{}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Platform != "fcm" && req.Platform != "apns" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Platform must be fcm or apns",
+		})
+	}
+
+	if req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Device token is required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.RegisterPushDevice(chatContext(c), userID, req.Platform, req.Token)
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+				Error:   "Invalid push device",
+				Message: status.Convert(err).Message(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to register push device",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(toPushDeviceResponse(resp.Device))
+}
+
+func (h *HTTPHandler) ListPushDevices(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.ListPushDevices(chatContext(c), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to list push devices",
+			Message: err.Error(),
+		})
+	}
+
+	devices := make([]models.PushDeviceResponse, len(resp.Devices))
+	for i, device := range resp.Devices {
+		devices[i] = toPushDeviceResponse(device)
+	}
+
+	return c.JSON(fiber.Map{
+		"devices": devices,
+	})
+}
+
+func (h *HTTPHandler) UnregisterPushDevice(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.chatClient.UnregisterPushDevice(chatContext(c), userID, c.Params("deviceId"))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error: "Push device not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Failed to unregister push device",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": resp.Success,
+	})
+}
+
+func toPushDeviceResponse(device *proto.PushDevice) models.PushDeviceResponse {
+	return models.PushDeviceResponse{
+		ID:         device.Id,
+		Platform:   device.Platform,
+		CreatedAt:  device.CreatedAt,
+		UpdatedAt:  device.UpdatedAt,
+		LastPushAt: device.LastPushAt,
+	}
 }
\ No newline at end of file
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+
+	"api-gateway/internal/models"
+	"api-gateway/internal/proto"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// chatContext is the request's context carrying the organization of the
+// caller's token, which chat-service scopes every call to.
+func chatContext(c *fiber.Ctx) context.Context {
+	return withOrg(c.Context(), c)
+}
+
+func withOrg(ctx context.Context, c *fiber.Ctx) context.Context {
+	orgID, _ := c.Locals("orgID").(string)
+	orgRole, _ := c.Locals("orgRole").(string)
+	userID, _ := c.Locals("userID").(string)
+	return metadata.AppendToOutgoingContext(ctx, "x-org-id", orgID, "x-org-role", orgRole, "x-user-id", userID)
+}
+
+func (h *HTTPHandler) CreateOrganization(c *fiber.Ctx) error {
+	var req models.CreateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.authClient.CreateOrganization(c.Context(), userID, req.Name)
+	if err != nil {
+		return organizationError(c, err, "Failed to create organization")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"organization": toOrganizationMembershipResponse(resp.Membership),
+	})
+}
+
+func (h *HTTPHandler) ListOrganizations(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.authClient.ListOrganizations(c.Context(), userID)
+	if err != nil {
+		return organizationError(c, err, "Failed to list organizations")
+	}
+
+	organizations := make([]models.OrganizationMembershipResponse, 0, len(resp.Memberships))
+	for _, membership := range resp.Memberships {
+		organizations = append(organizations, toOrganizationMembershipResponse(membership))
+	}
+
+	return c.JSON(fiber.Map{
+		"organizations": organizations,
+		"current":       c.Locals("orgID").(string),
+	})
+}
+
+// SwitchOrganization issues a token for another of the user's organizations.
+func (h *HTTPHandler) SwitchOrganization(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.authClient.SwitchOrganization(c.Context(), userID, c.Params("orgId"))
+	if err != nil {
+		return organizationError(c, err, "Failed to switch organization")
+	}
+
+	return c.JSON(fiber.Map{
+		"token":        resp.Token,
+		"organization": toOrganizationMembershipResponse(resp.Membership),
+	})
+}
+
+// ListOrganizationMembers is the organization's user directory.
+func (h *HTTPHandler) ListOrganizationMembers(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.authClient.ListOrganizationMembers(c.Context(), c.Params("orgId"), userID)
+	if err != nil {
+		return organizationError(c, err, "Failed to list organization members")
+	}
+
+	members := make([]models.OrganizationMemberResponse, 0, len(resp.Members))
+	for _, member := range resp.Members {
+		members = append(members, toOrganizationMemberResponse(member))
+	}
+
+	return c.JSON(fiber.Map{
+		"members": members,
+	})
+}
+
+func (h *HTTPHandler) AddOrganizationMember(c *fiber.Ctx) error {
+	var req models.AddOrganizationMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	if req.Login == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Login is required",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.authClient.AddOrganizationMember(c.Context(), c.Params("orgId"), userID, req.Login, req.Role)
+	if err != nil {
+		return organizationError(c, err, "Failed to add organization member")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"member": toOrganizationMemberResponse(resp.Member),
+	})
+}
+
+func (h *HTTPHandler) UpdateOrganizationMemberRole(c *fiber.Ctx) error {
+	var req models.UpdateOrganizationMemberRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error: "Invalid request body",
+		})
+	}
+
+	userID := c.Locals("userID").(string)
+
+	resp, err := h.authClient.UpdateOrganizationMemberRole(c.Context(), c.Params("orgId"), userID, c.Params("userId"), req.Role)
+	if err != nil {
+		return organizationError(c, err, "Failed to update organization member")
+	}
+
+	return c.JSON(fiber.Map{
+		"member": toOrganizationMemberResponse(resp.Member),
+	})
+}
+
+// RemoveOrganizationMember removes a member, or lets users leave when they
+// name themselves.
+func (h *HTTPHandler) RemoveOrganizationMember(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	if _, err := h.authClient.RemoveOrganizationMember(c.Context(), c.Params("orgId"), userID, c.Params("userId")); err != nil {
+		return organizationError(c, err, "Failed to remove organization member")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+	})
+}
+
+func organizationError(c *fiber.Ctx, err error, message string) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   message,
+			Message: status.Convert(err).Message(),
+		})
+	case codes.NotFound:
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Organization or user not found",
+			Message: status.Convert(err).Message(),
+		})
+	case codes.PermissionDenied:
+		return c.Status(fiber.StatusForbidden).JSON(models.ErrorResponse{
+			Error: "Only organization owners and admins can do this",
+		})
+	case codes.AlreadyExists:
+		return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+			Error: "Already a member of this organization",
+		})
+	case codes.FailedPrecondition:
+		return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+			Error:   message,
+			Message: status.Convert(err).Message(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func toUserResponse(user *proto.User) models.UserResponse {
+	return models.UserResponse{
+		ID:        user.GetId(),
+		Username:  user.GetUsername(),
+		Email:     user.GetEmail(),
+		CreatedAt: user.GetCreatedAt(),
+		UpdatedAt: user.GetUpdatedAt(),
+	}
+}
+
+func toOrganizationMembershipResponse(membership *proto.OrganizationMembership) models.OrganizationMembershipResponse {
+	org := membership.GetOrganization()
+	return models.OrganizationMembershipResponse{
+		Organization: models.OrganizationResponse{
+			ID:        org.GetId(),
+			Name:      org.GetName(),
+			CreatedBy: org.GetCreatedBy(),
+			CreatedAt: org.GetCreatedAt(),
+			UpdatedAt: org.GetUpdatedAt(),
+		},
+		Role:     membership.GetRole(),
+		JoinedAt: membership.GetJoinedAt(),
+	}
+}
+
+func toOrganizationMemberResponse(member *proto.OrganizationMember) models.OrganizationMemberResponse {
+	return models.OrganizationMemberResponse{
+		User:     toUserResponse(member.GetUser()),
+		Role:     member.GetRole(),
+		JoinedAt: member.GetJoinedAt(),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"regexp"
+	"strings"
+
+	"api-gateway/internal/models"
+)
+
+// slackControl matches Slack's <...> sequences: links, user and channel
+// mentions, and special mentions like <!here>.
+var slackControl = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]*))?>`)
+
+var slackUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// incomingWebhookContent turns a posted payload into message content. The
+// native content field is used as is; Slack bodies are converted to the
+// Markdown subset messages support, one paragraph per attachment.
+func incomingWebhookContent(payload models.IncomingWebhookPayload) string {
+	if payload.Content != "" {
+		return payload.Content
+	}
+
+	var paragraphs []string
+	if payload.Text != "" {
+		paragraphs = append(paragraphs, slackToMarkdown(payload.Text))
+	}
+	for _, attachment := range payload.Attachments {
+		if paragraph := slackAttachmentContent(attachment); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+}
+
+func slackAttachmentContent(attachment models.SlackAttachment) string {
+	var lines []string
+	if attachment.Pretext != "" {
+		lines = append(lines, slackToMarkdown(attachment.Pretext))
+	}
+	switch {
+	case attachment.Title != "" && attachment.TitleLink != "":
+		lines = append(lines, "["+attachment.Title+"]("+attachment.TitleLink+")")
+	case attachment.Title != "":
+		lines = append(lines, "**"+attachment.Title+"**")
+	}
+	if attachment.Text != "" {
+		lines = append(lines, slackToMarkdown(attachment.Text))
+	}
+	for _, field := range attachment.Fields {
+		lines = append(lines, "**"+field.Title+"**: "+slackToMarkdown(field.Value))
+	}
+
+	if len(lines) == 0 {
+		return attachment.Fallback
+	}
+	return strings.Join(lines, "\n")
+}
+
+// slackToMarkdown rewrites Slack's link and mention syntax and undoes its
+// HTML escaping. Other mrkdwn formatting is passed through.
+func slackToMarkdown(text string) string {
+	text = slackControl.ReplaceAllStringFunc(text, func(match string) string {
+		parts := slackControl.FindStringSubmatch(match)
+		target, label := parts[1], parts[2]
+
+		switch {
+		case strings.HasPrefix(target, "!"):
+			return "@" + strings.TrimPrefix(target, "!")
+		case strings.HasPrefix(target, "@"), strings.HasPrefix(target, "#"):
+			if label != "" {
+				return target[:1] + label
+			}
+			return target
+		case label != "":
+			return "[" + label + "](" + target + ")"
+		}
+		return target
+	})
+	return slackUnescaper.Replace(text)
+}
\ No newline at end of file
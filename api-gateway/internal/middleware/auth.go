@@ -43,6 +43,8 @@ func AuthMiddleware(authClient *clients.AuthClient) fiber.Handler {
 		// Set user info in context
 		c.Locals("userID", resp.UserId)
 		c.Locals("username", resp.Username)
+		c.Locals("orgID", resp.OrgId)
+		c.Locals("orgRole", resp.OrgRole)
 
 		return c.Next()
 	}
@@ -60,6 +62,8 @@ func OptionalAuthMiddleware(authClient *clients.AuthClient) fiber.Handler {
 				if err == nil && resp.Valid {
 					c.Locals("userID", resp.UserId)
 					c.Locals("username", resp.Username)
+					c.Locals("orgID", resp.OrgId)
+					c.Locals("orgRole", resp.OrgRole)
 				}
 			}
 		}
@@ -4,16 +4,30 @@ type RegisterRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=50"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	// OrganizationName names the workspace created for the user. Defaults to
+	// "<username>'s workspace".
+	OrganizationName string `json:"organization_name" validate:"max=100"`
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// OrgID is the organization to sign in to. Defaults to the user's first.
+	OrgID string `json:"org_id"`
 }
 
 type AuthResponse struct {
-	User  UserResponse `json:"user"`
-	Token string       `json:"token"`
+	User         UserResponse                   `json:"user"`
+	Token        string                         `json:"token"`
+	Organization OrganizationMembershipResponse `json:"organization"`
+}
+
+// ProfileResponse is the signed-in user along with the organization their
+// token is for.
+type ProfileResponse struct {
+	UserResponse
+	OrgID   string `json:"org_id"`
+	OrgRole string `json:"org_role"`
 }
 
 type UserResponse struct {
@@ -27,12 +41,52 @@ type UserResponse struct {
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	Reason  string `json:"reason,omitempty"`
 }
 
 type ValidateTokenResponse struct {
 	Valid    bool   `json:"valid"`
 	UserID   string `json:"user_id,omitempty"`
 	Username string `json:"username,omitempty"`
+	OrgID    string `json:"org_id,omitempty"`
+	OrgRole  string `json:"org_role,omitempty"`
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type AddOrganizationMemberRequest struct {
+	// Login is the email or username of the user to add.
+	Login string `json:"login" validate:"required"`
+	// Role defaults to "member".
+	Role string `json:"role" validate:"oneof='' owner admin member"`
+}
+
+type UpdateOrganizationMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type OrganizationResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// OrganizationMembershipResponse is one of the user's own organizations.
+type OrganizationMembershipResponse struct {
+	Organization OrganizationResponse `json:"organization"`
+	Role         string               `json:"role"`
+	JoinedAt     string               `json:"joined_at"`
+}
+
+// OrganizationMemberResponse is a user in an organization's directory.
+type OrganizationMemberResponse struct {
+	User     UserResponse `json:"user"`
+	Role     string       `json:"role"`
+	JoinedAt string       `json:"joined_at"`
 }
 
 type CreateRoomRequest struct {
@@ -40,9 +94,34 @@ type CreateRoomRequest struct {
 	Description string `json:"description" validate:"max=500"`
 }
 
+// UpdateRoomRequest only changes the fields that are present in the body.
+type UpdateRoomRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+	Archived    bool    `json:"archived"`
+}
+
 type SendMessageRequest struct {
-	RoomID  string `json:"room_id" validate:"required"`
-	Content string `json:"content" validate:"required,min=1,max=1000"`
+	RoomID          string `json:"room_id" validate:"required"`
+	Content         string `json:"content" validate:"required,min=1,max=1000"`
+	ClientMessageID string `json:"client_message_id" validate:"max=64"`
+	// ScheduledAt is an optional RFC 3339 time to send the message at.
+	ScheduledAt string `json:"scheduled_at"`
+	// QuoteMessageID is an optional message to quote in the reply.
+	QuoteMessageID string `json:"quote_message_id"`
+	// TTLSeconds optionally makes the message disappear after that long.
+	TTLSeconds int32 `json:"ttl_seconds" validate:"min=0,max=2592000"`
+}
+
+type ForwardMessageRequest struct {
+	// RoomID is the room to forward the message to.
+	RoomID          string `json:"room_id" validate:"required"`
+	ClientMessageID string `json:"client_message_id" validate:"max=64"`
+}
+
+type UpdateScheduledMessageRequest struct {
+	Content     *string `json:"content" validate:"omitempty,min=1,max=1000"`
+	ScheduledAt *string `json:"scheduled_at"`
 }
 
 type RoomResponse struct {
@@ -55,23 +134,275 @@ type RoomResponse struct {
 }
 
 type MessageResponse struct {
-	ID        string `json:"id"`
+	ID              string          `json:"id"`
+	RoomID          string          `json:"room_id"`
+	Seq             int64           `json:"seq"`
+	Type            string          `json:"type"`
+	UserID          string          `json:"user_id"`
+	Username        string          `json:"username"`
+	Content         string          `json:"content"`
+	PlainText       string          `json:"plain_text"`
+	Entities        []MessageEntity `json:"entities"`
+	ClientMessageID string          `json:"client_message_id,omitempty"`
+	CreatedAt       string          `json:"created_at"`
+	// Reference is set on forwarded messages and quoted replies.
+	Reference *MessageReference `json:"reference,omitempty"`
+	// ExpiresAt is set on disappearing messages.
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+type MessageReference struct {
+	Type      string `json:"type"`
+	MessageID string `json:"message_id"`
 	RoomID    string `json:"room_id"`
 	UserID    string `json:"user_id"`
 	Username  string `json:"username"`
-	Content   string `json:"content"`
 	CreatedAt string `json:"created_at"`
+	// Content is empty once the original is deleted and Available is false.
+	Content   string `json:"content,omitempty"`
+	Available bool   `json:"available"`
+}
+
+type MessageEntity struct {
+	Type     string `json:"type"`
+	Offset   int32  `json:"offset"`
+	Length   int32  `json:"length"`
+	URL      string `json:"url,omitempty"`
+	Language string `json:"language,omitempty"`
 }
 
 type JoinRoomRequest struct {
 	RoomID string `json:"room_id" validate:"required"`
 }
 
+type RoomMemberResponse struct {
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	JoinedAt   string `json:"joined_at"`
+	Online     bool   `json:"online"`
+	MutedUntil string `json:"muted_until,omitempty"`
+}
+
+type RoomMembersResponse struct {
+	Members     []RoomMemberResponse `json:"members"`
+	NextCursor  string               `json:"next_cursor,omitempty"`
+	OnlineCount int32                `json:"online_count"`
+}
+
+type CreateExportRequest struct {
+	// Format is "jsonl", "csv" or "html".
+	Format string `json:"format" validate:"required,oneof=jsonl csv html"`
+}
+
+type ExportResponse struct {
+	ID               string `json:"id"`
+	RoomID           string `json:"room_id"`
+	RequestedBy      string `json:"requested_by"`
+	Format           string `json:"format"`
+	Status           string `json:"status"`
+	TotalMessages    int64  `json:"total_messages"`
+	ExportedMessages int64  `json:"exported_messages"`
+	FileName         string `json:"file_name,omitempty"`
+	FileSize         int64  `json:"file_size,omitempty"`
+	Error            string `json:"error,omitempty"`
+	CreatedAt        string `json:"created_at"`
+	StartedAt        string `json:"started_at,omitempty"`
+	CompletedAt      string `json:"completed_at,omitempty"`
+	ExpiresAt        string `json:"expires_at,omitempty"`
+	// DownloadURL is set once the export has completed.
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+type RoomNotificationsRequest struct {
+	// NotifyLevel is "all", "mentions" or "none".
+	NotifyLevel string `json:"notify_level" validate:"required,oneof=all mentions none"`
+	// MutedUntil silences the room until then (RFC 3339); empty unmutes it.
+	MutedUntil string `json:"muted_until"`
+}
+
+type RoomNotificationsResponse struct {
+	RoomID      string `json:"room_id"`
+	NotifyLevel string `json:"notify_level"`
+	MutedUntil  string `json:"muted_until,omitempty"`
+}
+
+// DNDSchedule is a do-not-disturb window, as times of day in TimeZone.
+type DNDSchedule struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"time_zone"`
+}
+
+type EmailDigestsRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type RegisterPushDeviceRequest struct {
+	// Platform is "fcm" or "apns".
+	Platform string `json:"platform" validate:"required,oneof=fcm apns"`
+	Token    string `json:"token" validate:"required,max=4096"`
+}
+
+type PushDeviceResponse struct {
+	ID         string `json:"id"`
+	Platform   string `json:"platform"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+	LastPushAt string `json:"last_push_at,omitempty"`
+}
+
+type BookmarkMessageRequest struct {
+	Folder string `json:"folder" validate:"max=64"`
+	Note   string `json:"note" validate:"max=1000"`
+}
+
+type BookmarkResponse struct {
+	ID        string `json:"id"`
+	MessageID string `json:"message_id"`
+	RoomID    string `json:"room_id"`
+	RoomName  string `json:"room_name"`
+	Folder    string `json:"folder"`
+	Note      string `json:"note"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	// Available is false, and Message nil, once the message is deleted or
+	// the user can no longer read its room.
+	Available bool             `json:"available"`
+	Message   *MessageResponse `json:"message,omitempty"`
+}
+
+type BookmarkFolder struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type BookmarksResponse struct {
+	Bookmarks  []BookmarkResponse `json:"bookmarks"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	Folders    []BookmarkFolder   `json:"folders"`
+}
+
 type SendMessageResponse struct {
-	Message MessageResponse `json:"message"`
+	Message   MessageResponse `json:"message"`
+	Duplicate bool            `json:"duplicate,omitempty"`
 }
 
 type JoinRoomResponse struct {
 	Success bool   `json:"success"`
 	RoomID  string `json:"room_id"`
+}
+
+type SetMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=moderator member"`
+}
+
+type PinMessageRequest struct {
+	MessageID string `json:"message_id" validate:"required"`
+}
+
+// ModerationFilter mirrors the chat-service filter config; only the fields
+// relevant to Type are used.
+type ModerationFilter struct {
+	Type           string   `json:"type"`
+	Action         string   `json:"action"`
+	Words          []string `json:"words,omitempty"`
+	Patterns       []string `json:"patterns,omitempty"`
+	MaxLinks       int32    `json:"max_links,omitempty"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+	MaxRepeat      int32    `json:"max_repeat,omitempty"`
+	MaxCapsRatio   float64  `json:"max_caps_ratio,omitempty"`
+	MinLetters     int32    `json:"min_letters,omitempty"`
+}
+
+type SetRoomModerationRequest struct {
+	Filters    []ModerationFilter `json:"filters"`
+	UseDefault bool               `json:"use_default"`
+}
+
+type ReportMessageRequest struct {
+	MessageID string `json:"message_id" validate:"required"`
+	Reason    string `json:"reason" validate:"required,max=100"`
+	Detail    string `json:"detail" validate:"max=1000"`
+}
+
+type ResolveModerationItemRequest struct {
+	Action      string `json:"action" validate:"required,oneof=dismiss delete_message mute ban"`
+	Note        string `json:"note"`
+	MuteSeconds int64  `json:"mute_seconds"`
+}
+
+type CreatePollRequest struct {
+	Question       string   `json:"question" validate:"required,max=300"`
+	Options        []string `json:"options" validate:"min=2,max=10"`
+	MultipleChoice bool     `json:"multiple_choice"`
+	Anonymous      bool     `json:"anonymous"`
+	// ClosesAt is an optional RFC 3339 time to stop voting at.
+	ClosesAt string `json:"closes_at"`
+}
+
+type VotePollRequest struct {
+	OptionIDs []string `json:"option_ids"`
+}
+
+type SetSlowModeRequest struct {
+	Seconds int32 `json:"seconds" validate:"min=0,max=21600"`
+}
+
+type SetMessageTTLRequest struct {
+	Seconds int32 `json:"seconds" validate:"min=0,max=2592000"`
+}
+
+type SetRoomRetentionRequest struct {
+	Type  string `json:"type" validate:"oneof='' forever days messages"`
+	Value int32  `json:"value"`
+}
+
+type EditMessageRequest struct {
+	Content string `json:"content" validate:"required,min=1,max=1000"`
+}
+
+type CreateWebhookRequest struct {
+	// RoomID is optional for admins, whose webhooks then cover every room.
+	RoomID string `json:"room_id"`
+	URL    string `json:"url" validate:"required,url"`
+	// Secret is generated when empty.
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+type CreateIncomingWebhookRequest struct {
+	Name string `json:"name" validate:"required,max=80"`
+}
+
+// IncomingWebhookPayload is the body posted to an incoming webhook. Content
+// is the native field; Text and Attachments accept Slack-compatible bodies.
+type IncomingWebhookPayload struct {
+	Content         string            `json:"content"`
+	Text            string            `json:"text"`
+	Attachments     []SlackAttachment `json:"attachments"`
+	ClientMessageID string            `json:"client_message_id"`
+}
+
+type SlackAttachment struct {
+	Fallback  string       `json:"fallback"`
+	Pretext   string       `json:"pretext"`
+	Title     string       `json:"title"`
+	TitleLink string       `json:"title_link"`
+	Text      string       `json:"text"`
+	Fields    []SlackField `json:"fields"`
+}
+
+type SlackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
 }
\ No newline at end of file
@@ -43,19 +43,21 @@ func (c *AuthClient) Close() error {
 	return c.conn.Close()
 }
 
-func (c *AuthClient) Register(ctx context.Context, username, email, password string) (*proto.RegisterResponse, error) {
+func (c *AuthClient) Register(ctx context.Context, username, email, password, orgName string) (*proto.RegisterResponse, error) {
 	req := &proto.RegisterRequest{
-		Username: username,
-		Email:    email,
-		Password: password,
+		Username:         username,
+		Email:            email,
+		Password:         password,
+		OrganizationName: orgName,
 	}
 	return c.service.Register(ctx, req)
 }
 
-func (c *AuthClient) Login(ctx context.Context, email, password string) (*proto.LoginResponse, error) {
+func (c *AuthClient) Login(ctx context.Context, email, password, orgID string) (*proto.LoginResponse, error) {
 	req := &proto.LoginRequest{
 		Email:    email,
 		Password: password,
+		OrgId:    orgID,
 	}
 	return c.service.Login(ctx, req)
 }
@@ -67,9 +69,76 @@ func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*proto.Va
 	return c.service.ValidateToken(ctx, req)
 }
 
+func (c *AuthClient) ValidateUnsubscribeToken(ctx context.Context, token string) (*proto.ValidateUnsubscribeTokenResponse, error) {
+	req := &proto.ValidateUnsubscribeTokenRequest{
+		Token: token,
+	}
+	return c.service.ValidateUnsubscribeToken(ctx, req)
+}
+
 func (c *AuthClient) GetUser(ctx context.Context, userID string) (*proto.GetUserResponse, error) {
 	req := &proto.GetUserRequest{
 		UserId: userID,
 	}
 	return c.service.GetUser(ctx, req)
+}
+
+func (c *AuthClient) SwitchOrganization(ctx context.Context, userID, orgID string) (*proto.SwitchOrganizationResponse, error) {
+	req := &proto.SwitchOrganizationRequest{
+		UserId: userID,
+		OrgId:  orgID,
+	}
+	return c.service.SwitchOrganization(ctx, req)
+}
+
+func (c *AuthClient) CreateOrganization(ctx context.Context, userID, name string) (*proto.CreateOrganizationResponse, error) {
+	req := &proto.CreateOrganizationRequest{
+		UserId: userID,
+		Name:   name,
+	}
+	return c.service.CreateOrganization(ctx, req)
+}
+
+func (c *AuthClient) ListOrganizations(ctx context.Context, userID string) (*proto.ListOrganizationsResponse, error) {
+	req := &proto.ListOrganizationsRequest{
+		UserId: userID,
+	}
+	return c.service.ListOrganizations(ctx, req)
+}
+
+func (c *AuthClient) ListOrganizationMembers(ctx context.Context, orgID, userID string) (*proto.ListOrganizationMembersResponse, error) {
+	req := &proto.ListOrganizationMembersRequest{
+		OrgId:  orgID,
+		UserId: userID,
+	}
+	return c.service.ListOrganizationMembers(ctx, req)
+}
+
+func (c *AuthClient) AddOrganizationMember(ctx context.Context, orgID, actorID, login, role string) (*proto.AddOrganizationMemberResponse, error) {
+	req := &proto.AddOrganizationMemberRequest{
+		OrgId:   orgID,
+		ActorId: actorID,
+		Login:   login,
+		Role:    role,
+	}
+	return c.service.AddOrganizationMember(ctx, req)
+}
+
+func (c *AuthClient) UpdateOrganizationMemberRole(ctx context.Context, orgID, actorID, userID, role string) (*proto.UpdateOrganizationMemberRoleResponse, error) {
+	req := &proto.UpdateOrganizationMemberRoleRequest{
+		OrgId:   orgID,
+		ActorId: actorID,
+		UserId:  userID,
+		Role:    role,
+	}
+	return c.service.UpdateOrganizationMemberRole(ctx, req)
+}
+
+func (c *AuthClient) RemoveOrganizationMember(ctx context.Context, orgID, actorID, userID string) (*proto.RemoveOrganizationMemberResponse, error) {
+	req := &proto.RemoveOrganizationMemberRequest{
+		OrgId:   orgID,
+		ActorId: actorID,
+		UserId:  userID,
+	}
+	return c.service.RemoveOrganizationMember(ctx, req)
 }
\ No newline at end of file
@@ -52,11 +52,32 @@ func (c *ChatClient) CreateRoom(ctx context.Context, name, description, userID s
 	return c.service.CreateRoom(ctx, req)
 }
 
-func (c *ChatClient) GetRooms(ctx context.Context) (*proto.GetRoomsResponse, error) {
-	req := &proto.GetRoomsRequest{}
+func (c *ChatClient) GetRooms(ctx context.Context, includePinnedCount bool) (*proto.GetRoomsResponse, error) {
+	req := &proto.GetRoomsRequest{
+		IncludePinnedCount: includePinnedCount,
+	}
 	return c.service.GetRooms(ctx, req)
 }
 
+func (c *ChatClient) UpdateRoom(ctx context.Context, roomID, userID string, name, description *string, archived bool) (*proto.UpdateRoomResponse, error) {
+	req := &proto.UpdateRoomRequest{
+		RoomId:      roomID,
+		UserId:      userID,
+		Name:        name,
+		Description: description,
+		Archived:    archived,
+	}
+	return c.service.UpdateRoom(ctx, req)
+}
+
+func (c *ChatClient) DeleteRoom(ctx context.Context, roomID, userID string) (*proto.DeleteRoomResponse, error) {
+	req := &proto.DeleteRoomRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+	return c.service.DeleteRoom(ctx, req)
+}
+
 func (c *ChatClient) JoinRoom(ctx context.Context, roomID, userID string) (*proto.JoinRoomResponse, error) {
 	req := &proto.JoinRoomRequest{
 		RoomId: roomID,
@@ -65,19 +86,457 @@ func (c *ChatClient) JoinRoom(ctx context.Context, roomID, userID string) (*prot
 	return c.service.JoinRoom(ctx, req)
 }
 
-func (c *ChatClient) SendMessage(ctx context.Context, roomID, userID, content string) (*proto.SendMessageResponse, error) {
+func (c *ChatClient) SendMessage(ctx context.Context, roomID, userID, content, clientMessageID, scheduledAt, quoteMessageID string, ttlSeconds int32) (*proto.SendMessageResponse, error) {
 	req := &proto.SendMessageRequest{
-		RoomId:  roomID,
-		UserId:  userID,
-		Content: content,
+		RoomId:          roomID,
+		UserId:          userID,
+		Content:         content,
+		ClientMessageId: clientMessageID,
+		ScheduledAt:     scheduledAt,
+		QuoteMessageId:  quoteMessageID,
+		TtlSeconds:      ttlSeconds,
 	}
 	return c.service.SendMessage(ctx, req)
 }
 
-func (c *ChatClient) GetRoomMessages(ctx context.Context, roomID string, limit int32) (*proto.GetRoomMessagesResponse, error) {
+func (c *ChatClient) ForwardMessage(ctx context.Context, messageID, targetRoomID, userID, clientMessageID string) (*proto.ForwardMessageResponse, error) {
+	req := &proto.ForwardMessageRequest{
+		MessageId:       messageID,
+		TargetRoomId:    targetRoomID,
+		UserId:          userID,
+		ClientMessageId: clientMessageID,
+	}
+	return c.service.ForwardMessage(ctx, req)
+}
+
+func (c *ChatClient) GetRoomMessages(ctx context.Context, roomID, userID string, limit int32) (*proto.GetRoomMessagesResponse, error) {
 	req := &proto.GetRoomMessagesRequest{
 		RoomId: roomID,
 		Limit:  limit,
+		UserId: userID,
 	}
 	return c.service.GetRoomMessages(ctx, req)
+}
+
+func (c *ChatClient) GetMessagesSince(ctx context.Context, roomID, userID string, seq int64, limit int32) (*proto.GetMessagesSinceResponse, error) {
+	req := &proto.GetMessagesSinceRequest{
+		RoomId: roomID,
+		Seq:    seq,
+		Limit:  limit,
+		UserId: userID,
+	}
+	return c.service.GetMessagesSince(ctx, req)
+}
+
+func (c *ChatClient) SetMemberRole(ctx context.Context, roomID, userID, targetUserID, role string) (*proto.SetMemberRoleResponse, error) {
+	req := &proto.SetMemberRoleRequest{
+		RoomId:       roomID,
+		UserId:       userID,
+		TargetUserId: targetUserID,
+		Role:         role,
+	}
+	return c.service.SetMemberRole(ctx, req)
+}
+
+func (c *ChatClient) PinMessage(ctx context.Context, roomID, messageID, userID string) (*proto.PinMessageResponse, error) {
+	req := &proto.PinMessageRequest{
+		RoomId:    roomID,
+		MessageId: messageID,
+		UserId:    userID,
+	}
+	return c.service.PinMessage(ctx, req)
+}
+
+func (c *ChatClient) UnpinMessage(ctx context.Context, roomID, messageID, userID string) (*proto.UnpinMessageResponse, error) {
+	req := &proto.UnpinMessageRequest{
+		RoomId:    roomID,
+		MessageId: messageID,
+		UserId:    userID,
+	}
+	return c.service.UnpinMessage(ctx, req)
+}
+
+func (c *ChatClient) GetRoomMembers(ctx context.Context, roomID, userID, cursor string, limit int32) (*proto.GetRoomMembersResponse, error) {
+	req := &proto.GetRoomMembersRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Cursor: cursor,
+		Limit:  limit,
+	}
+	return c.service.GetRoomMembers(ctx, req)
+}
+
+func (c *ChatClient) ListPinnedMessages(ctx context.Context, roomID, userID string) (*proto.ListPinnedMessagesResponse, error) {
+	req := &proto.ListPinnedMessagesRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+	return c.service.ListPinnedMessages(ctx, req)
+}
+
+func (c *ChatClient) SetRoomRetention(ctx context.Context, roomID, userID, retentionType string, value int32) (*proto.SetRoomRetentionResponse, error) {
+	req := &proto.SetRoomRetentionRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Retention: &proto.RetentionPolicy{
+			Type:  retentionType,
+			Value: value,
+		},
+	}
+	return c.service.SetRoomRetention(ctx, req)
+}
+
+func (c *ChatClient) GetPurgeRecords(ctx context.Context, roomID, userID string, limit int32) (*proto.GetPurgeRecordsResponse, error) {
+	req := &proto.GetPurgeRecordsRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Limit:  limit,
+	}
+	return c.service.GetPurgeRecords(ctx, req)
+}
+
+func (c *ChatClient) SetRoomModeration(ctx context.Context, roomID, userID string, filters []*proto.ModerationFilter, useDefault bool) (*proto.SetRoomModerationResponse, error) {
+	req := &proto.SetRoomModerationRequest{
+		RoomId:     roomID,
+		UserId:     userID,
+		Filters:    filters,
+		UseDefault: useDefault,
+	}
+	return c.service.SetRoomModeration(ctx, req)
+}
+
+func (c *ChatClient) GetRoomModeration(ctx context.Context, roomID, userID string) (*proto.GetRoomModerationResponse, error) {
+	req := &proto.GetRoomModerationRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+	return c.service.GetRoomModeration(ctx, req)
+}
+
+func (c *ChatClient) ListModerationQueue(ctx context.Context, roomID, userID, status string, limit int32) (*proto.ListModerationQueueResponse, error) {
+	req := &proto.ListModerationQueueRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Status: status,
+		Limit:  limit,
+	}
+	return c.service.ListModerationQueue(ctx, req)
+}
+
+func (c *ChatClient) SetSlowMode(ctx context.Context, roomID, userID string, seconds int32) (*proto.SetSlowModeResponse, error) {
+	req := &proto.SetSlowModeRequest{
+		RoomId:  roomID,
+		UserId:  userID,
+		Seconds: seconds,
+	}
+	return c.service.SetSlowMode(ctx, req)
+}
+
+func (c *ChatClient) SetMessageTTL(ctx context.Context, roomID, userID string, seconds int32) (*proto.SetMessageTTLResponse, error) {
+	req := &proto.SetMessageTTLRequest{
+		RoomId:  roomID,
+		UserId:  userID,
+		Seconds: seconds,
+	}
+	return c.service.SetMessageTTL(ctx, req)
+}
+
+func (c *ChatClient) ReportMessage(ctx context.Context, messageID, userID, reason, detail string) (*proto.ReportMessageResponse, error) {
+	req := &proto.ReportMessageRequest{
+		MessageId: messageID,
+		UserId:    userID,
+		Reason:    reason,
+		Detail:    detail,
+	}
+	return c.service.ReportMessage(ctx, req)
+}
+
+func (c *ChatClient) ClaimModerationItem(ctx context.Context, itemID, userID string) (*proto.ClaimModerationItemResponse, error) {
+	req := &proto.ClaimModerationItemRequest{
+		ItemId: itemID,
+		UserId: userID,
+	}
+	return c.service.ClaimModerationItem(ctx, req)
+}
+
+func (c *ChatClient) ResolveModerationItem(ctx context.Context, itemID, userID, action, note string, muteSeconds int64) (*proto.ResolveModerationItemResponse, error) {
+	req := &proto.ResolveModerationItemRequest{
+		ItemId:      itemID,
+		UserId:      userID,
+		Action:      action,
+		Note:        note,
+		MuteSeconds: muteSeconds,
+	}
+	return c.service.ResolveModerationItem(ctx, req)
+}
+
+func (c *ChatClient) ListModerationActions(ctx context.Context, roomID, userID string, limit int32) (*proto.ListModerationActionsResponse, error) {
+	req := &proto.ListModerationActionsRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Limit:  limit,
+	}
+	return c.service.ListModerationActions(ctx, req)
+}
+
+func (c *ChatClient) ListScheduledMessages(ctx context.Context, userID, roomID, status string) (*proto.ListScheduledMessagesResponse, error) {
+	req := &proto.ListScheduledMessagesRequest{
+		UserId: userID,
+		RoomId: roomID,
+		Status: status,
+	}
+	return c.service.ListScheduledMessages(ctx, req)
+}
+
+func (c *ChatClient) UpdateScheduledMessage(ctx context.Context, id, userID string, content, scheduledAt *string) (*proto.UpdateScheduledMessageResponse, error) {
+	req := &proto.UpdateScheduledMessageRequest{
+		Id:          id,
+		UserId:      userID,
+		Content:     content,
+		ScheduledAt: scheduledAt,
+	}
+	return c.service.UpdateScheduledMessage(ctx, req)
+}
+
+func (c *ChatClient) CancelScheduledMessage(ctx context.Context, id, userID string) (*proto.CancelScheduledMessageResponse, error) {
+	req := &proto.CancelScheduledMessageRequest{
+		Id:     id,
+		UserId: userID,
+	}
+	return c.service.CancelScheduledMessage(ctx, req)
+}
+
+func (c *ChatClient) CreatePoll(ctx context.Context, req *proto.CreatePollRequest) (*proto.CreatePollResponse, error) {
+	return c.service.CreatePoll(ctx, req)
+}
+
+func (c *ChatClient) VotePoll(ctx context.Context, pollID, userID string, optionIDs []string) (*proto.VotePollResponse, error) {
+	req := &proto.VotePollRequest{
+		PollId:    pollID,
+		UserId:    userID,
+		OptionIds: optionIDs,
+	}
+	return c.service.VotePoll(ctx, req)
+}
+
+func (c *ChatClient) ClosePoll(ctx context.Context, pollID, userID string) (*proto.ClosePollResponse, error) {
+	req := &proto.ClosePollRequest{
+		PollId: pollID,
+		UserId: userID,
+	}
+	return c.service.ClosePoll(ctx, req)
+}
+
+func (c *ChatClient) EditMessage(ctx context.Context, messageID, userID, content string) (*proto.EditMessageResponse, error) {
+	req := &proto.EditMessageRequest{
+		MessageId: messageID,
+		UserId:    userID,
+		Content:   content,
+	}
+	return c.service.EditMessage(ctx, req)
+}
+
+func (c *ChatClient) DeleteMessage(ctx context.Context, messageID, userID string) (*proto.DeleteMessageResponse, error) {
+	req := &proto.DeleteMessageRequest{
+		MessageId: messageID,
+		UserId:    userID,
+	}
+	return c.service.DeleteMessage(ctx, req)
+}
+
+func (c *ChatClient) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.CreateWebhookResponse, error) {
+	return c.service.CreateWebhook(ctx, req)
+}
+
+func (c *ChatClient) ListWebhooks(ctx context.Context, roomID, userID string) (*proto.ListWebhooksResponse, error) {
+	req := &proto.ListWebhooksRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+	return c.service.ListWebhooks(ctx, req)
+}
+
+func (c *ChatClient) UpdateWebhook(ctx context.Context, req *proto.UpdateWebhookRequest) (*proto.UpdateWebhookResponse, error) {
+	return c.service.UpdateWebhook(ctx, req)
+}
+
+func (c *ChatClient) DeleteWebhook(ctx context.Context, webhookID, userID string) (*proto.DeleteWebhookResponse, error) {
+	req := &proto.DeleteWebhookRequest{
+		WebhookId: webhookID,
+		UserId:    userID,
+	}
+	return c.service.DeleteWebhook(ctx, req)
+}
+
+func (c *ChatClient) ListWebhookDeliveries(ctx context.Context, webhookID, userID, status string, limit int32) (*proto.ListWebhookDeliveriesResponse, error) {
+	req := &proto.ListWebhookDeliveriesRequest{
+		WebhookId: webhookID,
+		UserId:    userID,
+		Status:    status,
+		Limit:     limit,
+	}
+	return c.service.ListWebhookDeliveries(ctx, req)
+}
+
+func (c *ChatClient) CreateIncomingWebhook(ctx context.Context, roomID, userID, name string) (*proto.CreateIncomingWebhookResponse, error) {
+	req := &proto.CreateIncomingWebhookRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Name:   name,
+	}
+	return c.service.CreateIncomingWebhook(ctx, req)
+}
+
+func (c *ChatClient) ListIncomingWebhooks(ctx context.Context, roomID, userID string) (*proto.ListIncomingWebhooksResponse, error) {
+	req := &proto.ListIncomingWebhooksRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+	return c.service.ListIncomingWebhooks(ctx, req)
+}
+
+func (c *ChatClient) RevokeIncomingWebhook(ctx context.Context, id, userID string) (*proto.RevokeIncomingWebhookResponse, error) {
+	req := &proto.RevokeIncomingWebhookRequest{
+		Id:     id,
+		UserId: userID,
+	}
+	return c.service.RevokeIncomingWebhook(ctx, req)
+}
+
+func (c *ChatClient) PostIncomingWebhook(ctx context.Context, token, content, clientMessageID string) (*proto.PostIncomingWebhookResponse, error) {
+	req := &proto.PostIncomingWebhookRequest{
+		Token:           token,
+		Content:         content,
+		ClientMessageId: clientMessageID,
+	}
+	return c.service.PostIncomingWebhook(ctx, req)
+}
+
+func (c *ChatClient) CreateExport(ctx context.Context, roomID, userID, format string) (*proto.CreateExportResponse, error) {
+	req := &proto.CreateExportRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Format: format,
+	}
+	return c.service.CreateExport(ctx, req)
+}
+
+func (c *ChatClient) GetExport(ctx context.Context, exportID, userID string) (*proto.GetExportResponse, error) {
+	req := &proto.GetExportRequest{
+		ExportId: exportID,
+		UserId:   userID,
+	}
+	return c.service.GetExport(ctx, req)
+}
+
+func (c *ChatClient) ListExports(ctx context.Context, roomID, userID string, limit int32) (*proto.ListExportsResponse, error) {
+	req := &proto.ListExportsRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Limit:  limit,
+	}
+	return c.service.ListExports(ctx, req)
+}
+
+// DownloadExport opens the stream of an export's file. ctx must stay alive
+// until the stream is read to the end.
+func (c *ChatClient) DownloadExport(ctx context.Context, exportID, userID string) (proto.ChatService_DownloadExportClient, error) {
+	req := &proto.DownloadExportRequest{
+		ExportId: exportID,
+		UserId:   userID,
+	}
+	return c.service.DownloadExport(ctx, req)
+}
+
+func (c *ChatClient) BookmarkMessage(ctx context.Context, messageID, userID, folder, note string) (*proto.BookmarkMessageResponse, error) {
+	req := &proto.BookmarkMessageRequest{
+		MessageId: messageID,
+		UserId:    userID,
+		Folder:    folder,
+		Note:      note,
+	}
+	return c.service.BookmarkMessage(ctx, req)
+}
+
+func (c *ChatClient) RemoveBookmark(ctx context.Context, messageID, userID string) (*proto.RemoveBookmarkResponse, error) {
+	req := &proto.RemoveBookmarkRequest{
+		MessageId: messageID,
+		UserId:    userID,
+	}
+	return c.service.RemoveBookmark(ctx, req)
+}
+
+func (c *ChatClient) ListBookmarks(ctx context.Context, userID, folder, cursor string, limit int32) (*proto.ListBookmarksResponse, error) {
+	req := &proto.ListBookmarksRequest{
+		UserId: userID,
+		Folder: folder,
+		Cursor: cursor,
+		Limit:  limit,
+	}
+	return c.service.ListBookmarks(ctx, req)
+}
+
+func (c *ChatClient) GetRoomNotifications(ctx context.Context, roomID, userID string) (*proto.GetRoomNotificationsResponse, error) {
+	req := &proto.GetRoomNotificationsRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+	return c.service.GetRoomNotifications(ctx, req)
+}
+
+func (c *ChatClient) SetRoomNotifications(ctx context.Context, roomID, userID, notifyLevel, mutedUntil string) (*proto.SetRoomNotificationsResponse, error) {
+	req := &proto.SetRoomNotificationsRequest{
+		RoomId:      roomID,
+		UserId:      userID,
+		NotifyLevel: notifyLevel,
+		MutedUntil:  mutedUntil,
+	}
+	return c.service.SetRoomNotifications(ctx, req)
+}
+
+func (c *ChatClient) GetNotificationSettings(ctx context.Context, userID string) (*proto.GetNotificationSettingsResponse, error) {
+	req := &proto.GetNotificationSettingsRequest{
+		UserId: userID,
+	}
+	return c.service.GetNotificationSettings(ctx, req)
+}
+
+func (c *ChatClient) SetDNDSchedule(ctx context.Context, userID string, dnd *proto.DNDSchedule) (*proto.SetDNDScheduleResponse, error) {
+	req := &proto.SetDNDScheduleRequest{
+		UserId: userID,
+		Dnd:    dnd,
+	}
+	return c.service.SetDNDSchedule(ctx, req)
+}
+
+func (c *ChatClient) SetEmailDigests(ctx context.Context, userID string, enabled bool) (*proto.SetEmailDigestsResponse, error) {
+	req := &proto.SetEmailDigestsRequest{
+		UserId:  userID,
+		Enabled: enabled,
+	}
+	return c.service.SetEmailDigests(ctx, req)
+}
+
+func (c *ChatClient) RegisterPushDevice(ctx context.Context, userID, platform, token string) (*proto.RegisterPushDeviceResponse, error) {
+	req := &proto.RegisterPushDeviceRequest{
+		UserId:   userID,
+		Platform: platform,
+		Token:    token,
+	}
+	return c.service.RegisterPushDevice(ctx, req)
+}
+
+func (c *ChatClient) ListPushDevices(ctx context.Context, userID string) (*proto.ListPushDevicesResponse, error) {
+	req := &proto.ListPushDevicesRequest{
+		UserId: userID,
+	}
+	return c.service.ListPushDevices(ctx, req)
+}
+
+func (c *ChatClient) UnregisterPushDevice(ctx context.Context, userID, deviceID string) (*proto.UnregisterPushDeviceResponse, error) {
+	req := &proto.UnregisterPushDeviceRequest{
+		UserId:   userID,
+		DeviceId: deviceID,
+	}
+	return c.service.UnregisterPushDevice(ctx, req)
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 
+	"auth-service/internal/models"
 	"auth-service/internal/proto"
 	"auth-service/internal/service"
 	"google.golang.org/grpc/codes"
@@ -21,49 +22,42 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 }
 
 func (h *AuthHandler) Register(ctx context.Context, req *proto.RegisterRequest) (*proto.RegisterResponse, error) {
-	user, token, err := h.authService.Register(req.Username, req.Email, req.Password)
+	session, err := h.authService.Register(req.Username, req.Email, req.Password, req.OrganizationName)
 	if err != nil {
 		if err == service.ErrUserAlreadyExists {
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
+		if err == service.ErrInvalidOrgName {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	return &proto.RegisterResponse{
-		User: &proto.User{
-			Id:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
-		},
-		Token: token,
+		User:       toProtoUser(session.User),
+		Token:      session.Token,
+		Membership: toProtoMembership(session.Member),
 	}, nil
 }
 
 func (h *AuthHandler) Login(ctx context.Context, req *proto.LoginRequest) (*proto.LoginResponse, error) {
-	user, token, err := h.authService.Login(req.Email, req.Password)
+	session, err := h.authService.Login(req.Email, req.Password, req.OrgId)
 	if err != nil {
 		if err == service.ErrInvalidCredentials {
 			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
 		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toOrgStatusError(err)
 	}
 
 	return &proto.LoginResponse{
-		User: &proto.User{
-			Id:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
-		},
-		Token: token,
+		User:       toProtoUser(session.User),
+		Token:      session.Token,
+		Membership: toProtoMembership(session.Member),
 	}, nil
 }
 
 func (h *AuthHandler) ValidateToken(ctx context.Context, req *proto.ValidateTokenRequest) (*proto.ValidateTokenResponse, error) {
-	userID, username, err := h.authService.ValidateToken(req.Token)
+	identity, err := h.authService.ValidateToken(req.Token)
 	if err != nil {
 		return &proto.ValidateTokenResponse{
 			Valid:    false,
@@ -74,8 +68,10 @@ func (h *AuthHandler) ValidateToken(ctx context.Context, req *proto.ValidateToke
 
 	return &proto.ValidateTokenResponse{
 		Valid:    true,
-		UserId:   userID,
-		Username: username,
+		UserId:   identity.UserID,
+		Username: identity.Username,
+		OrgId:    identity.OrgID,
+		OrgRole:  identity.OrgRole,
 	}, nil
 }
 
@@ -97,4 +93,99 @@ func (h *AuthHandler) GetUser(ctx context.Context, req *proto.GetUserRequest) (*
 			UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
 		},
 	}, nil
+}
+
+func (h *AuthHandler) GetUsers(ctx context.Context, req *proto.GetUsersRequest) (*proto.GetUsersResponse, error) {
+	users, err := h.authService.GetUsers(req.UserIds)
+	if err != nil {
+		if err == service.ErrTooManyUsers {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	protoUsers := make([]*proto.User, 0, len(users))
+	for _, user := range users {
+		protoUsers = append(protoUsers, &proto.User{
+			Id:        user.ID,
+			Username:  user.Username,
+			Email:     user.Email,
+			CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &proto.GetUsersResponse{
+		Users: protoUsers,
+	}, nil
+}
+
+func (h *AuthHandler) ImportUsers(ctx context.Context, req *proto.ImportUsersRequest) (*proto.ImportUsersResponse, error) {
+	users := make([]*models.User, 0, len(req.Users))
+	for _, user := range req.Users {
+		if user.Username == "" {
+			return nil, status.Error(codes.InvalidArgument, "username is required")
+		}
+		users = append(users, &models.User{
+			Username: user.Username,
+			Email:    user.Email,
+		})
+	}
+
+	results, err := h.authService.ImportUsers(req.OrgId, users, req.DryRun)
+	if err != nil {
+		if err == service.ErrTooManyUsers {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, toOrgStatusError(err)
+	}
+
+	protoUsers := make([]*proto.ImportedUser, 0, len(results))
+	for _, result := range results {
+		imported := &proto.ImportedUser{Created: result.Created}
+		if result.User != nil {
+			imported.User = &proto.User{
+				Id:        result.User.ID,
+				Username:  result.User.Username,
+				Email:     result.User.Email,
+				CreatedAt: result.User.CreatedAt.Format("2006-01-02 15:04:05"),
+				UpdatedAt: result.User.UpdatedAt.Format("2006-01-02 15:04:05"),
+			}
+		}
+		protoUsers = append(protoUsers, imported)
+	}
+
+	return &proto.ImportUsersResponse{
+		Users: protoUsers,
+	}, nil
+}
+
+func (h *AuthHandler) CreateUnsubscribeToken(ctx context.Context, req *proto.CreateUnsubscribeTokenRequest) (*proto.CreateUnsubscribeTokenResponse, error) {
+	if req.UserId == "" || req.List == "" {
+		return nil, status.Error(codes.InvalidArgument, "user ID and list are required")
+	}
+
+	token, err := h.authService.CreateUnsubscribeToken(req.UserId, req.List)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &proto.CreateUnsubscribeTokenResponse{
+		Token: token,
+	}, nil
+}
+
+func (h *AuthHandler) ValidateUnsubscribeToken(ctx context.Context, req *proto.ValidateUnsubscribeTokenRequest) (*proto.ValidateUnsubscribeTokenResponse, error) {
+	userID, list, err := h.authService.ValidateUnsubscribeToken(req.Token)
+	if err != nil {
+		return &proto.ValidateUnsubscribeTokenResponse{
+			Valid: false,
+		}, nil
+	}
+
+	return &proto.ValidateUnsubscribeTokenResponse{
+		Valid:  true,
+		UserId: userID,
+		List:   list,
+	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/models"
+	"auth-service/internal/proto"
+	"auth-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *AuthHandler) SwitchOrganization(ctx context.Context, req *proto.SwitchOrganizationRequest) (*proto.SwitchOrganizationResponse, error) {
+	session, err := h.authService.SwitchOrganization(req.UserId, req.OrgId)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	return &proto.SwitchOrganizationResponse{
+		User:       toProtoUser(session.User),
+		Token:      session.Token,
+		Membership: toProtoMembership(session.Member),
+	}, nil
+}
+
+func (h *AuthHandler) CreateOrganization(ctx context.Context, req *proto.CreateOrganizationRequest) (*proto.CreateOrganizationResponse, error) {
+	member, err := h.authService.CreateOrganization(req.UserId, req.Name)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	return &proto.CreateOrganizationResponse{
+		Membership: toProtoMembership(member),
+	}, nil
+}
+
+func (h *AuthHandler) ListOrganizations(ctx context.Context, req *proto.ListOrganizationsRequest) (*proto.ListOrganizationsResponse, error) {
+	members, err := h.authService.ListOrganizations(req.UserId)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	memberships := make([]*proto.OrganizationMembership, 0, len(members))
+	for _, member := range members {
+		memberships = append(memberships, toProtoMembership(member))
+	}
+
+	return &proto.ListOrganizationsResponse{
+		Memberships: memberships,
+	}, nil
+}
+
+func (h *AuthHandler) GetOrganizationMember(ctx context.Context, req *proto.GetOrganizationMemberRequest) (*proto.GetOrganizationMemberResponse, error) {
+	member, err := h.authService.GetOrganizationMember(req.OrgId, req.UserId)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	return &proto.GetOrganizationMemberResponse{
+		Member: toProtoOrgMember(member),
+	}, nil
+}
+
+func (h *AuthHandler) ListOrganizationMembers(ctx context.Context, req *proto.ListOrganizationMembersRequest) (*proto.ListOrganizationMembersResponse, error) {
+	members, err := h.authService.ListOrganizationMembers(req.OrgId, req.UserId)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	protoMembers := make([]*proto.OrganizationMember, 0, len(members))
+	for _, member := range members {
+		protoMembers = append(protoMembers, toProtoOrgMember(member))
+	}
+
+	return &proto.ListOrganizationMembersResponse{
+		Members: protoMembers,
+	}, nil
+}
+
+func (h *AuthHandler) AddOrganizationMember(ctx context.Context, req *proto.AddOrganizationMemberRequest) (*proto.AddOrganizationMemberResponse, error) {
+	if req.Login == "" {
+		return nil, status.Error(codes.InvalidArgument, "email or username is required")
+	}
+
+	member, err := h.authService.AddOrganizationMember(req.OrgId, req.ActorId, req.Login, req.Role)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	return &proto.AddOrganizationMemberResponse{
+		Member: toProtoOrgMember(member),
+	}, nil
+}
+
+func (h *AuthHandler) UpdateOrganizationMemberRole(ctx context.Context, req *proto.UpdateOrganizationMemberRoleRequest) (*proto.UpdateOrganizationMemberRoleResponse, error) {
+	member, err := h.authService.UpdateOrganizationMemberRole(req.OrgId, req.ActorId, req.UserId, req.Role)
+	if err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	return &proto.UpdateOrganizationMemberRoleResponse{
+		Member: toProtoOrgMember(member),
+	}, nil
+}
+
+func (h *AuthHandler) RemoveOrganizationMember(ctx context.Context, req *proto.RemoveOrganizationMemberRequest) (*proto.RemoveOrganizationMemberResponse, error) {
+	if err := h.authService.RemoveOrganizationMember(req.OrgId, req.ActorId, req.UserId); err != nil {
+		return nil, toOrgStatusError(err)
+	}
+
+	return &proto.RemoveOrganizationMemberResponse{
+		Success: true,
+	}, nil
+}
+
+func toOrgStatusError(err error) error {
+	switch err {
+	case service.ErrInvalidOrgName, service.ErrInvalidOrgRole:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case service.ErrNotOrgMember, service.ErrUserNotFound, service.ErrOrganizationNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case service.ErrOrgPermissionDenied:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case service.ErrAlreadyOrgMember:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case service.ErrLastOrgOwner, service.ErrNoOrganization:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toProtoUser(user *models.User) *proto.User {
+	return &proto.User{
+		Id:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toProtoMembership(member *models.OrganizationMember) *proto.OrganizationMembership {
+	membership := &proto.OrganizationMembership{
+		Organization: &proto.Organization{Id: member.OrgID},
+		Role:         member.Role,
+		JoinedAt:     member.CreatedAt.Format(time.RFC3339),
+	}
+	if org := member.Organization; org != nil {
+		membership.Organization = &proto.Organization{
+			Id:        org.ID,
+			Name:      org.Name,
+			CreatedBy: org.CreatedBy,
+			CreatedAt: org.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: org.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return membership
+}
+
+func toProtoOrgMember(member *models.OrganizationMember) *proto.OrganizationMember {
+	protoMember := &proto.OrganizationMember{
+		User:     &proto.User{Id: member.UserID},
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt.Format(time.RFC3339),
+	}
+	if member.User != nil {
+		protoMember.User = toProtoUser(member.User)
+	}
+	return protoMember
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Organization roles. Owners and admins manage the organization's members
+// and moderate all of its rooms; only owners can make other owners.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// DefaultOrganizationID is the organization that users and rooms created
+// before organizations existed are moved into. chat-service uses the same ID.
+const DefaultOrganizationID = "00000000-0000-0000-0000-000000000001"
+
+// Organization is a workspace of one customer. Its rooms and members are
+// isolated from every other organization.
+type Organization struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedBy string    `gorm:"type:uuid" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	o.CreatedAt = time.Now()
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+func (o *Organization) BeforeUpdate(tx *gorm.DB) error {
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// OrganizationMember is a user's membership and role in an organization.
+type OrganizationMember struct {
+	OrgID        string        `gorm:"primaryKey;type:uuid" json:"org_id"`
+	UserID       string        `gorm:"primaryKey;type:uuid;index" json:"user_id"`
+	Role         string        `gorm:"not null;default:member" json:"role"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Organization *Organization `gorm:"foreignKey:OrgID;constraint:OnDelete:CASCADE" json:"organization,omitempty"`
+	User         *User         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+// IsValidOrgRole reports whether role is one of the organization roles.
+func IsValidOrgRole(role string) bool {
+	switch role {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return true
+	}
+	return false
+}
+
+// CanManage reports whether the member may manage the organization's members.
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == OrgRoleOwner || m.Role == OrgRoleAdmin
+}
\ No newline at end of file
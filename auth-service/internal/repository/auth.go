@@ -17,6 +17,18 @@ type AuthRepository interface {
 	GetUserByID(id string) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByUsername(username string) (*models.User, error)
+	GetUsersByIDs(ids []string) ([]*models.User, error)
+	GetUsersByEmailsOrUsernames(emails, usernames []string) ([]*models.User, error)
+	CreateUserWithOrganization(user *models.User, org *models.Organization) error
+	CreateOrganization(org *models.Organization, ownerID string) error
+	GetOrganizationByID(orgID string) (*models.Organization, error)
+	GetOrganizationMember(orgID, userID string) (*models.OrganizationMember, error)
+	GetUserOrganizations(userID string) ([]*models.OrganizationMember, error)
+	GetOrganizationMembers(orgID string) ([]*models.OrganizationMember, error)
+	GetOrganizationUsersByEmailsOrUsernames(orgID string, emails, usernames []string) ([]*models.User, error)
+	AddOrganizationMember(member *models.OrganizationMember) error
+	UpdateOrganizationMemberRole(orgID, userID, role string) (*models.OrganizationMember, error)
+	RemoveOrganizationMember(orgID, userID string) error
 }
 
 type authRepository struct {
@@ -72,4 +84,26 @@ func (r *authRepository) GetUserByUsername(username string) (*models.User, error
 		return nil, result.Error
 	}
 	return &user, nil
+}
+
+// GetUsersByIDs returns the users with the given IDs. IDs that are not valid
+// UUIDs simply match nothing.
+func (r *authRepository) GetUsersByIDs(ids []string) ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("id::text IN ?", ids).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+// GetUsersByEmailsOrUsernames returns every user whose username is in
+// usernames or whose lowercased email is in emails.
+func (r *authRepository) GetUsersByEmailsOrUsernames(emails, usernames []string) ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("LOWER(email) IN ? OR username IN ?", emails, usernames).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
 }
\ No newline at end of file
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"errors"
+
+	"auth-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrOrgMemberNotFound    = errors.New("organization member not found")
+	ErrAlreadyOrgMember     = errors.New("user already a member of this organization")
+	ErrLastOrgOwner         = errors.New("organization must keep at least one owner")
+)
+
+// CreateOrganization creates the organization with owner as its first owner.
+func (r *authRepository) CreateOrganization(org *models.Organization, ownerID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			OrgID:  org.ID,
+			UserID: ownerID,
+			Role:   models.OrgRoleOwner,
+		}).Error
+	})
+}
+
+// CreateUserWithOrganization creates the user together with an organization
+// they own.
+func (r *authRepository) CreateUserWithOrganization(user *models.User, org *models.Organization) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrUserExists
+			}
+			return err
+		}
+		org.CreatedBy = user.ID
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			OrgID:  org.ID,
+			UserID: user.ID,
+			Role:   models.OrgRoleOwner,
+		}).Error
+	})
+}
+
+func (r *authRepository) GetOrganizationByID(orgID string) (*models.Organization, error) {
+	var org models.Organization
+	result := r.db.First(&org, "id::text = ?", orgID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, result.Error
+	}
+	return &org, nil
+}
+
+// GetOrganizationMember returns the user's membership of the organization.
+// IDs that are not valid UUIDs simply match nothing.
+func (r *authRepository) GetOrganizationMember(orgID, userID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	result := r.db.Preload("Organization").
+		First(&member, "org_id::text = ? AND user_id::text = ?", orgID, userID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrOrgMemberNotFound
+		}
+		return nil, result.Error
+	}
+	return &member, nil
+}
+
+// GetUserOrganizations returns the user's memberships with their
+// organizations, oldest first.
+func (r *authRepository) GetUserOrganizations(userID string) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	result := r.db.Preload("Organization").
+		Where("user_id::text = ?", userID).
+		Order("created_at ASC").
+		Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+// GetOrganizationMembers returns the organization's members with their
+// users, ordered by username.
+func (r *authRepository) GetOrganizationMembers(orgID string) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	result := r.db.Joins("User").
+		Where("organization_members.org_id = ?", orgID).
+		Order(`"User".username ASC`).
+		Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+// GetOrganizationUsersByEmailsOrUsernames is GetUsersByEmailsOrUsernames
+// limited to members of the organization.
+func (r *authRepository) GetOrganizationUsersByEmailsOrUsernames(orgID string, emails, usernames []string) ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Joins("JOIN organization_members ON organization_members.user_id = users.id").
+		Where("organization_members.org_id::text = ?", orgID).
+		Where("(LOWER(users.email) IN ? OR users.username IN ?)", emails, usernames).
+		Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+func (r *authRepository) AddOrganizationMember(member *models.OrganizationMember) error {
+	result := r.db.Omit(clause.Associations).Create(member)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyOrgMember
+		}
+		return result.Error
+	}
+	return nil
+}
+
+// UpdateOrganizationMemberRole changes the member's role, refusing to demote
+// the organization's last owner.
+func (r *authRepository) UpdateOrganizationMemberRole(orgID, userID, role string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganizationMember(tx, &member, orgID, userID); err != nil {
+			return err
+		}
+		if member.Role == models.OrgRoleOwner && role != models.OrgRoleOwner {
+			if err := requireOtherOwner(tx, orgID); err != nil {
+				return err
+			}
+		}
+		member.Role = role
+		return tx.Model(&member).Update("role", role).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveOrganizationMember removes the user from the organization, refusing
+// to remove its last owner.
+func (r *authRepository) RemoveOrganizationMember(orgID, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var member models.OrganizationMember
+		if err := lockOrganizationMember(tx, &member, orgID, userID); err != nil {
+			return err
+		}
+		if member.Role == models.OrgRoleOwner {
+			if err := requireOtherOwner(tx, orgID); err != nil {
+				return err
+			}
+		}
+		return tx.Where("org_id = ? AND user_id = ?", orgID, userID).
+			Delete(&models.OrganizationMember{}).Error
+	})
+}
+
+// lockOrganizationMember loads the membership and locks the organization's
+// owners, so two owners cannot demote each other at the same time.
+func lockOrganizationMember(tx *gorm.DB, member *models.OrganizationMember, orgID, userID string) error {
+	err := tx.Exec("SELECT 1 FROM organization_members WHERE org_id = ? AND role = ? FOR UPDATE",
+		orgID, models.OrgRoleOwner).Error
+	if err != nil {
+		return err
+	}
+
+	result := tx.First(member, "org_id = ? AND user_id = ?", orgID, userID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return ErrOrgMemberNotFound
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func requireOtherOwner(tx *gorm.DB, orgID string) error {
+	var owners int64
+	err := tx.Model(&models.OrganizationMember{}).
+		Where("org_id = ? AND role = ?", orgID, models.OrgRoleOwner).
+		Count(&owners).Error
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOrgOwner
+	}
+	return nil
+}
\ No newline at end of file
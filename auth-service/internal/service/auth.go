@@ -2,23 +2,66 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"auth-service/internal/models"
 	"auth-service/internal/repository"
 	"auth-service/internal/utils"
 )
 
+// maxUsersPerLookup caps how many users GetUsers looks up in one call.
+const maxUsersPerLookup = 500
+
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrUserNotFound = errors.New("user not found")
+	ErrTooManyUsers = errors.New("too many users requested")
+	ErrInvalidToken = errors.New("invalid token")
 )
 
 type AuthService interface {
-	Register(username, email, password string) (*models.User, string, error)
-	Login(email, password string) (*models.User, string, error)
-	ValidateToken(token string) (string, string, error)
+	Register(username, email, password, orgName string) (*Session, error)
+	Login(email, password, orgID string) (*Session, error)
+	ValidateToken(token string) (*Identity, error)
 	GetUser(userID string) (*models.User, error)
+	GetUsers(userIDs []string) ([]*models.User, error)
+	ImportUsers(orgID string, users []*models.User, dryRun bool) ([]*ImportedUser, error)
+	CreateUnsubscribeToken(userID, list string) (string, error)
+	ValidateUnsubscribeToken(token string) (string, string, error)
+	SwitchOrganization(userID, orgID string) (*Session, error)
+	CreateOrganization(userID, name string) (*models.OrganizationMember, error)
+	ListOrganizations(userID string) ([]*models.OrganizationMember, error)
+	GetOrganizationMember(orgID, userID string) (*models.OrganizationMember, error)
+	ListOrganizationMembers(orgID, userID string) ([]*models.OrganizationMember, error)
+	AddOrganizationMember(orgID, actorID, login, role string) (*models.OrganizationMember, error)
+	UpdateOrganizationMemberRole(orgID, actorID, userID, role string) (*models.OrganizationMember, error)
+	RemoveOrganizationMember(orgID, actorID, userID string) error
+}
+
+// Session is a signed-in user with a token for one of their organizations.
+// Member.Organization is loaded.
+type Session struct {
+	User   *models.User
+	Member *models.OrganizationMember
+	Token  string
+}
+
+// Identity is who a valid token belongs to, and their role in the
+// organization it was issued for.
+type Identity struct {
+	UserID   string
+	Username string
+	OrgID    string
+	OrgRole  string
+}
+
+// ImportedUser is the account an imported user was mapped to. User is nil for
+// accounts a dry run would create.
+type ImportedUser struct {
+	User    *models.User
+	Created bool
 }
 
 type authService struct {
@@ -33,22 +76,32 @@ func NewAuthService(repo repository.AuthRepository) AuthService {
 	}
 }
 
-func (s *authService) Register(username, email, password string) (*models.User, string, error) {
+// Register creates the user together with an organization they own, named
+// orgName or else after them, and signs them in to it.
+func (s *authService) Register(username, email, password, orgName string) (*Session, error) {
+	orgName = strings.TrimSpace(orgName)
+	if orgName == "" {
+		orgName = username + "'s workspace"
+	}
+	if len(orgName) > maxOrgNameLength {
+		return nil, ErrInvalidOrgName
+	}
+
 	// Check if user already exists
 	_, err := s.repo.GetUserByEmail(email)
 	if err == nil {
-		return nil, "", ErrUserAlreadyExists
+		return nil, ErrUserAlreadyExists
 	}
 
 	_, err = s.repo.GetUserByUsername(username)
 	if err == nil {
-		return nil, "", ErrUserAlreadyExists
+		return nil, ErrUserAlreadyExists
 	}
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	// Create user
@@ -57,50 +110,271 @@ func (s *authService) Register(username, email, password string) (*models.User,
 		Email:    email,
 		Password: hashedPassword,
 	}
+	org := &models.Organization{Name: orgName}
 
-	err = s.repo.CreateUser(user)
+	err = s.repo.CreateUserWithOrganization(user, org)
 	if err != nil {
-		return nil, "", err
+		if err == repository.ErrUserExists {
+			return nil, ErrUserAlreadyExists
+		}
+		return nil, err
 	}
 
-	// Generate token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username)
-	if err != nil {
-		return nil, "", err
+	member := &models.OrganizationMember{
+		OrgID:        org.ID,
+		UserID:       user.ID,
+		Role:         models.OrgRoleOwner,
+		CreatedAt:    org.CreatedAt,
+		Organization: org,
 	}
-
-	return user, token, nil
+	return s.newSession(user, member)
 }
 
-func (s *authService) Login(email, password string) (*models.User, string, error) {
+// Login signs the user in to the organization orgID, or when it is empty to
+// the first organization they joined.
+func (s *authService) Login(email, password, orgID string) (*Session, error) {
 	user, err := s.repo.GetUserByEmail(email)
 	if err != nil {
-		return nil, "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
 	// Check password
 	if !utils.CheckPassword(password, user.Password) {
-		return nil, "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
-	// Generate token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username)
+	var member *models.OrganizationMember
+	if orgID != "" {
+		member, err = s.requireOrgMember(orgID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		members, err := s.repo.GetUserOrganizations(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 0 {
+			return nil, ErrNoOrganization
+		}
+		member = members[0]
+	}
+
+	return s.newSession(user, member)
+}
+
+// newSession signs a token for the user in the member's organization.
+func (s *authService) newSession(user *models.User, member *models.OrganizationMember) (*Session, error) {
+	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, member.OrgID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	return user, token, nil
+	return &Session{User: user, Member: member, Token: token}, nil
 }
 
-func (s *authService) ValidateToken(token string) (string, string, error) {
+// ValidateToken checks the token's signature and that its user still belongs
+// to its organization, so removed members lose access right away.
+func (s *authService) ValidateToken(token string) (*Identity, error) {
 	claims, err := s.jwtManager.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetOrganizationMember(claims.OrgID, claims.UserID)
+	if err != nil {
+		if err == repository.ErrOrgMemberNotFound {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &Identity{
+		UserID:   claims.UserID,
+		Username: claims.Username,
+		OrgID:    member.OrgID,
+		OrgRole:  member.Role,
+	}, nil
+}
+
+func (s *authService) CreateUnsubscribeToken(userID, list string) (string, error) {
+	return s.jwtManager.GenerateUnsubscribeToken(userID, list)
+}
+
+func (s *authService) ValidateUnsubscribeToken(token string) (string, string, error) {
+	claims, err := s.jwtManager.ValidateUnsubscribeToken(token)
 	if err != nil {
 		return "", "", err
 	}
 
-	return claims.UserID, claims.Username, nil
+	return claims.UserID, claims.List, nil
 }
 
 func (s *authService) GetUser(userID string) (*models.User, error) {
 	return s.repo.GetUserByID(userID)
+}
+
+func (s *authService) GetUsers(userIDs []string) ([]*models.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	if len(userIDs) > maxUsersPerLookup {
+		return nil, ErrTooManyUsers
+	}
+	return s.repo.GetUsersByIDs(userIDs)
+}
+
+// ImportUsers maps users of another chat system to accounts in the
+// organization. A user matches the member of the organization with the same
+// email, or else the same username; the others get a new account. Accounts
+// outside the organization are never matched, so an import cannot take over
+// another organization's users. When one of them already has the email, the
+// new account gets an email derived from it and the organization, and when
+// one has the username, a numbered username. Imported accounts have no
+// usable password.
+func (s *authService) ImportUsers(orgID string, users []*models.User, dryRun bool) ([]*ImportedUser, error) {
+	if len(users) > maxUsersPerLookup {
+		return nil, ErrTooManyUsers
+	}
+	if _, err := s.repo.GetOrganizationByID(orgID); err != nil {
+		if err == repository.ErrOrganizationNotFound {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	emails := make([]string, 0, 2*len(users))
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		if user.Email == "" {
+			user.Email = placeholderEmail(user.Username)
+		}
+		email := strings.ToLower(user.Email)
+		emails = append(emails, email, orgImportEmail(orgID, email))
+		usernames = append(usernames, user.Username)
+	}
+
+	members, err := s.repo.GetOrganizationUsersByEmailsOrUsernames(orgID, emails, usernames)
+	if err != nil {
+		return nil, err
+	}
+	byEmail := make(map[string]*models.User, len(members))
+	byUsername := make(map[string]*models.User, len(members))
+	for _, user := range members {
+		byEmail[strings.ToLower(user.Email)] = user
+		byUsername[user.Username] = user
+	}
+
+	// Emails and usernames of accounts outside the organization cannot be
+	// given to new accounts
+	existing, err := s.repo.GetUsersByEmailsOrUsernames(emails, usernames)
+	if err != nil {
+		return nil, err
+	}
+	takenEmails := make(map[string]bool, len(existing))
+	takenUsernames := make(map[string]bool, len(existing))
+	for _, user := range existing {
+		takenEmails[strings.ToLower(user.Email)] = true
+		takenUsernames[user.Username] = true
+	}
+
+	// Repeated users in one call map to the same account
+	created := make(map[string]*ImportedUser)
+	results := make([]*ImportedUser, len(users))
+	for i, user := range users {
+		email := strings.ToLower(user.Email)
+		if match, ok := byEmail[email]; ok {
+			results[i] = &ImportedUser{User: match}
+			continue
+		}
+		if match, ok := byEmail[orgImportEmail(orgID, email)]; ok {
+			results[i] = &ImportedUser{User: match}
+			continue
+		}
+		if match, ok := byUsername[user.Username]; ok {
+			results[i] = &ImportedUser{User: match}
+			continue
+		}
+		if result, ok := created[user.Username]; ok {
+			results[i] = result
+			continue
+		}
+
+		result := &ImportedUser{Created: true}
+		if !dryRun {
+			if takenEmails[email] {
+				email = orgImportEmail(orgID, email)
+			} else {
+				email = user.Email
+			}
+			account, err := s.createImportedAccount(user.Username, email, takenUsernames[user.Username])
+			if err != nil {
+				return nil, err
+			}
+			result.User = account
+		}
+		created[user.Username] = result
+		results[i] = result
+	}
+
+	if !dryRun {
+		for _, result := range results {
+			err := s.repo.AddOrganizationMember(&models.OrganizationMember{
+				OrgID:  orgID,
+				UserID: result.User.ID,
+				Role:   models.OrgRoleMember,
+			})
+			if err != nil && err != repository.ErrAlreadyOrgMember {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// maxImportedUsernames bounds the numbered usernames tried for an imported
+// user whose username is taken.
+const maxImportedUsernames = 20
+
+// createImportedAccount creates the account of an imported user. A taken
+// username is numbered, as in "alice-2".
+func (s *authService) createImportedAccount(username, email string, usernameTaken bool) (*models.User, error) {
+	first := 1
+	if usernameTaken {
+		first = 2
+	}
+
+	for n := first; n <= maxImportedUsernames; n++ {
+		account := &models.User{
+			Username: username,
+			Email:    email,
+			// Not a bcrypt hash, so no password matches it
+			Password: "!",
+		}
+		if n > 1 {
+			account.Username = fmt.Sprintf("%s-%d", username, n)
+		}
+
+		err := s.repo.CreateUser(account)
+		if err == nil {
+			return account, nil
+		}
+		if err != repository.ErrUserExists {
+			return nil, err
+		}
+	}
+	return nil, ErrUserAlreadyExists
+}
+
+// orgImportEmail stands in for the email of an imported user when an account
+// outside the organization already has it. It is derived from the email and
+// the organization, so a re-run of the import finds the account again.
+func orgImportEmail(orgID, email string) string {
+	return strings.ReplaceAll(email, "@", ".at.") + "." + orgID + "@imported.invalid"
+}
+
+// placeholderEmail stands in for the email of imported users that have none,
+// such as bots. The .invalid domain can never receive mail.
+func placeholderEmail(username string) string {
+	return strings.ToLower(username) + "@imported.invalid"
 }
\ No newline at end of file
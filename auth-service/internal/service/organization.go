@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"auth-service/internal/models"
+	"auth-service/internal/repository"
+)
+
+// maxOrgNameLength caps the length of organization names.
+const maxOrgNameLength = 100
+
+var (
+	ErrInvalidOrgName       = errors.New("organization name must be 1 to 100 characters")
+	ErrInvalidOrgRole       = errors.New("organization role must be owner, admin or member")
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrNotOrgMember         = errors.New("user is not a member of this organization")
+	ErrOrgPermissionDenied  = errors.New("insufficient organization permissions")
+	ErrAlreadyOrgMember     = errors.New("user already a member of this organization")
+	ErrLastOrgOwner         = errors.New("organization must keep at least one owner")
+	ErrNoOrganization       = errors.New("user does not belong to any organization")
+)
+
+// SwitchOrganization signs the user in to another of their organizations.
+func (s *authService) SwitchOrganization(userID, orgID string) (*Session, error) {
+	member, err := s.requireOrgMember(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newSession(user, member)
+}
+
+func (s *authService) CreateOrganization(userID, name string) (*models.OrganizationMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxOrgNameLength {
+		return nil, ErrInvalidOrgName
+	}
+
+	org := &models.Organization{
+		Name:      name,
+		CreatedBy: userID,
+	}
+	if err := s.repo.CreateOrganization(org, userID); err != nil {
+		return nil, err
+	}
+
+	return &models.OrganizationMember{
+		OrgID:        org.ID,
+		UserID:       userID,
+		Role:         models.OrgRoleOwner,
+		CreatedAt:    org.CreatedAt,
+		Organization: org,
+	}, nil
+}
+
+func (s *authService) ListOrganizations(userID string) ([]*models.OrganizationMember, error) {
+	return s.repo.GetUserOrganizations(userID)
+}
+
+func (s *authService) GetOrganizationMember(orgID, userID string) (*models.OrganizationMember, error) {
+	return s.requireOrgMember(orgID, userID)
+}
+
+// ListOrganizationMembers is the organization's user directory, which only
+// its members can see.
+func (s *authService) ListOrganizationMembers(orgID, userID string) ([]*models.OrganizationMember, error) {
+	if _, err := s.requireOrgMember(orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOrganizationMembers(orgID)
+}
+
+// AddOrganizationMember adds the user with the given email or username to
+// the organization. Owners and admins can add members; only owners can add
+// owners.
+func (s *authService) AddOrganizationMember(orgID, actorID, login, role string) (*models.OrganizationMember, error) {
+	if role == "" {
+		role = models.OrgRoleMember
+	}
+	if !models.IsValidOrgRole(role) {
+		return nil, ErrInvalidOrgRole
+	}
+
+	if _, err := s.requireOrgManager(orgID, actorID, role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.findUser(login)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &models.OrganizationMember{
+		OrgID:  orgID,
+		UserID: user.ID,
+		Role:   role,
+		User:   user,
+	}
+	if err := s.repo.AddOrganizationMember(member); err != nil {
+		if err == repository.ErrAlreadyOrgMember {
+			return nil, ErrAlreadyOrgMember
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// UpdateOrganizationMemberRole changes a member's role. Only owners can make
+// owners or change an owner's role, and the last owner cannot be demoted.
+func (s *authService) UpdateOrganizationMemberRole(orgID, actorID, userID, role string) (*models.OrganizationMember, error) {
+	if !models.IsValidOrgRole(role) {
+		return nil, ErrInvalidOrgRole
+	}
+
+	actor, err := s.requireOrgManager(orgID, actorID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.requireOrgMember(orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == models.OrgRoleOwner && actor.Role != models.OrgRoleOwner {
+		return nil, ErrOrgPermissionDenied
+	}
+
+	member, err := s.repo.UpdateOrganizationMemberRole(orgID, userID, role)
+	if err != nil {
+		return nil, orgMemberError(err)
+	}
+	return member, nil
+}
+
+// RemoveOrganizationMember removes a member from the organization. Members
+// can always leave; owners and admins can remove others, but only owners can
+// remove owners. The last owner cannot leave.
+func (s *authService) RemoveOrganizationMember(orgID, actorID, userID string) error {
+	if actorID != userID {
+		actor, err := s.requireOrgManager(orgID, actorID, "")
+		if err != nil {
+			return err
+		}
+
+		target, err := s.requireOrgMember(orgID, userID)
+		if err != nil {
+			return err
+		}
+		if target.Role == models.OrgRoleOwner && actor.Role != models.OrgRoleOwner {
+			return ErrOrgPermissionDenied
+		}
+	}
+
+	return orgMemberError(s.repo.RemoveOrganizationMember(orgID, userID))
+}
+
+// requireOrgMember returns the user's membership of the organization.
+func (s *authService) requireOrgMember(orgID, userID string) (*models.OrganizationMember, error) {
+	member, err := s.repo.GetOrganizationMember(orgID, userID)
+	if err != nil {
+		if err == repository.ErrOrgMemberNotFound {
+			return nil, ErrNotOrgMember
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// requireOrgManager returns the actor's membership if they may manage the
+// organization's members and grant role.
+func (s *authService) requireOrgManager(orgID, actorID, role string) (*models.OrganizationMember, error) {
+	actor, err := s.requireOrgMember(orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage() {
+		return nil, ErrOrgPermissionDenied
+	}
+	if role == models.OrgRoleOwner && actor.Role != models.OrgRoleOwner {
+		return nil, ErrOrgPermissionDenied
+	}
+	return actor, nil
+}
+
+// findUser looks a user up by email, or else by username.
+func (s *authService) findUser(login string) (*models.User, error) {
+	user, err := s.repo.GetUserByEmail(login)
+	if err == repository.ErrUserNotFound {
+		user, err = s.repo.GetUserByUsername(login)
+	}
+	if err != nil {
+		if err == repository.ErrUserNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func orgMemberError(err error) error {
+	switch err {
+	case repository.ErrOrgMemberNotFound:
+		return ErrNotOrgMember
+	case repository.ErrLastOrgOwner:
+		return ErrLastOrgOwner
+	}
+	return err
+}
\ No newline at end of file
@@ -13,13 +13,26 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// unsubscribeAudience marks unsubscribe tokens, so they cannot be used to
+// sign in.
+const unsubscribeAudience = "unsubscribe"
+
 type JWTManager struct {
 	secretKey string
 }
 
+// Claims identify the user and the organization they signed in to.
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
+	OrgID    string `json:"org_id"`
+	jwt.RegisteredClaims
+}
+
+// UnsubscribeClaims identify the user and mailing of an unsubscribe link.
+type UnsubscribeClaims struct {
+	UserID string `json:"user_id"`
+	List   string `json:"list"`
 	jwt.RegisteredClaims
 }
 
@@ -31,10 +44,11 @@ func NewJWTManager() *JWTManager {
 	return &JWTManager{secretKey: secretKey}
 }
 
-func (manager *JWTManager) GenerateToken(userID, username string) (string, error) {
+func (manager *JWTManager) GenerateToken(userID, username, orgID string) (string, error) {
 	claims := Claims{
 		UserID:   userID,
 		Username: username,
+		OrgID:    orgID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -60,8 +74,44 @@ func (manager *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, ErrInvalidToken
 	}
 
+	// Tokens issued before organizations existed carry no organization
 	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || len(claims.Audience) > 0 || claims.OrgID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// GenerateUnsubscribeToken signs a token that unsubscribes the user from the
+// mailing. It does not expire, since emails are kept for a long time.
+func (manager *JWTManager) GenerateUnsubscribeToken(userID, list string) (string, error) {
+	claims := UnsubscribeClaims{
+		UserID: userID,
+		List:   list,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience: jwt.ClaimStrings{unsubscribeAudience},
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(manager.secretKey))
+}
+
+func (manager *JWTManager) ValidateUnsubscribeToken(tokenString string) (*UnsubscribeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &UnsubscribeClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return []byte(manager.secretKey), nil
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*UnsubscribeClaims)
+	if !ok || !token.Valid || !claims.VerifyAudience(unsubscribeAudience, true) || claims.UserID == "" {
 		return nil, ErrInvalidToken
 	}
 
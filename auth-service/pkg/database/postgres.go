@@ -9,6 +9,7 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -57,11 +58,54 @@ func AutoMigrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to create uuid extension: %w", err)
 	}
 
-	err = db.AutoMigrate(&models.User{})
+	err = db.AutoMigrate(&models.User{}, &models.Organization{}, &models.OrganizationMember{})
 	if err != nil {
 		return fmt.Errorf("failed to auto migrate models: %w", err)
 	}
 
+	err = backfillDefaultOrganization(db)
+	if err != nil {
+		return fmt.Errorf("failed to backfill default organization: %w", err)
+	}
+
 	log.Println("Database migration completed successfully")
 	return nil
+}
+
+// backfillDefaultOrganization moves users that belong to no organization,
+// which were created before organizations existed, into the default one.
+// The earliest of them owns it, so someone can manage its members.
+func backfillDefaultOrganization(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var orphans []*models.User
+		err := tx.Where("id NOT IN (?)", tx.Model(&models.OrganizationMember{}).Select("user_id")).
+			Order("created_at").
+			Find(&orphans).Error
+		if err != nil || len(orphans) == 0 {
+			return err
+		}
+
+		org := &models.Organization{ID: models.DefaultOrganizationID, Name: "Default"}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(org).Error; err != nil {
+			return err
+		}
+
+		var owners int64
+		err = tx.Model(&models.OrganizationMember{}).
+			Where("org_id = ? AND role = ?", org.ID, models.OrgRoleOwner).
+			Count(&owners).Error
+		if err != nil {
+			return err
+		}
+
+		members := make([]*models.OrganizationMember, len(orphans))
+		for i, user := range orphans {
+			members[i] = &models.OrganizationMember{OrgID: org.ID, UserID: user.ID, Role: models.OrgRoleMember}
+		}
+		if owners == 0 {
+			members[0].Role = models.OrgRoleOwner
+		}
+		log.Printf("Moving %d users into the default organization", len(members))
+		return tx.Create(members).Error
+	})
 }
\ No newline at end of file
@@ -0,0 +1,86 @@
+// Command import imports the history of a Slack or Mattermost workspace.
+//
+//	import -slack export.zip [-org <org-id>] [-owner <user-id>] [-dry-run]
+//	import -mattermost import.jsonl [-org <org-id>] [-owner <user-id>] [-dry-run]
+//
+// Rooms are imported into the organization, and users are added to it. It
+// uses the same environment as the server to reach the database and
+// auth-service.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"chat-service/internal/importer"
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+	"chat-service/pkg/clients"
+	"chat-service/pkg/database"
+
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	slackFile := flag.String("slack", "", "Slack workspace export ZIP file")
+	mattermostFile := flag.String("mattermost", "", "Mattermost bulk import JSONL file, or a ZIP file containing it")
+	orgID := flag.String("org", models.DefaultOrganizationID, "organization ID to import into")
+	ownerID := flag.String("owner", "", "user ID that owns rooms whose creator is unknown")
+	dryRun := flag.Bool("dry-run", false, "report what would be imported without importing anything")
+	flag.Parse()
+
+	if (*slackFile == "") == (*mattermostFile == "") {
+		fmt.Fprintln(os.Stderr, "Exactly one of -slack and -mattermost is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var archive *importer.Archive
+	var err error
+	if *slackFile != "" {
+		archive, err = importer.ReadSlack(*slackFile)
+	} else {
+		archive, err = importer.ReadMattermost(*mattermostFile)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read archive: %v", err)
+	}
+
+	db, err := database.NewPostgresConnection()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	// Logging every statement would bury the report
+	db.Logger = db.Logger.LogMode(logger.Warn)
+
+	// Dry runs need the columns imports are matched by, so they migrate too
+	if err := database.AutoMigrate(db); err != nil {
+		log.Fatalf("Failed to run database migrations: %v", err)
+	}
+
+	authClient, err := clients.NewAuthClient()
+	if err != nil {
+		log.Fatalf("Failed to connect to Auth Service: %v", err)
+	}
+	defer authClient.Close()
+
+	chatRepo, err := repository.NewChatRepository(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize repository: %v", err)
+	}
+	report, err := importer.New(chatRepo.ForOrg(*orgID), authClient, *orgID, *ownerID, *dryRun).Run(ctx, archive)
+	if report != nil {
+		report.Print(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+}
\ No newline at end of file
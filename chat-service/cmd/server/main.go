@@ -5,11 +5,21 @@ import (
 	"log"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	// Do-not-disturb schedules need time zones even where the system has none
+	_ "time/tzdata"
 
+	"chat-service/internal/events"
 	"chat-service/internal/handler"
+	"chat-service/internal/mail"
+	"chat-service/internal/push"
 	"chat-service/internal/repository"
 	"chat-service/internal/service"
+	"chat-service/internal/worker"
 	"chat-service/pkg/centrifugo"
+	"chat-service/pkg/clients"
+	"chat-service/pkg/config"
 	"chat-service/pkg/database"
 
 	"google.golang.org/grpc"
@@ -18,6 +28,10 @@ import (
 )
 
 func main() {
+	// Cancelled on shutdown to stop background workers
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize database connection
 	db, err := database.NewPostgresConnection()
 	if err != nil {
@@ -37,18 +51,78 @@ func main() {
 	}
 	defer centrifugoClient.Close()
 
+	// Initialize auth-service client, used to look up usernames
+	authClient, err := clients.NewAuthClient()
+	if err != nil {
+		log.Fatalf("Failed to connect to Auth Service: %v", err)
+	}
+	defer authClient.Close()
+
 	// Initialize repository
-	chatRepo := repository.NewChatRepository(db)
+	chatRepo, err := repository.NewChatRepository(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize repository: %v", err)
+	}
+
+	// Initialize in-process event bus
+	eventBus := events.NewBus(config.Int("EVENT_STREAM_BUFFER", 256))
 
 	// Initialize service
-	chatService := service.NewChatService(chatRepo, centrifugoClient)
+	chatService := service.NewChatService(chatRepo, centrifugoClient, eventBus, authClient)
+
+	// Start retention worker
+	retentionWorker, err := worker.NewRetentionWorker(chatRepo, centrifugoClient)
+	if err != nil {
+		log.Fatalf("Failed to configure retention worker: %v", err)
+	}
+	go retentionWorker.Run(ctx)
+
+	// Start scheduled message worker
+	schedulerWorker := worker.NewSchedulerWorker(chatRepo, chatService)
+	go schedulerWorker.Run(ctx)
+
+	// Start disappearing message worker
+	expiryWorker := worker.NewExpiryWorker(chatService)
+	go expiryWorker.Run(ctx)
+
+	// Start webhook delivery worker
+	webhookWorker := worker.NewWebhookWorker(chatRepo, nil)
+	go webhookWorker.Run(ctx)
+
+	// Start room export worker
+	exportWorker, err := worker.NewExportWorker(chatRepo)
+	if err != nil {
+		log.Fatalf("Failed to configure export worker: %v", err)
+	}
+	go exportWorker.Run(ctx)
+
+	// Start push notification worker
+	pushProviders, err := push.ProvidersFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure push providers: %v", err)
+	}
+	pushWorker := worker.NewPushWorker(chatRepo, pushProviders)
+	go pushWorker.Run(ctx)
+
+	// Start email digest worker
+	mailer, err := mail.MailerFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	if mailer != nil {
+		digestWorker := worker.NewDigestWorker(chatRepo, centrifugoClient, authClient, mailer)
+		go digestWorker.Run(ctx)
+	} else {
+		log.Println("Email digests disabled; set MAIL_SMTP_ADDR or MAIL_DROP_DIR to enable them")
+	}
 
 	// Initialize gRPC handler
 	chatHandler := handler.NewChatHandler(chatService)
 
 	// Create gRPC server
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.ChainUnaryInterceptor(unaryInterceptor, handler.RequireOrg),
+		grpc.ChainStreamInterceptor(streamInterceptor, handler.RequireOrgStream),
 	)
 
 	// Register chat service
@@ -66,7 +140,16 @@ func main() {
 	}
 
 	log.Printf("Chat Service gRPC server listening on port %s", port)
-	
+
+	// Graceful shutdown
+	go func() {
+		<-ctx.Done()
+		log.Println("Shutting down Chat Service...")
+		// End event streams first, GracefulStop waits for them
+		eventBus.Close()
+		grpcServer.GracefulStop()
+	}()
+
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
@@ -76,4 +159,12 @@ func main() {
 func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	log.Printf("gRPC method called: %s", info.FullMethod)
 	return handler(ctx, req)
+}
+
+// streamInterceptor is a simple logging interceptor for streaming calls
+func streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	log.Printf("gRPC stream opened: %s", info.FullMethod)
+	err := handler(srv, ss)
+	log.Printf("gRPC stream closed: %s", info.FullMethod)
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,137 @@
+// Package digest renders the email digests of unread messages sent to users
+// who have been offline for a while.
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+	"time"
+)
+
+// Digest is the unread messages of one user across rooms.
+type Digest struct {
+	Username string
+	Rooms    []*Room
+	// UnsubscribeURL opts the user out of digests without signing in.
+	UnsubscribeURL string
+}
+
+// Room is the unread messages of one room. Mentions holds the messages that
+// mention the user, of which MoreMentions were left out.
+type Room struct {
+	Name         string
+	Unread       int
+	Mentions     []Excerpt
+	MoreMentions int
+}
+
+// Excerpt is the start of a message.
+type Excerpt struct {
+	Username string
+	Text     string
+	SentAt   time.Time
+}
+
+// Unread is the number of unread messages across rooms.
+func (d *Digest) Unread() int {
+	total := 0
+	for _, room := range d.Rooms {
+		total += room.Unread
+	}
+	return total
+}
+
+// Mentioned is the number of messages across rooms that mention the user.
+func (d *Digest) Mentioned() int {
+	total := 0
+	for _, room := range d.Rooms {
+		total += len(room.Mentions) + room.MoreMentions
+	}
+	return total
+}
+
+// Subject is the subject line of the digest email.
+func (d *Digest) Subject() string {
+	unread := plural(d.Unread(), "unread message", "unread messages")
+	if mentioned := d.Mentioned(); mentioned > 0 {
+		return fmt.Sprintf("%s and %s", plural(mentioned, "mention", "mentions"), unread)
+	}
+	if len(d.Rooms) == 1 {
+		return fmt.Sprintf("%s in %s", unread, d.Rooms[0].Name)
+	}
+	return fmt.Sprintf("%s in %d rooms", unread, len(d.Rooms))
+}
+
+// Render returns the plain-text and HTML bodies of the digest email.
+func (d *Digest) Render() (string, string, error) {
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, d); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplate.Execute(&html, d); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("Jan 2, 15:04 UTC")
+}
+
+var textTemplate = template.Must(template.New("text").Funcs(template.FuncMap{
+	"plural": plural,
+	"time":   formatTime,
+}).Parse(`Hi {{.Username}},
+
+While you were away you got {{plural .Unread "unread message" "unread messages"}}.
+{{range .Rooms}}
+{{.Name}}: {{plural .Unread "unread message" "unread messages"}}
+{{- range .Mentions}}
+  {{.Username}} mentioned you ({{time .SentAt}}):
+    {{.Text}}
+{{- end}}
+{{- if .MoreMentions}}
+  ...and {{plural .MoreMentions "more mention" "more mentions"}}
+{{- end}}
+{{end}}
+--
+You get this email because you had unread messages while offline.
+To stop these digests, visit {{.UnsubscribeURL}}
+`))
+
+// The HTML body uses inline styles only, as most mail clients drop style
+// sheets.
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(htmltemplate.FuncMap{
+	"plural": plural,
+	"time":   formatTime,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Unread messages</title></head>
+<body style="font-family: -apple-system, 'Segoe UI', Roboto, sans-serif; color: #1d1d1f; max-width: 600px; margin: 0 auto; padding: 1em;">
+<p>Hi {{.Username}},</p>
+<p>While you were away you got {{plural .Unread "unread message" "unread messages"}}.</p>
+{{range .Rooms}}
+<h2 style="font-size: 1.1em; margin: 1.5em 0 0.25em;">{{.Name}}</h2>
+<p style="color: #666; margin: 0 0 0.5em;">{{plural .Unread "unread message" "unread messages"}}</p>
+{{range .Mentions}}
+<div style="border-left: 3px solid #4a90d9; padding: 0.25em 0.75em; margin: 0.5em 0;">
+<div style="font-size: 0.85em; color: #666;"><strong>{{.Username}}</strong> mentioned you &middot; {{time .SentAt}}</div>
+<div style="white-space: pre-wrap;">{{.Text}}</div>
+</div>
+{{end}}
+{{if .MoreMentions}}<p style="font-size: 0.85em; color: #666;">&hellip;and {{plural .MoreMentions "more mention" "more mentions"}}</p>{{end}}
+{{end}}
+<hr style="border: none; border-top: 1px solid #ddd; margin-top: 2em;">
+<p style="font-size: 0.8em; color: #888;">You get this email because you had unread messages while offline. <a href="{{.UnsubscribeURL}}" style="color: #888;">Unsubscribe from these digests</a>.</p>
+</body>
+</html>
+`))
\ No newline at end of file
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"chat-service/internal/models"
+)
+
+var (
+	// ErrSlowConsumer is returned when a subscriber fell too far behind and was dropped.
+	ErrSlowConsumer = errors.New("subscriber too slow, events dropped")
+	// ErrBusClosed is returned when the bus shuts down.
+	ErrBusClosed = errors.New("event bus closed")
+)
+
+const TypeMessage = "message"
+
+// Event is a room event as seen by in-process subscribers.
+type Event struct {
+	Type   string
+	RoomID string
+	// OrgID is the organization of the room, if it has one.
+	OrgID string
+	// Seq is the message sequence number for message events and zero otherwise.
+	Seq       int64
+	Message   *models.Message
+	Data      map[string]any
+	Timestamp time.Time
+}
+
+// Bus fans room events out to in-process subscribers. Publishing never blocks:
+// a subscriber whose buffer is full is dropped with ErrSlowConsumer and is
+// expected to resubscribe from its last position.
+type Bus struct {
+	mu         sync.RWMutex
+	subs       map[*Subscription]struct{}
+	bufferSize int
+	closed     bool
+}
+
+func NewBus(bufferSize int) *Bus {
+	return &Bus{
+		subs:       make(map[*Subscription]struct{}),
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscribe registers a subscriber for the given rooms, or for all rooms when
+// roomIDs is empty.
+func (b *Bus) Subscribe(roomIDs []string) *Subscription {
+	sub := b.newSubscription()
+	if len(roomIDs) > 0 {
+		sub.rooms = make(map[string]struct{}, len(roomIDs))
+		for _, roomID := range roomIDs {
+			sub.rooms[roomID] = struct{}{}
+		}
+	}
+	return b.add(sub)
+}
+
+// SubscribeOrg registers a subscriber for every room of the organization,
+// including rooms created after it subscribed.
+func (b *Bus) SubscribeOrg(orgID string) *Subscription {
+	sub := b.newSubscription()
+	sub.orgID = orgID
+	return b.add(sub)
+}
+
+func (b *Bus) newSubscription() *Subscription {
+	return &Subscription{
+		bus:    b,
+		events: make(chan Event, b.bufferSize),
+		done:   make(chan struct{}),
+	}
+}
+
+func (b *Bus) add(sub *Subscription) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		sub.close(ErrBusClosed)
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+func (b *Bus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	var slow []*Subscription
+	for sub := range b.subs {
+		if !sub.wants(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		b.remove(sub, ErrSlowConsumer)
+	}
+}
+
+// Close drops every subscriber with ErrBusClosed.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[*Subscription]struct{})
+	b.mu.Unlock()
+
+	for sub := range subs {
+		sub.close(ErrBusClosed)
+	}
+}
+
+func (b *Bus) remove(sub *Subscription, err error) {
+	b.mu.Lock()
+	_, ok := b.subs[sub]
+	delete(b.subs, sub)
+	b.mu.Unlock()
+
+	if ok {
+		sub.close(err)
+	}
+}
+
+// Subscription receives events until it is closed by the subscriber or the bus.
+type Subscription struct {
+	bus    *Bus
+	rooms  map[string]struct{}
+	orgID  string
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// Events delivers buffered events. It is never closed; select on Done as well.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done is closed when the subscription ends; Err tells why.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.remove(s, nil)
+	s.close(nil)
+}
+
+func (s *Subscription) wants(event Event) bool {
+	if s.orgID != "" {
+		return event.OrgID == s.orgID
+	}
+	if s.rooms == nil {
+		return true
+	}
+	_, ok := s.rooms[event.RoomID]
+	return ok
+}
+
+func (s *Subscription) close(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package events
+
+import "testing"
+
+// received drains the events buffered for sub.
+func received(sub *Subscription) []Event {
+	var got []Event
+	for {
+		select {
+		case event := <-sub.Events():
+			got = append(got, event)
+		default:
+			return got
+		}
+	}
+}
+
+func TestSubscribeRooms(t *testing.T) {
+	bus := NewBus(8)
+	sub := bus.Subscribe([]string{"room-1"})
+	defer sub.Close()
+
+	bus.Publish(Event{Type: "room_updated", RoomID: "room-1", OrgID: "org-1"})
+	bus.Publish(Event{Type: "room_updated", RoomID: "room-2", OrgID: "org-1"})
+
+	got := received(sub)
+	if len(got) != 1 || got[0].RoomID != "room-1" {
+		t.Errorf("received %+v, want only the event of room-1", got)
+	}
+}
+
+func TestSubscribeOrg(t *testing.T) {
+	bus := NewBus(8)
+	sub := bus.SubscribeOrg("org-1")
+	defer sub.Close()
+
+	// A room created after subscribing is delivered like any other
+	bus.Publish(Event{Type: "room_updated", RoomID: "room-1", OrgID: "org-1"})
+	bus.Publish(Event{Type: TypeMessage, RoomID: "room-new", OrgID: "org-1", Seq: 1})
+	bus.Publish(Event{Type: TypeMessage, RoomID: "room-other", OrgID: "org-2", Seq: 1})
+	bus.Publish(Event{Type: TypeMessage, RoomID: "room-legacy", Seq: 1})
+
+	got := received(sub)
+	if len(got) != 2 || got[0].RoomID != "room-1" || got[1].RoomID != "room-new" {
+		t.Errorf("received %+v, want the events of org-1's rooms only", got)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	bus := NewBus(1)
+	sub := bus.SubscribeOrg("org-1")
+
+	bus.Publish(Event{Type: TypeMessage, RoomID: "room-1", OrgID: "org-1", Seq: 1})
+	bus.Publish(Event{Type: TypeMessage, RoomID: "room-1", OrgID: "org-1", Seq: 2})
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("subscription still open after its buffer overflowed")
+	}
+	if sub.Err() != ErrSlowConsumer {
+		t.Errorf("Err = %v, want ErrSlowConsumer", sub.Err())
+	}
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvColumns = []string{
+	"seq", "message_id", "created_at", "user_id", "username", "type", "content",
+	"edited_at", "previous_versions", "deleted", "deleted_at", "deleted_by", "expired",
+}
+
+// csvWriter writes one row per record. The room header, and with it the
+// count of messages removed by retention, is not part of the file, and
+// earlier versions of edited messages are a JSON array in one cell.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(header *Header) error {
+	return c.w.Write(csvColumns)
+}
+
+func (c *csvWriter) WriteRecord(record *Record) error {
+	previous := ""
+	if len(record.Edits) > 0 {
+		data, err := json.Marshal(record.Edits)
+		if err != nil {
+			return err
+		}
+		previous = string(data)
+	}
+
+	return c.w.Write([]string{
+		strconv.FormatInt(record.Seq, 10),
+		record.MessageID,
+		formatTime(&record.CreatedAt),
+		record.UserID,
+		csvSafe(record.Username),
+		record.Type,
+		csvSafe(record.Content),
+		formatTime(record.EditedAt),
+		csvSafe(previous),
+		strconv.FormatBool(record.Deleted),
+		formatTime(record.DeletedAt),
+		record.DeletedBy,
+		strconv.FormatBool(record.Expired),
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvSafe keeps spreadsheets from evaluating user content as a formula.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
\ No newline at end of file
@@ -0,0 +1,146 @@
+// Package export writes room transcripts as JSON Lines, CSV or a
+// self-contained HTML page.
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"chat-service/internal/models"
+)
+
+// Record is one message in a transcript, including deleted ones.
+type Record struct {
+	Seq       int64        `json:"seq"`
+	MessageID string       `json:"message_id"`
+	Type      string       `json:"type"`
+	UserID    string       `json:"user_id"`
+	Username  string       `json:"username"`
+	Content   string       `json:"content"`
+	CreatedAt time.Time    `json:"created_at"`
+	EditedAt  *time.Time   `json:"edited_at,omitempty"`
+	Poll      *models.Poll `json:"poll,omitempty"`
+
+	// Edits holds the earlier versions of the content, oldest first.
+	Edits []Edit `json:"edits,omitempty"`
+
+	// Deleted messages keep the content they had when they were deleted.
+	// Expired ones were deleted when their time-to-live ran out, by no one,
+	// and keep no content.
+	Deleted   bool       `json:"deleted"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+	Expired   bool       `json:"expired,omitempty"`
+}
+
+// Edit is a version of a message's content that a later edit replaced.
+type Edit struct {
+	Content    string    `json:"content"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// Header describes the exported room.
+type Header struct {
+	RoomID      string    `json:"room_id"`
+	RoomName    string    `json:"room_name"`
+	Description string    `json:"description,omitempty"`
+	ExportID    string    `json:"export_id"`
+	RequestedBy string    `json:"requested_by"`
+	ExportedAt  time.Time `json:"exported_at"`
+	// Purged accounts for the messages the retention policy removed, which
+	// leave gaps in the records' seq.
+	Purged *Purged `json:"purged,omitempty"`
+}
+
+// Purged sums up the messages the retention policy removed from the room.
+type Purged struct {
+	Messages        int64      `json:"messages"`
+	NewestRemovedAt *time.Time `json:"newest_removed_at,omitempty"`
+}
+
+// Writer writes a transcript: the header, then every record, then Close.
+// Close does not close the underlying io.Writer.
+type Writer interface {
+	WriteHeader(header *Header) error
+	WriteRecord(record *Record) error
+	Close() error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case models.ExportFormatJSONL:
+		return newJSONLWriter(w), nil
+	case models.ExportFormatCSV:
+		return newCSVWriter(w), nil
+	case models.ExportFormatHTML:
+		return newHTMLWriter(w), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// Dir is where export files are kept: EXPORT_DIR, or a directory under the
+// system's temporary directory. Every replica must see the same directory.
+func Dir() string {
+	if dir := os.Getenv("EXPORT_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "chat-exports")
+}
+
+// FilePath is where the file of a completed export is kept.
+func FilePath(dir, jobID string) string {
+	return filepath.Join(dir, jobID+".export")
+}
+
+// ContentType is the MIME type of files in the format.
+func ContentType(format string) string {
+	switch format {
+	case models.ExportFormatJSONL:
+		return "application/x-ndjson"
+	case models.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case models.ExportFormatHTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// FileName names the export file of a room.
+func FileName(roomName, format string, exportedAt time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", slug(roomName), exportedAt.UTC().Format("20060102-150405"), format)
+}
+
+// slug keeps letters, digits and dashes so the name is safe in a
+// Content-Disposition header and on any file system.
+func slug(name string) string {
+	out := make([]rune, 0, len(name))
+	dash := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			out = append(out, r)
+			dash = false
+		case r >= 'A' && r <= 'Z':
+			out = append(out, r+'a'-'A')
+			dash = false
+		default:
+			if !dash && len(out) > 0 {
+				out = append(out, '-')
+				dash = true
+			}
+		}
+	}
+	for len(out) > 0 && out[len(out)-1] == '-' {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return "room"
+	}
+	if len(out) > 60 {
+		out = out[:60]
+	}
+	return string(out)
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bufio"
+	"html/template"
+	"io"
+	"time"
+)
+
+// The page needs no external resources, so it can be archived as is. Content
+// is shown as escaped plain text rather than rendered Markdown, to keep the
+// transcript faithful to what was sent.
+var htmlTemplates = template.Must(template.New("header").Funcs(template.FuncMap{
+	"time": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05 UTC") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.RoomName}} transcript</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2em auto; max-width: 860px; color: #1d1d1f; }
+header { border-bottom: 1px solid #ddd; margin-bottom: 1em; }
+.meta { color: #666; font-size: 0.85em; }
+.message { padding: 0.5em 0; border-bottom: 1px solid #f0f0f0; }
+.message .author { font-weight: 600; }
+.content { white-space: pre-wrap; word-wrap: break-word; margin: 0.25em 0; }
+.deleted .content { color: #a33; text-decoration: line-through; }
+.action .content { font-style: italic; }
+details { font-size: 0.85em; color: #555; }
+.poll { font-size: 0.9em; margin: 0.25em 0 0.25em 1em; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.RoomName}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<p class="meta">Room {{.RoomID}} &middot; exported {{time .ExportedAt}} by {{.RequestedBy}} &middot; export {{.ExportID}}</p>
+{{with .Purged}}<p class="meta">{{.Messages}} earlier message(s) were removed by the retention policy{{if .NewestRemovedAt}}, the newest sent {{time .NewestRemovedAt}}{{end}}.</p>{{end}}
+</header>
+<main>
+`))
+
+func init() {
+	template.Must(htmlTemplates.New("record").Parse(`<div class="message {{.Type}}{{if .Deleted}} deleted{{end}}" id="m{{.Seq}}">
+<div class="meta"><span class="author">{{.Username}}</span> ({{.UserID}}) &middot; #{{.Seq}} &middot; {{time .CreatedAt}}{{if .EditedAt}} &middot; edited {{time .EditedAt}}{{end}}{{if .Expired}} &middot; expired {{time .DeletedAt}}{{else if .Deleted}} &middot; deleted {{time .DeletedAt}} by {{.DeletedBy}}{{end}}</div>
+<div class="content">{{.Content}}</div>
+{{with .Poll}}<ul class="poll">{{range .Options}}<li>{{.Text}}: {{.VoteCount}}</li>{{end}}</ul>{{end}}
+{{if .Edits}}<details><summary>{{len .Edits}} earlier version(s)</summary>{{range .Edits}}<div class="meta">replaced {{time .ReplacedAt}}</div><div class="content">{{.Content}}</div>{{end}}</details>{{end}}
+</div>
+`))
+	template.Must(htmlTemplates.New("footer").Parse(`</main>
+<footer class="meta"><p>{{.}} messages</p></footer>
+</body>
+</html>
+`))
+}
+
+type htmlWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func newHTMLWriter(w io.Writer) *htmlWriter {
+	return &htmlWriter{w: bufio.NewWriter(w)}
+}
+
+func (h *htmlWriter) WriteHeader(header *Header) error {
+	return htmlTemplates.ExecuteTemplate(h.w, "header", header)
+}
+
+func (h *htmlWriter) WriteRecord(record *Record) error {
+	h.count++
+	return htmlTemplates.ExecuteTemplate(h.w, "record", record)
+}
+
+func (h *htmlWriter) Close() error {
+	if err := htmlTemplates.ExecuteTemplate(h.w, "footer", h.count); err != nil {
+		return err
+	}
+	return h.w.Flush()
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// jsonlWriter writes the header as the first line, tagged "kind": "room",
+// and each record on its own line tagged "kind": "message".
+type jsonlWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	buffered := bufio.NewWriter(w)
+	enc := json.NewEncoder(buffered)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{w: buffered, enc: enc}
+}
+
+func (j *jsonlWriter) WriteHeader(header *Header) error {
+	return j.enc.Encode(struct {
+		Kind string `json:"kind"`
+		*Header
+	}{"room", header})
+}
+
+func (j *jsonlWriter) WriteRecord(record *Record) error {
+	return j.enc.Encode(struct {
+		Kind string `json:"kind"`
+		*Record
+	}{"message", record})
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.w.Flush()
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+)
+
+func (h *ChatHandler) BookmarkMessage(ctx context.Context, req *proto.BookmarkMessageRequest) (*proto.BookmarkMessageResponse, error) {
+	bookmark, err := h.scoped(ctx).BookmarkMessage(req.MessageId, req.UserId, req.Folder, req.Note)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.BookmarkMessageResponse{
+		Bookmark: toProtoBookmark(bookmark),
+	}, nil
+}
+
+func (h *ChatHandler) RemoveBookmark(ctx context.Context, req *proto.RemoveBookmarkRequest) (*proto.RemoveBookmarkResponse, error) {
+	if err := h.scoped(ctx).RemoveBookmark(req.MessageId, req.UserId); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.RemoveBookmarkResponse{
+		Success: true,
+	}, nil
+}
+
+func (h *ChatHandler) ListBookmarks(ctx context.Context, req *proto.ListBookmarksRequest) (*proto.ListBookmarksResponse, error) {
+	page, err := h.scoped(ctx).ListBookmarks(req.UserId, req.Folder, req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	bookmarks := make([]*proto.Bookmark, len(page.Bookmarks))
+	for i, bookmark := range page.Bookmarks {
+		bookmarks[i] = toProtoBookmark(bookmark)
+	}
+	folders := make([]*proto.BookmarkFolder, len(page.Folders))
+	for i, folder := range page.Folders {
+		folders[i] = &proto.BookmarkFolder{
+			Name:  folder.Name,
+			Count: folder.Count,
+		}
+	}
+
+	return &proto.ListBookmarksResponse{
+		Bookmarks:  bookmarks,
+		NextCursor: page.NextCursor,
+		Folders:    folders,
+	}, nil
+}
+
+func toProtoBookmark(bookmark *models.Bookmark) *proto.Bookmark {
+	protoBookmark := &proto.Bookmark{
+		Id:        bookmark.ID,
+		MessageId: bookmark.MessageID,
+		RoomId:    bookmark.RoomID,
+		RoomName:  bookmark.RoomName,
+		Folder:    bookmark.Folder,
+		Note:      bookmark.Note,
+		CreatedAt: bookmark.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: bookmark.UpdatedAt.Format(time.RFC3339),
+		Available: bookmark.Available(),
+	}
+	if bookmark.Message != nil {
+		protoBookmark.Message = toProtoMessage(bookmark.Message)
+	}
+	return protoBookmark
+}
\ No newline at end of file
@@ -2,8 +2,15 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
+	"chat-service/internal/events"
+	"chat-service/internal/markdown"
+	"chat-service/internal/models"
+	"chat-service/internal/moderation"
 	"chat-service/internal/proto"
 	"chat-service/internal/service"
 	"google.golang.org/grpc/codes"
@@ -22,39 +29,25 @@ func NewChatHandler(chatService service.ChatService) *ChatHandler {
 }
 
 func (h *ChatHandler) CreateRoom(ctx context.Context, req *proto.CreateRoomRequest) (*proto.CreateRoomResponse, error) {
-	room, err := h.chatService.CreateRoom(req.Name, req.Description, req.UserId)
+	room, err := h.scoped(ctx).CreateRoom(req.Name, req.Description, req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
 
 	return &proto.CreateRoomResponse{
-		Room: &proto.Room{
-			Id:          room.ID,
-			Name:        room.Name,
-			Description: room.Description,
-			CreatedBy:   room.CreatedBy,
-			CreatedAt:   room.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:   room.UpdatedAt.Format(time.RFC3339),
-		},
+		Room: toProtoRoom(room),
 	}, nil
 }
 
 func (h *ChatHandler) GetRooms(ctx context.Context, req *proto.GetRoomsRequest) (*proto.GetRoomsResponse, error) {
-	rooms, err := h.chatService.GetRooms()
+	rooms, err := h.scoped(ctx).GetRooms(req.IncludePinnedCount)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	protoRooms := make([]*proto.Room, len(rooms))
 	for i, room := range rooms {
-		protoRooms[i] = &proto.Room{
-			Id:          room.ID,
-			Name:        room.Name,
-			Description: room.Description,
-			CreatedBy:   room.CreatedBy,
-			CreatedAt:   room.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:   room.UpdatedAt.Format(time.RFC3339),
-		}
+		protoRooms[i] = toProtoRoom(room)
 	}
 
 	return &proto.GetRoomsResponse{
@@ -62,13 +55,43 @@ func (h *ChatHandler) GetRooms(ctx context.Context, req *proto.GetRoomsRequest)
 	}, nil
 }
 
+func (h *ChatHandler) UpdateRoom(ctx context.Context, req *proto.UpdateRoomRequest) (*proto.UpdateRoomResponse, error) {
+	room, err := h.scoped(ctx).UpdateRoom(req.RoomId, req.UserId, req.Name, req.Description, req.Archived)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.UpdateRoomResponse{
+		Room: toProtoRoom(room),
+	}, nil
+}
+
+func (h *ChatHandler) ArchiveRoom(ctx context.Context, req *proto.ArchiveRoomRequest) (*proto.ArchiveRoomResponse, error) {
+	room, err := h.scoped(ctx).ArchiveRoom(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.ArchiveRoomResponse{
+		Room: toProtoRoom(room),
+	}, nil
+}
+
+func (h *ChatHandler) DeleteRoom(ctx context.Context, req *proto.DeleteRoomRequest) (*proto.DeleteRoomResponse, error) {
+	err := h.scoped(ctx).DeleteRoom(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.DeleteRoomResponse{
+		Success: true,
+	}, nil
+}
+
 func (h *ChatHandler) JoinRoom(ctx context.Context, req *proto.JoinRoomRequest) (*proto.JoinRoomResponse, error) {
-	err := h.chatService.JoinRoom(req.RoomId, req.UserId)
+	err := h.scoped(ctx).JoinRoom(req.RoomId, req.UserId)
 	if err != nil {
-		if err == service.ErrRoomNotFound {
-			return nil, status.Error(codes.NotFound, "room not found")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
 
 	return &proto.JoinRoomResponse{
@@ -77,60 +100,447 @@ func (h *ChatHandler) JoinRoom(ctx context.Context, req *proto.JoinRoomRequest)
 	}, nil
 }
 
+func (h *ChatHandler) GetRoomMembers(ctx context.Context, req *proto.GetRoomMembersRequest) (*proto.GetRoomMembersResponse, error) {
+	page, err := h.scoped(ctx).GetRoomMembers(req.RoomId, req.UserId, req.Cursor, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoMembers := make([]*proto.RoomMember, len(page.Members))
+	for i, member := range page.Members {
+		protoMembers[i] = &proto.RoomMember{
+			UserId:     member.UserID,
+			Username:   member.Username,
+			Role:       member.Role,
+			JoinedAt:   member.JoinedAt.Format(time.RFC3339),
+			Online:     member.Online,
+			MutedUntil: formatOptionalTime(member.MutedUntil),
+		}
+	}
+
+	return &proto.GetRoomMembersResponse{
+		Members:     protoMembers,
+		NextCursor:  page.NextCursor,
+		OnlineCount: int32(page.OnlineCount),
+	}, nil
+}
+
+func (h *ChatHandler) SetMemberRole(ctx context.Context, req *proto.SetMemberRoleRequest) (*proto.SetMemberRoleResponse, error) {
+	err := h.scoped(ctx).SetMemberRole(req.RoomId, req.UserId, req.TargetUserId, req.Role)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetMemberRoleResponse{
+		Success: true,
+	}, nil
+}
+
 func (h *ChatHandler) SendMessage(ctx context.Context, req *proto.SendMessageRequest) (*proto.SendMessageResponse, error) {
 	// Note: We need username here, but it's not in the request
 	// For now, we'll use user_id as username, but in real implementation
 	// we would need to get username from auth service
 	username := req.UserId // Temporary - should be fetched from user service
 
-	message, err := h.chatService.SendMessage(req.RoomId, req.UserId, username, req.Content)
-	if err != nil {
-		if err == service.ErrRoomNotFound {
-			return nil, status.Error(codes.NotFound, "room not found")
+	if req.ScheduledAt != "" {
+		if req.QuoteMessageId != "" {
+			return nil, toStatusError(service.ErrQuoteNotScheduled)
 		}
-		if err == service.ErrNotRoomMember {
-			return nil, status.Error(codes.PermissionDenied, "user is not a member of this room")
+		if req.TtlSeconds != 0 {
+			return nil, toStatusError(service.ErrTTLNotScheduled)
 		}
-		if err == service.ErrInvalidMessage {
-			return nil, status.Error(codes.InvalidArgument, "invalid message content")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return h.scheduleMessage(ctx, req, username)
+	}
+
+	opts := service.SendMessageOptions{
+		ClientMessageID: req.ClientMessageId,
+		QuoteMessageID:  req.QuoteMessageId,
+		TTL:             time.Duration(req.TtlSeconds) * time.Second,
+	}
+
+	message, err := h.scoped(ctx).SendMessage(req.RoomId, req.UserId, username, req.Content, opts)
+	if err != nil {
+		return nil, toStatusError(err)
 	}
 
 	return &proto.SendMessageResponse{
-		Message: &proto.Message{
-			Id:        message.ID,
-			RoomId:    message.RoomID,
-			UserId:    message.UserID,
-			Username:  message.Username,
-			Content:   message.Content,
-			CreatedAt: message.CreatedAt.Format(time.RFC3339),
-		},
+		Message:   toProtoMessage(message),
+		Duplicate: message.Duplicate,
+	}, nil
+}
+
+func (h *ChatHandler) ForwardMessage(ctx context.Context, req *proto.ForwardMessageRequest) (*proto.ForwardMessageResponse, error) {
+	username := req.UserId // Temporary, as in SendMessage
+
+	opts := service.SendMessageOptions{
+		ClientMessageID: req.ClientMessageId,
+	}
+
+	message, err := h.scoped(ctx).ForwardMessage(req.MessageId, req.TargetRoomId, req.UserId, username, opts)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.ForwardMessageResponse{
+		Message:   toProtoMessage(message),
+		Duplicate: message.Duplicate,
+	}, nil
+}
+
+func (h *ChatHandler) EditMessage(ctx context.Context, req *proto.EditMessageRequest) (*proto.EditMessageResponse, error) {
+	message, err := h.scoped(ctx).EditMessage(req.MessageId, req.UserId, req.Content)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.EditMessageResponse{
+		Message: toProtoMessage(message),
+	}, nil
+}
+
+func (h *ChatHandler) DeleteMessage(ctx context.Context, req *proto.DeleteMessageRequest) (*proto.DeleteMessageResponse, error) {
+	err := h.scoped(ctx).DeleteMessage(req.MessageId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.DeleteMessageResponse{
+		Success: true,
 	}, nil
 }
 
 func (h *ChatHandler) GetRoomMessages(ctx context.Context, req *proto.GetRoomMessagesRequest) (*proto.GetRoomMessagesResponse, error) {
-	messages, err := h.chatService.GetRoomMessages(req.RoomId, int(req.Limit))
+	messages, err := h.scoped(ctx).GetRoomMessages(req.RoomId, req.UserId, int(req.Limit))
 	if err != nil {
-		if err == service.ErrRoomNotFound {
-			return nil, status.Error(codes.NotFound, "room not found")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, toStatusError(err)
 	}
 
 	protoMessages := make([]*proto.Message, len(messages))
 	for i, message := range messages {
-		protoMessages[i] = &proto.Message{
-			Id:        message.ID,
-			RoomId:    message.RoomID,
-			UserId:    message.UserID,
-			Username:  message.Username,
-			Content:   message.Content,
-			CreatedAt: message.CreatedAt.Format(time.RFC3339),
-		}
+		protoMessages[i] = toProtoMessage(message)
 	}
 
 	return &proto.GetRoomMessagesResponse{
 		Messages: protoMessages,
 	}, nil
+}
+
+func (h *ChatHandler) GetMessagesSince(ctx context.Context, req *proto.GetMessagesSinceRequest) (*proto.GetMessagesSinceResponse, error) {
+	messages, lastSeq, err := h.scoped(ctx).GetMessagesSince(req.RoomId, req.UserId, req.Seq, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoMessages := make([]*proto.Message, len(messages))
+	for i, message := range messages {
+		protoMessages[i] = toProtoMessage(message)
+	}
+
+	return &proto.GetMessagesSinceResponse{
+		Messages: protoMessages,
+		LastSeq:  lastSeq,
+	}, nil
+}
+
+func (h *ChatHandler) PinMessage(ctx context.Context, req *proto.PinMessageRequest) (*proto.PinMessageResponse, error) {
+	pin, err := h.scoped(ctx).PinMessage(req.RoomId, req.MessageId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.PinMessageResponse{
+		PinnedMessage: toProtoPinnedMessage(pin),
+	}, nil
+}
+
+func (h *ChatHandler) UnpinMessage(ctx context.Context, req *proto.UnpinMessageRequest) (*proto.UnpinMessageResponse, error) {
+	err := h.scoped(ctx).UnpinMessage(req.RoomId, req.MessageId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.UnpinMessageResponse{
+		Success: true,
+	}, nil
+}
+
+func (h *ChatHandler) ListPinnedMessages(ctx context.Context, req *proto.ListPinnedMessagesRequest) (*proto.ListPinnedMessagesResponse, error) {
+	pins, err := h.scoped(ctx).ListPinnedMessages(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoPins := make([]*proto.PinnedMessage, len(pins))
+	for i, pin := range pins {
+		protoPins[i] = toProtoPinnedMessage(pin)
+	}
+
+	return &proto.ListPinnedMessagesResponse{
+		PinnedMessages: protoPins,
+	}, nil
+}
+
+func (h *ChatHandler) SetRoomRetention(ctx context.Context, req *proto.SetRoomRetentionRequest) (*proto.SetRoomRetentionResponse, error) {
+	if req.Retention == nil {
+		return nil, status.Error(codes.InvalidArgument, "retention policy is required")
+	}
+
+	policy := models.RetentionPolicy{
+		Type:  req.Retention.Type,
+		Value: int(req.Retention.Value),
+	}
+
+	room, err := h.scoped(ctx).SetRoomRetention(req.RoomId, req.UserId, policy)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetRoomRetentionResponse{
+		Room: toProtoRoom(room),
+	}, nil
+}
+
+func (h *ChatHandler) GetPurgeRecords(ctx context.Context, req *proto.GetPurgeRecordsRequest) (*proto.GetPurgeRecordsResponse, error) {
+	records, err := h.scoped(ctx).GetPurgeRecords(req.RoomId, req.UserId, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoRecords := make([]*proto.PurgeRecord, len(records))
+	for i, record := range records {
+		protoRecords[i] = &proto.PurgeRecord{
+			Id:              record.ID,
+			RoomId:          record.RoomID,
+			Policy:          record.Policy,
+			Action:          record.Action,
+			MessagesRemoved: record.MessagesRemoved,
+			OldestRemovedAt: formatOptionalTime(record.OldestRemovedAt),
+			NewestRemovedAt: formatOptionalTime(record.NewestRemovedAt),
+			RoomCleared:     record.RoomCleared,
+			StartedAt:       record.StartedAt.Format(time.RFC3339),
+			FinishedAt:      record.FinishedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &proto.GetPurgeRecordsResponse{
+		Records: protoRecords,
+	}, nil
+}
+
+func (h *ChatHandler) StreamRoomEvents(req *proto.StreamRoomEventsRequest, stream proto.ChatService_StreamRoomEventsServer) error {
+	err := h.scoped(stream.Context()).StreamRoomEvents(stream.Context(), req.UserId, req.RoomIds, req.SinceSeq, req.RoomSinceSeq, func(event events.Event) error {
+		protoEvent, err := toProtoRoomEvent(event)
+		if err != nil {
+			return err
+		}
+		return stream.Send(protoEvent)
+	})
+	if err == nil || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return toStatusError(err)
+}
+
+// toStatusError maps service errors to gRPC status errors.
+func toStatusError(err error) error {
+	var rejection *moderation.RejectionError
+	if errors.As(err, &rejection) {
+		return rejectionStatus(rejection)
+	}
+
+	var limited *service.RateLimitError
+	if errors.As(err, &limited) {
+		return rateLimitStatus(limited)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrRoomNotFound),
+		errors.Is(err, service.ErrMessageNotFound),
+		errors.Is(err, service.ErrPinNotFound),
+		errors.Is(err, service.ErrModerationItemNotFound),
+		errors.Is(err, service.ErrScheduledMessageNotFound),
+		errors.Is(err, service.ErrPollNotFound),
+		errors.Is(err, service.ErrWebhookNotFound),
+		errors.Is(err, service.ErrIncomingWebhookNotFound),
+		errors.Is(err, service.ErrExportNotFound),
+		errors.Is(err, service.ErrBookmarkNotFound),
+		errors.Is(err, service.ErrPushDeviceNotFound),
+		errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrNotOrgMember):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrNotRoomMember),
+		errors.Is(err, service.ErrPermissionDenied),
+		errors.Is(err, service.ErrUserMuted),
+		errors.Is(err, service.ErrUserBanned),
+		errors.Is(err, service.ErrSourceNotReadable):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidMessage),
+		errors.Is(err, service.ErrInvalidRole),
+		errors.Is(err, service.ErrInvalidRetention),
+		errors.Is(err, service.ErrInvalidRoomName),
+		errors.Is(err, service.ErrInvalidClientMessageID),
+		errors.Is(err, service.ErrInvalidModerationConfig),
+		errors.Is(err, service.ErrInvalidSlowMode),
+		errors.Is(err, service.ErrInvalidReport),
+		errors.Is(err, service.ErrInvalidResolution),
+		errors.Is(err, service.ErrInvalidSchedule),
+		errors.Is(err, service.ErrCommandNotScheduled),
+		errors.Is(err, service.ErrCommandOptions),
+		errors.Is(err, service.ErrInvalidPoll),
+		errors.Is(err, service.ErrInvalidVote),
+		errors.Is(err, service.ErrInvalidWebhook),
+		errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidExportFormat),
+		errors.Is(err, service.ErrQuoteNotScheduled),
+		errors.Is(err, service.ErrInvalidBookmark),
+		errors.Is(err, service.ErrInvalidNotifyLevel),
+		errors.Is(err, service.ErrInvalidMutedUntil),
+		errors.Is(err, service.ErrInvalidDNDSchedule),
+		errors.Is(err, service.ErrInvalidPushDevice),
+		errors.Is(err, service.ErrInvalidMessageTTL),
+		errors.Is(err, service.ErrTTLNotScheduled):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, events.ErrSlowConsumer):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, events.ErrBusClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, service.ErrAlreadyPinned),
+		errors.Is(err, service.ErrAlreadyReported):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrPinLimitReached),
+		errors.Is(err, service.ErrRoomArchived),
+		errors.Is(err, service.ErrItemClaimed),
+		errors.Is(err, service.ErrItemResolved),
+		errors.Is(err, service.ErrScheduledMessageNotPending),
+		errors.Is(err, service.ErrPollClosed),
+		errors.Is(err, service.ErrExportNotReady):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toProtoRoom(room *models.Room) *proto.Room {
+	return &proto.Room{
+		Id:          room.ID,
+		Name:        room.Name,
+		Description: room.Description,
+		CreatedBy:   room.CreatedBy,
+		CreatedAt:   room.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   room.UpdatedAt.Format(time.RFC3339),
+		PinnedCount: room.PinnedCount,
+		Retention: &proto.RetentionPolicy{
+			Type:  room.RetentionType,
+			Value: int32(room.RetentionValue),
+		},
+		ArchivedAt:        formatOptionalTime(room.ArchivedAt),
+		LastSeq:           room.LastSeq,
+		SlowModeSeconds:   int32(room.SlowModeSeconds),
+		MessageTtlSeconds: int32(room.MessageTTLSeconds),
+		OrgId:             room.OrgID,
+	}
+}
+
+func toProtoMessage(message *models.Message) *proto.Message {
+	// Messages stored before Markdown support have not been rendered yet
+	if message.PlainText == "" && message.Content != "" {
+		message.Render()
+	}
+
+	protoMessage := &proto.Message{
+		Id:              message.ID,
+		RoomId:          message.RoomID,
+		Seq:             message.Seq,
+		UserId:          message.UserID,
+		Username:        message.Username,
+		Content:         message.Content,
+		CreatedAt:       message.CreatedAt.Format(time.RFC3339),
+		ClientMessageId: message.ClientMessageID,
+		Type:            message.Type,
+		PlainText:       message.PlainText,
+		Entities:        toProtoEntities(message.Entities),
+		EditedAt:        formatOptionalTime(message.EditedAt),
+		ExpiresAt:       formatOptionalTime(message.ExpiresAt),
+	}
+	if message.Poll != nil {
+		protoMessage.Poll = toProtoPoll(message.Poll)
+	}
+	if message.ParentID != nil {
+		protoMessage.ParentId = *message.ParentID
+	}
+	if message.Reference.IsSet() {
+		protoMessage.Reference = toProtoMessageReference(&message.Reference)
+	}
+	for _, reaction := range message.Reactions {
+		protoMessage.Reactions = append(protoMessage.Reactions, &proto.Reaction{
+			Emoji:   reaction.Emoji,
+			Count:   int32(reaction.Count),
+			UserIds: reaction.UserIDs,
+		})
+	}
+	return protoMessage
+}
+
+func toProtoMessageReference(reference *models.MessageReference) *proto.MessageReference {
+	protoReference := &proto.MessageReference{
+		Type:      reference.Type,
+		RoomId:    reference.RoomID,
+		UserId:    reference.UserID,
+		Username:  reference.Username,
+		CreatedAt: formatOptionalTime(reference.CreatedAt),
+		Content:   reference.Content,
+		Available: reference.Available,
+	}
+	if reference.MessageID != nil {
+		protoReference.MessageId = *reference.MessageID
+	}
+	return protoReference
+}
+
+func toProtoEntities(entities []markdown.Entity) []*proto.MessageEntity {
+	protoEntities := make([]*proto.MessageEntity, len(entities))
+	for i, entity := range entities {
+		protoEntities[i] = &proto.MessageEntity{
+			Type:     entity.Type,
+			Offset:   int32(entity.Offset),
+			Length:   int32(entity.Length),
+			Url:      entity.URL,
+			Language: entity.Language,
+		}
+	}
+	return protoEntities
+}
+
+func toProtoPinnedMessage(pin *models.PinnedMessage) *proto.PinnedMessage {
+	return &proto.PinnedMessage{
+		Message:  toProtoMessage(&pin.Message),
+		PinnedBy: pin.PinnedBy,
+		PinnedAt: pin.PinnedAt.Format(time.RFC3339),
+	}
+}
+
+func toProtoRoomEvent(event events.Event) (*proto.RoomEvent, error) {
+	protoEvent := &proto.RoomEvent{
+		Type:      event.Type,
+		RoomId:    event.RoomID,
+		Seq:       event.Seq,
+		Timestamp: event.Timestamp.Format(time.RFC3339),
+	}
+	if event.Message != nil {
+		protoEvent.Message = toProtoMessage(event.Message)
+	}
+	if event.Data != nil {
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event data: %w", err)
+		}
+		protoEvent.Data = string(data)
+	}
+	return protoEvent, nil
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
 }
\ No newline at end of file
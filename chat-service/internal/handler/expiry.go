@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"context"
+
+	"chat-service/internal/proto"
+)
+
+func (h *ChatHandler) SetMessageTTL(ctx context.Context, req *proto.SetMessageTTLRequest) (*proto.SetMessageTTLResponse, error) {
+	room, err := h.scoped(ctx).SetMessageTTL(req.RoomId, req.UserId, int(req.Seconds))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetMessageTTLResponse{
+		Room: toProtoRoom(room),
+	}, nil
+}
\ No newline at end of file
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"chat-service/internal/export"
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+)
+
+// exportChunkSize is how much of an export file each streamed chunk holds.
+const exportChunkSize = 64 * 1024
+
+// CreateExport queues an export of the room's history. Messages have no file
+// attachments, so exports carry no attachment metadata.
+func (h *ChatHandler) CreateExport(ctx context.Context, req *proto.CreateExportRequest) (*proto.CreateExportResponse, error) {
+	job, err := h.scoped(ctx).CreateExport(req.RoomId, req.UserId, req.Format)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.CreateExportResponse{
+		Export: toProtoExportJob(job),
+	}, nil
+}
+
+func (h *ChatHandler) GetExport(ctx context.Context, req *proto.GetExportRequest) (*proto.GetExportResponse, error) {
+	job, err := h.scoped(ctx).GetExport(req.ExportId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.GetExportResponse{
+		Export: toProtoExportJob(job),
+	}, nil
+}
+
+func (h *ChatHandler) ListExports(ctx context.Context, req *proto.ListExportsRequest) (*proto.ListExportsResponse, error) {
+	jobs, err := h.scoped(ctx).ListExports(req.RoomId, req.UserId, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoJobs := make([]*proto.ExportJob, len(jobs))
+	for i, job := range jobs {
+		protoJobs[i] = toProtoExportJob(job)
+	}
+
+	return &proto.ListExportsResponse{
+		Exports: protoJobs,
+	}, nil
+}
+
+func (h *ChatHandler) DownloadExport(req *proto.DownloadExportRequest, stream proto.ChatService_DownloadExportServer) error {
+	job, file, err := h.scoped(stream.Context()).OpenExport(req.ExportId, req.UserId)
+	if err != nil {
+		return toStatusError(err)
+	}
+	defer file.Close()
+
+	chunk := &proto.ExportChunk{
+		FileName:    job.FileName,
+		ContentType: export.ContentType(job.Format),
+		Size:        job.FileSize,
+	}
+	buf := make([]byte, exportChunkSize)
+	sent := false
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			chunk.Data = buf[:n]
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			chunk = &proto.ExportChunk{}
+			sent = true
+		}
+		if errors.Is(err, io.EOF) {
+			if !sent {
+				// The metadata goes out even for an empty file
+				return stream.Send(chunk)
+			}
+			return nil
+		}
+		if err != nil {
+			return toStatusError(err)
+		}
+	}
+}
+
+func toProtoExportJob(job *models.ExportJob) *proto.ExportJob {
+	return &proto.ExportJob{
+		Id:               job.ID,
+		RoomId:           job.RoomID,
+		RequestedBy:      job.RequestedBy,
+		Format:           job.Format,
+		Status:           job.Status,
+		TotalMessages:    job.TotalMessages,
+		ExportedMessages: job.ExportedMessages,
+		FileName:         job.FileName,
+		FileSize:         job.FileSize,
+		Error:            job.Error,
+		CreatedAt:        job.CreatedAt.Format(time.RFC3339),
+		StartedAt:        formatOptionalTime(job.StartedAt),
+		CompletedAt:      formatOptionalTime(job.CompletedAt),
+		ExpiresAt:        formatOptionalTime(job.ExpiresAt),
+	}
+}
\ No newline at end of file
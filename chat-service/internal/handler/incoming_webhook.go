@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+)
+
+func (h *ChatHandler) CreateIncomingWebhook(ctx context.Context, req *proto.CreateIncomingWebhookRequest) (*proto.CreateIncomingWebhookResponse, error) {
+	hook, token, err := h.scoped(ctx).CreateIncomingWebhook(req.RoomId, req.UserId, req.Name)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.CreateIncomingWebhookResponse{
+		Webhook: toProtoIncomingWebhook(hook),
+		Token:   token,
+	}, nil
+}
+
+func (h *ChatHandler) ListIncomingWebhooks(ctx context.Context, req *proto.ListIncomingWebhooksRequest) (*proto.ListIncomingWebhooksResponse, error) {
+	hooks, err := h.scoped(ctx).ListIncomingWebhooks(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoHooks := make([]*proto.IncomingWebhook, len(hooks))
+	for i, hook := range hooks {
+		protoHooks[i] = toProtoIncomingWebhook(hook)
+	}
+
+	return &proto.ListIncomingWebhooksResponse{
+		Webhooks: protoHooks,
+	}, nil
+}
+
+func (h *ChatHandler) RevokeIncomingWebhook(ctx context.Context, req *proto.RevokeIncomingWebhookRequest) (*proto.RevokeIncomingWebhookResponse, error) {
+	hook, err := h.scoped(ctx).RevokeIncomingWebhook(req.Id, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.RevokeIncomingWebhookResponse{
+		Webhook: toProtoIncomingWebhook(hook),
+	}, nil
+}
+
+func (h *ChatHandler) PostIncomingWebhook(ctx context.Context, req *proto.PostIncomingWebhookRequest) (*proto.PostIncomingWebhookResponse, error) {
+	opts := service.SendMessageOptions{
+		ClientMessageID: req.ClientMessageId,
+	}
+
+	message, err := h.scoped(ctx).PostIncomingWebhook(req.Token, req.Content, opts)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.PostIncomingWebhookResponse{
+		Message:   toProtoMessage(message),
+		Duplicate: message.Duplicate,
+	}, nil
+}
+
+func toProtoIncomingWebhook(hook *models.IncomingWebhook) *proto.IncomingWebhook {
+	return &proto.IncomingWebhook{
+		Id:         hook.ID,
+		RoomId:     hook.RoomID,
+		Name:       hook.Name,
+		BotUserId:  hook.BotUserID(),
+		CreatedBy:  hook.CreatedBy,
+		LastUsedAt: formatOptionalTime(hook.LastUsedAt),
+		RevokedAt:  formatOptionalTime(hook.RevokedAt),
+		CreatedAt:  hook.CreatedAt.Format(time.RFC3339),
+	}
+}
\ No newline at end of file
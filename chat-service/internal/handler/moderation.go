@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/moderation"
+	"chat-service/internal/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// moderationErrorDomain identifies moderation rejections in error details.
+const moderationErrorDomain = "chat.moderation"
+
+func (h *ChatHandler) SetRoomModeration(ctx context.Context, req *proto.SetRoomModerationRequest) (*proto.SetRoomModerationResponse, error) {
+	var filters []moderation.FilterConfig
+	if !req.UseDefault {
+		filters = fromProtoFilters(req.Filters)
+	}
+
+	room, err := h.scoped(ctx).SetRoomModeration(req.RoomId, req.UserId, filters)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetRoomModerationResponse{
+		Filters:   toProtoFilters(room.ModerationFilters),
+		IsDefault: room.ModerationFilters == nil,
+	}, nil
+}
+
+func (h *ChatHandler) GetRoomModeration(ctx context.Context, req *proto.GetRoomModerationRequest) (*proto.GetRoomModerationResponse, error) {
+	filters, isDefault, err := h.scoped(ctx).GetRoomModeration(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.GetRoomModerationResponse{
+		Filters:   toProtoFilters(filters),
+		IsDefault: isDefault,
+	}, nil
+}
+
+func (h *ChatHandler) ListModerationQueue(ctx context.Context, req *proto.ListModerationQueueRequest) (*proto.ListModerationQueueResponse, error) {
+	items, err := h.scoped(ctx).ListModerationQueue(req.RoomId, req.UserId, req.Status, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoItems := make([]*proto.ModerationItem, len(items))
+	for i, item := range items {
+		protoItems[i] = toProtoModerationItem(item)
+	}
+
+	return &proto.ListModerationQueueResponse{
+		Items: protoItems,
+	}, nil
+}
+
+func (h *ChatHandler) ReportMessage(ctx context.Context, req *proto.ReportMessageRequest) (*proto.ReportMessageResponse, error) {
+	item, err := h.scoped(ctx).ReportMessage(req.MessageId, req.UserId, req.Reason, req.Detail)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.ReportMessageResponse{
+		Item: toProtoModerationItem(item),
+	}, nil
+}
+
+func (h *ChatHandler) ClaimModerationItem(ctx context.Context, req *proto.ClaimModerationItemRequest) (*proto.ClaimModerationItemResponse, error) {
+	item, err := h.scoped(ctx).ClaimModerationItem(req.ItemId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.ClaimModerationItemResponse{
+		Item: toProtoModerationItem(item),
+	}, nil
+}
+
+func (h *ChatHandler) ResolveModerationItem(ctx context.Context, req *proto.ResolveModerationItemRequest) (*proto.ResolveModerationItemResponse, error) {
+	muteFor := time.Duration(req.MuteSeconds) * time.Second
+	item, err := h.scoped(ctx).ResolveModerationItem(req.ItemId, req.UserId, req.Action, req.Note, muteFor)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.ResolveModerationItemResponse{
+		Item: toProtoModerationItem(item),
+	}, nil
+}
+
+func (h *ChatHandler) ListModerationActions(ctx context.Context, req *proto.ListModerationActionsRequest) (*proto.ListModerationActionsResponse, error) {
+	actions, err := h.scoped(ctx).ListModerationActions(req.RoomId, req.UserId, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoActions := make([]*proto.ModerationAction, len(actions))
+	for i, action := range actions {
+		protoActions[i] = toProtoModerationAction(action)
+	}
+
+	return &proto.ListModerationActionsResponse{
+		Actions: protoActions,
+	}, nil
+}
+
+// rejectionStatus reports a moderation rejection as InvalidArgument with an
+// ErrorInfo detail whose reason clients can match on.
+func rejectionStatus(rejection *moderation.RejectionError) error {
+	st := status.New(codes.InvalidArgument, rejection.Error())
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: rejection.Reason,
+		Domain: moderationErrorDomain,
+		Metadata: map[string]string{
+			"filter": rejection.Filter,
+			"detail": rejection.Detail,
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func toProtoModerationItem(item *models.ModerationItem) *proto.ModerationItem {
+	protoItem := &proto.ModerationItem{
+		Id:             item.ID,
+		RoomId:         item.RoomID,
+		MessageId:      derefString(item.MessageID),
+		AuthorId:       item.AuthorID,
+		Content:        item.Content,
+		Source:         item.Source,
+		Filter:         item.Filter,
+		ReporterId:     item.ReporterID,
+		Reason:         item.Reason,
+		Detail:         item.Detail,
+		Status:         item.Status,
+		ClaimedBy:      item.ClaimedBy,
+		ClaimedAt:      formatOptionalTime(item.ClaimedAt),
+		ResolvedBy:     item.ResolvedBy,
+		ResolvedAt:     formatOptionalTime(item.ResolvedAt),
+		Resolution:     item.Resolution,
+		ResolutionNote: item.ResolutionNote,
+		CreatedAt:      item.CreatedAt.Format(time.RFC3339),
+	}
+	if item.Message != nil {
+		protoItem.Message = toProtoMessage(item.Message)
+	}
+	return protoItem
+}
+
+func toProtoModerationAction(action *models.ModerationAction) *proto.ModerationAction {
+	return &proto.ModerationAction{
+		Id:              action.ID,
+		RoomId:          action.RoomID,
+		ItemId:          derefString(action.ItemID),
+		ModeratorId:     action.ModeratorID,
+		Action:          action.Action,
+		TargetUserId:    action.TargetUserID,
+		MessageId:       action.MessageID,
+		Note:            action.Note,
+		DurationSeconds: action.DurationSeconds,
+		CreatedAt:       action.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func fromProtoFilters(filters []*proto.ModerationFilter) []moderation.FilterConfig {
+	configs := make([]moderation.FilterConfig, len(filters))
+	for i, filter := range filters {
+		configs[i] = moderation.FilterConfig{
+			Type:           filter.Type,
+			Action:         moderation.Action(filter.Action),
+			Words:          filter.Words,
+			Patterns:       filter.Patterns,
+			MaxLinks:       int(filter.MaxLinks),
+			AllowedDomains: filter.AllowedDomains,
+			BlockedDomains: filter.BlockedDomains,
+			MaxRepeat:      int(filter.MaxRepeat),
+			MaxCapsRatio:   filter.MaxCapsRatio,
+			MinLetters:     int(filter.MinLetters),
+		}
+	}
+	return configs
+}
+
+func toProtoFilters(configs []moderation.FilterConfig) []*proto.ModerationFilter {
+	filters := make([]*proto.ModerationFilter, len(configs))
+	for i, config := range configs {
+		filters[i] = &proto.ModerationFilter{
+			Type:           config.Type,
+			Action:         string(config.Action),
+			Words:          config.Words,
+			Patterns:       config.Patterns,
+			MaxLinks:       int32(config.MaxLinks),
+			AllowedDomains: config.AllowedDomains,
+			BlockedDomains: config.BlockedDomains,
+			MaxRepeat:      int32(config.MaxRepeat),
+			MaxCapsRatio:   config.MaxCapsRatio,
+			MinLetters:     int32(config.MinLetters),
+		}
+	}
+	return filters
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
\ No newline at end of file
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+)
+
+func (h *ChatHandler) GetRoomNotifications(ctx context.Context, req *proto.GetRoomNotificationsRequest) (*proto.GetRoomNotificationsResponse, error) {
+	member, err := h.scoped(ctx).GetRoomNotifications(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.GetRoomNotificationsResponse{
+		Notifications: toProtoRoomNotifications(member),
+	}, nil
+}
+
+func (h *ChatHandler) SetRoomNotifications(ctx context.Context, req *proto.SetRoomNotificationsRequest) (*proto.SetRoomNotificationsResponse, error) {
+	var mutedUntil *time.Time
+	if req.MutedUntil != "" {
+		until, err := time.Parse(time.RFC3339, req.MutedUntil)
+		if err != nil {
+			return nil, toStatusError(service.ErrInvalidMutedUntil)
+		}
+		mutedUntil = &until
+	}
+
+	member, err := h.scoped(ctx).SetRoomNotifications(req.RoomId, req.UserId, req.NotifyLevel, mutedUntil)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetRoomNotificationsResponse{
+		Notifications: toProtoRoomNotifications(member),
+	}, nil
+}
+
+func (h *ChatHandler) GetNotificationSettings(ctx context.Context, req *proto.GetNotificationSettingsRequest) (*proto.GetNotificationSettingsResponse, error) {
+	settings, err := h.scoped(ctx).GetNotificationSettings(req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.GetNotificationSettingsResponse{
+		Dnd:          toProtoDNDSchedule(settings),
+		EmailDigests: !settings.DigestOptOut,
+	}, nil
+}
+
+func (h *ChatHandler) SetDNDSchedule(ctx context.Context, req *proto.SetDNDScheduleRequest) (*proto.SetDNDScheduleResponse, error) {
+	schedule := service.DNDSchedule{}
+	if req.Dnd != nil {
+		schedule = service.DNDSchedule{
+			Enabled:  req.Dnd.Enabled,
+			Start:    req.Dnd.Start,
+			End:      req.Dnd.End,
+			TimeZone: req.Dnd.TimeZone,
+		}
+	}
+
+	settings, err := h.scoped(ctx).SetDNDSchedule(req.UserId, schedule)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetDNDScheduleResponse{
+		Dnd: toProtoDNDSchedule(settings),
+	}, nil
+}
+
+func (h *ChatHandler) SetEmailDigests(ctx context.Context, req *proto.SetEmailDigestsRequest) (*proto.SetEmailDigestsResponse, error) {
+	settings, err := h.scoped(ctx).SetEmailDigests(req.UserId, req.Enabled)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetEmailDigestsResponse{
+		EmailDigests: !settings.DigestOptOut,
+	}, nil
+}
+
+func toProtoRoomNotifications(member *models.RoomMember) *proto.RoomNotifications {
+	notifications := &proto.RoomNotifications{
+		RoomId:      member.RoomID,
+		NotifyLevel: member.NotifyLevel,
+	}
+	// Mutes that have run out are not reported
+	if member.NotificationsMuted(time.Now()) {
+		notifications.MutedUntil = formatOptionalTime(member.NotificationsMutedUntil)
+	}
+	return notifications
+}
+
+func toProtoDNDSchedule(settings *models.NotificationSettings) *proto.DNDSchedule {
+	timeZone := settings.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	return &proto.DNDSchedule{
+		Enabled:  settings.DNDEnabled,
+		Start:    settings.DNDStart,
+		End:      settings.DNDEnd,
+		TimeZone: timeZone,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+
+	"chat-service/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys the gateway sets on calls made for a signed-in user: the
+// organization of their token, their role in it and their user ID.
+const (
+	orgIDMetadataKey   = "x-org-id"
+	orgRoleMetadataKey = "x-org-role"
+	userIDMetadataKey  = "x-user-id"
+)
+
+// orgFreeMethods are called without a signed-in user. They find their room,
+// or their user, from a token instead.
+var orgFreeMethods = map[string]bool{
+	"/chat.ChatService/PostIncomingWebhook": true,
+	"/chat.ChatService/SetEmailDigests":     true,
+}
+
+// RequireOrg rejects calls that do not name the caller's organization, so
+// that no request reaches an unscoped service by accident.
+func RequireOrg(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !orgFreeMethods[info.FullMethod] && metadataValue(ctx, orgIDMetadataKey) == "" {
+		return nil, status.Error(codes.Unauthenticated, "organization is required")
+	}
+	return handler(ctx, req)
+}
+
+// RequireOrgStream is RequireOrg for streaming calls.
+func RequireOrgStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if metadataValue(ss.Context(), orgIDMetadataKey) == "" {
+		return status.Error(codes.Unauthenticated, "organization is required")
+	}
+	return handler(srv, ss)
+}
+
+// scoped returns the chat service scoped to the caller's organization.
+func (h *ChatHandler) scoped(ctx context.Context) service.ChatService {
+	orgID := metadataValue(ctx, orgIDMetadataKey)
+	if orgID == "" {
+		return h.chatService
+	}
+	return h.chatService.ForOrg(orgID, metadataValue(ctx, orgRoleMetadataKey), metadataValue(ctx, userIDMetadataKey))
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
\ No newline at end of file
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeChatService records the organizations it was scoped to, as
+// "orgID/orgRole/callerID". Methods it does not override panic through the
+// nil embedded interface.
+type fakeChatService struct {
+	service.ChatService
+	orgID  string
+	scopes *[]string
+}
+
+func (s *fakeChatService) ForOrg(orgID, orgRole, callerID string) service.ChatService {
+	*s.scopes = append(*s.scopes, orgID+"/"+orgRole+"/"+callerID)
+	return &fakeChatService{orgID: orgID, scopes: s.scopes}
+}
+
+func (s *fakeChatService) CreateRoom(name, description, userID string) (*models.Room, error) {
+	return &models.Room{ID: "room-1", OrgID: s.orgID, Name: name, Description: description, CreatedBy: userID}, nil
+}
+
+func TestScopedUsesCallerOrganization(t *testing.T) {
+	var scopes []string
+	h := NewChatHandler(&fakeChatService{scopes: &scopes})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		orgIDMetadataKey, "org-1",
+		orgRoleMetadataKey, "admin",
+		userIDMetadataKey, "user-1",
+	))
+
+	resp, err := h.CreateRoom(ctx, &proto.CreateRoomRequest{Name: "general", UserId: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if len(scopes) != 1 || scopes[0] != "org-1/admin/user-1" {
+		t.Errorf("scopes = %v, want one scope to org-1 for user-1 as admin", scopes)
+	}
+	if resp.Room.GetName() != "general" {
+		t.Errorf("room = %+v, want the created room", resp.Room)
+	}
+}
+
+func TestScopedWithoutOrganization(t *testing.T) {
+	var scopes []string
+	chatService := &fakeChatService{scopes: &scopes}
+	h := NewChatHandler(chatService)
+
+	if got := h.scoped(context.Background()); got != chatService {
+		t.Errorf("scoped = %v, want the unscoped service", got)
+	}
+	if len(scopes) != 0 {
+		t.Errorf("scopes = %v, want none", scopes)
+	}
+}
+
+func TestRequireOrg(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	withOrg := metadata.NewIncomingContext(context.Background(), metadata.Pairs(orgIDMetadataKey, "org-1"))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{"with organization", withOrg, "/chat.ChatService/CreateRoom", codes.OK},
+		{"without organization", context.Background(), "/chat.ChatService/CreateRoom", codes.Unauthenticated},
+		{"organization-free method", context.Background(), "/chat.ChatService/PostIncomingWebhook", codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RequireOrg(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.code {
+				t.Errorf("code = %s, want %s", code, tt.code)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+)
+
+func (h *ChatHandler) CreatePoll(ctx context.Context, req *proto.CreatePollRequest) (*proto.CreatePollResponse, error) {
+	username := req.UserId // Temporary - should be fetched from user service
+
+	params := service.PollParams{
+		Question:       req.Question,
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+	}
+	if req.ClosesAt != "" {
+		closesAt, err := time.Parse(time.RFC3339, req.ClosesAt)
+		if err != nil {
+			return nil, toStatusError(service.ErrInvalidPoll)
+		}
+		params.ClosesAt = &closesAt
+	}
+
+	message, err := h.scoped(ctx).CreatePoll(req.RoomId, req.UserId, username, params)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.CreatePollResponse{
+		Message: toProtoMessage(message),
+	}, nil
+}
+
+func (h *ChatHandler) VotePoll(ctx context.Context, req *proto.VotePollRequest) (*proto.VotePollResponse, error) {
+	poll, err := h.scoped(ctx).VotePoll(req.PollId, req.UserId, req.OptionIds)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.VotePollResponse{
+		Poll: toProtoPoll(poll),
+	}, nil
+}
+
+func (h *ChatHandler) ClosePoll(ctx context.Context, req *proto.ClosePollRequest) (*proto.ClosePollResponse, error) {
+	poll, err := h.scoped(ctx).ClosePoll(req.PollId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.ClosePollResponse{
+		Poll: toProtoPoll(poll),
+	}, nil
+}
+
+func toProtoPoll(poll *models.Poll) *proto.Poll {
+	options := make([]*proto.PollOption, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = &proto.PollOption{
+			Id:        option.ID,
+			Text:      option.Text,
+			VoteCount: int32(option.VoteCount),
+			Voters:    option.Voters,
+		}
+	}
+
+	return &proto.Poll{
+		Id:             poll.ID,
+		MessageId:      poll.MessageID,
+		Question:       poll.Question,
+		Options:        options,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		ClosesAt:       formatOptionalTime(poll.ClosesAt),
+		ClosedAt:       formatOptionalTime(poll.ClosedAt),
+		Closed:         poll.IsClosed(time.Now()),
+		TotalVoters:    int32(poll.TotalVoters),
+		CreatedBy:      poll.CreatedBy,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+)
+
+func (h *ChatHandler) RegisterPushDevice(ctx context.Context, req *proto.RegisterPushDeviceRequest) (*proto.RegisterPushDeviceResponse, error) {
+	device, err := h.scoped(ctx).RegisterPushDevice(req.UserId, req.Platform, req.Token)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.RegisterPushDeviceResponse{
+		Device: toProtoPushDevice(device),
+	}, nil
+}
+
+func (h *ChatHandler) ListPushDevices(ctx context.Context, req *proto.ListPushDevicesRequest) (*proto.ListPushDevicesResponse, error) {
+	devices, err := h.scoped(ctx).ListPushDevices(req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoDevices := make([]*proto.PushDevice, len(devices))
+	for i, device := range devices {
+		protoDevices[i] = toProtoPushDevice(device)
+	}
+
+	return &proto.ListPushDevicesResponse{
+		Devices: protoDevices,
+	}, nil
+}
+
+func (h *ChatHandler) UnregisterPushDevice(ctx context.Context, req *proto.UnregisterPushDeviceRequest) (*proto.UnregisterPushDeviceResponse, error) {
+	if err := h.scoped(ctx).UnregisterPushDevice(req.UserId, req.DeviceId); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.UnregisterPushDeviceResponse{
+		Success: true,
+	}, nil
+}
+
+func toProtoPushDevice(device *models.PushDevice) *proto.PushDevice {
+	return &proto.PushDevice{
+		Id:         device.ID,
+		Platform:   device.Platform,
+		CreatedAt:  device.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  device.UpdatedAt.Format(time.RFC3339),
+		LastPushAt: formatOptionalTime(device.LastPushAt),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	rateLimitErrorDomain = "chat.ratelimit"
+
+	reasonSlowMode    = "SLOW_MODE"
+	reasonRateLimited = "RATE_LIMITED"
+)
+
+func (h *ChatHandler) SetSlowMode(ctx context.Context, req *proto.SetSlowModeRequest) (*proto.SetSlowModeResponse, error) {
+	room, err := h.scoped(ctx).SetSlowMode(req.RoomId, req.UserId, int(req.Seconds))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SetSlowModeResponse{
+		Room: toProtoRoom(room),
+	}, nil
+}
+
+// rateLimitStatus reports a rate limit as ResourceExhausted with a RetryInfo
+// detail telling the client how long to wait.
+func rateLimitStatus(limited *service.RateLimitError) error {
+	reason := reasonRateLimited
+	if limited.SlowMode {
+		reason = reasonSlowMode
+	}
+
+	st := status.New(codes.ResourceExhausted, limited.Error())
+	detailed, err := st.WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(limited.RetryAfter)},
+		&errdetails.ErrorInfo{Reason: reason, Domain: rateLimitErrorDomain},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
\ No newline at end of file
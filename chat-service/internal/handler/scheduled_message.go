@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+)
+
+func (h *ChatHandler) scheduleMessage(ctx context.Context, req *proto.SendMessageRequest, username string) (*proto.SendMessageResponse, error) {
+	at, err := time.Parse(time.RFC3339, req.ScheduledAt)
+	if err != nil {
+		return nil, toStatusError(service.ErrInvalidSchedule)
+	}
+
+	scheduled, err := h.scoped(ctx).ScheduleMessage(req.RoomId, req.UserId, username, req.Content, at)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.SendMessageResponse{
+		ScheduledMessage: toProtoScheduledMessage(scheduled),
+	}, nil
+}
+
+func (h *ChatHandler) ListScheduledMessages(ctx context.Context, req *proto.ListScheduledMessagesRequest) (*proto.ListScheduledMessagesResponse, error) {
+	scheduled, err := h.scoped(ctx).ListScheduledMessages(req.UserId, req.RoomId, req.Status)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoScheduled := make([]*proto.ScheduledMessage, len(scheduled))
+	for i, sm := range scheduled {
+		protoScheduled[i] = toProtoScheduledMessage(sm)
+	}
+
+	return &proto.ListScheduledMessagesResponse{
+		ScheduledMessages: protoScheduled,
+	}, nil
+}
+
+func (h *ChatHandler) UpdateScheduledMessage(ctx context.Context, req *proto.UpdateScheduledMessageRequest) (*proto.UpdateScheduledMessageResponse, error) {
+	var at *time.Time
+	if req.ScheduledAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.ScheduledAt)
+		if err != nil {
+			return nil, toStatusError(service.ErrInvalidSchedule)
+		}
+		at = &parsed
+	}
+
+	scheduled, err := h.scoped(ctx).UpdateScheduledMessage(req.Id, req.UserId, req.Content, at)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.UpdateScheduledMessageResponse{
+		ScheduledMessage: toProtoScheduledMessage(scheduled),
+	}, nil
+}
+
+func (h *ChatHandler) CancelScheduledMessage(ctx context.Context, req *proto.CancelScheduledMessageRequest) (*proto.CancelScheduledMessageResponse, error) {
+	scheduled, err := h.scoped(ctx).CancelScheduledMessage(req.Id, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.CancelScheduledMessageResponse{
+		ScheduledMessage: toProtoScheduledMessage(scheduled),
+	}, nil
+}
+
+func toProtoScheduledMessage(scheduled *models.ScheduledMessage) *proto.ScheduledMessage {
+	return &proto.ScheduledMessage{
+		Id:          scheduled.ID,
+		RoomId:      scheduled.RoomID,
+		UserId:      scheduled.UserID,
+		Content:     scheduled.Content,
+		ScheduledAt: scheduled.ScheduledAt.Format(time.RFC3339),
+		Status:      scheduled.Status,
+		MessageId:   derefString(scheduled.MessageID),
+		Error:       scheduled.Error,
+		SentAt:      formatOptionalTime(scheduled.SentAt),
+		CreatedAt:   scheduled.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   scheduled.UpdatedAt.Format(time.RFC3339),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/service"
+)
+
+func (h *ChatHandler) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.CreateWebhookResponse, error) {
+	webhook, err := h.scoped(ctx).CreateWebhook(req.UserId, service.WebhookParams{
+		RoomID: req.RoomId,
+		URL:    req.Url,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	// The secret is shown once, when the webhook is created
+	protoWebhook := toProtoWebhook(webhook)
+	protoWebhook.Secret = webhook.Secret
+
+	return &proto.CreateWebhookResponse{
+		Webhook: protoWebhook,
+	}, nil
+}
+
+func (h *ChatHandler) ListWebhooks(ctx context.Context, req *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	webhooks, err := h.scoped(ctx).ListWebhooks(req.RoomId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoWebhooks := make([]*proto.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		protoWebhooks[i] = toProtoWebhook(webhook)
+	}
+
+	return &proto.ListWebhooksResponse{
+		Webhooks: protoWebhooks,
+	}, nil
+}
+
+func (h *ChatHandler) UpdateWebhook(ctx context.Context, req *proto.UpdateWebhookRequest) (*proto.UpdateWebhookResponse, error) {
+	update := service.WebhookUpdate{
+		URL:    req.Url,
+		Secret: req.Secret,
+		Active: req.Active,
+	}
+	if req.UpdateEvents {
+		update.Events = &req.Events
+	}
+
+	webhook, err := h.scoped(ctx).UpdateWebhook(req.WebhookId, req.UserId, update)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.UpdateWebhookResponse{
+		Webhook: toProtoWebhook(webhook),
+	}, nil
+}
+
+func (h *ChatHandler) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest) (*proto.DeleteWebhookResponse, error) {
+	err := h.scoped(ctx).DeleteWebhook(req.WebhookId, req.UserId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &proto.DeleteWebhookResponse{
+		Success: true,
+	}, nil
+}
+
+func (h *ChatHandler) ListWebhookDeliveries(ctx context.Context, req *proto.ListWebhookDeliveriesRequest) (*proto.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := h.scoped(ctx).ListWebhookDeliveries(req.WebhookId, req.UserId, req.Status, int(req.Limit))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	protoDeliveries := make([]*proto.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		protoDeliveries[i] = toProtoWebhookDelivery(delivery)
+	}
+
+	return &proto.ListWebhookDeliveriesResponse{
+		Deliveries: protoDeliveries,
+	}, nil
+}
+
+func toProtoWebhook(webhook *models.Webhook) *proto.Webhook {
+	return &proto.Webhook{
+		Id:             webhook.ID,
+		RoomId:         derefString(webhook.RoomID),
+		Url:            webhook.URL,
+		Events:         webhook.Events,
+		Active:         webhook.Active,
+		Failures:       int32(webhook.Failures),
+		DisabledAt:     formatOptionalTime(webhook.DisabledAt),
+		DisabledReason: webhook.DisabledReason,
+		CreatedBy:      webhook.CreatedBy,
+		CreatedAt:      webhook.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      webhook.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func toProtoWebhookDelivery(delivery *models.WebhookDelivery) *proto.WebhookDelivery {
+	return &proto.WebhookDelivery{
+		Id:             delivery.ID,
+		WebhookId:      delivery.WebhookID,
+		RoomId:         delivery.RoomID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       int32(delivery.Attempts),
+		NextAttemptAt:  delivery.NextAttemptAt.Format(time.RFC3339),
+		ResponseStatus: int32(delivery.ResponseStatus),
+		Error:          delivery.Error,
+		DeliveredAt:    formatOptionalTime(delivery.DeliveredAt),
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+	}
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+// Package importer imports the history of other chat systems into rooms.
+// Readers turn an export archive into an Archive, which the Importer then
+// writes to the database.
+package importer
+
+import (
+	"sort"
+	"time"
+)
+
+// Archive is the content of an export from another chat system. IDs are the
+// source system's, prefixed with its name, and serve as external IDs.
+type Archive struct {
+	Source   string
+	Users    []*User
+	Channels []*Channel
+}
+
+type User struct {
+	ID       string
+	Username string
+	// Email is empty for bots and users whose email was not exported.
+	Email string
+}
+
+type Channel struct {
+	ID          string
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	// CreatorID is the user ID of the channel's creator, if known.
+	CreatorID string
+	MemberIDs []string
+	Messages  []*Message
+}
+
+type Message struct {
+	ID string
+	// ThreadID is the ID of the first message of the thread this message
+	// replies to.
+	ThreadID  string
+	UserID    string
+	Text      string
+	Type      string
+	CreatedAt time.Time
+	EditedAt  *time.Time
+	Reactions []*Reaction
+	// Files counts the files attached to the message, which are not imported.
+	Files int
+}
+
+type Reaction struct {
+	Emoji  string
+	UserID string
+}
+
+// sortMessages puts the messages of every channel in the order they were sent.
+func (a *Archive) sortMessages() {
+	for _, channel := range a.Channels {
+		sort.SliceStable(channel.Messages, func(i, j int) bool {
+			return channel.Messages[i].CreatedAt.Before(channel.Messages[j].CreatedAt)
+		})
+	}
+}
\ No newline at end of file
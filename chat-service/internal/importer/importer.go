@@ -0,0 +1,319 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"chat-service/internal/models"
+	"chat-service/internal/proto"
+	"chat-service/internal/repository"
+)
+
+const (
+	// batchSize is how many messages are imported per transaction.
+	batchSize = 500
+	// userBatchSize is how many users are mapped per call, the most
+	// auth-service takes at once.
+	userBatchSize = 500
+)
+
+// Accounts maps imported users to auth-service accounts in an organization.
+// Each call takes at most userBatchSize users.
+type Accounts interface {
+	ImportUsers(ctx context.Context, orgID string, users []*proto.ImportUser, dryRun bool) ([]*proto.ImportedUser, error)
+}
+
+// Importer writes archives to the database. Rooms, messages and reactions are
+// matched by their external IDs, so an archive can be imported again, for
+// example a newer export of the same workspace, without duplicating anything.
+//
+// Imported messages are not published, do not pass moderation and do not
+// trigger webhooks.
+type Importer struct {
+	repo     repository.ChatRepository
+	accounts Accounts
+	// orgID is the organization imported users are added to. repo is
+	// scoped to it.
+	orgID string
+	// ownerID owns rooms whose creator is unknown.
+	ownerID string
+	dryRun  bool
+}
+
+func New(repo repository.ChatRepository, accounts Accounts, orgID, ownerID string, dryRun bool) *Importer {
+	return &Importer{
+		repo:     repo,
+		accounts: accounts,
+		orgID:    orgID,
+		ownerID:  ownerID,
+		dryRun:   dryRun,
+	}
+}
+
+// account is the auth-service account of an imported user. ID is empty for
+// accounts a dry run would create.
+type account struct {
+	ID       string
+	Username string
+}
+
+// Run imports the archive. In a dry run nothing is written, and the report
+// tells what an import would do.
+func (i *Importer) Run(ctx context.Context, archive *Archive) (*Report, error) {
+	report := &Report{Source: archive.Source, DryRun: i.dryRun}
+	archive.sortMessages()
+
+	accounts, err := i.importUsers(ctx, archive.Users, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import users: %w", err)
+	}
+
+	for _, channel := range archive.Channels {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := i.importChannel(ctx, channel, accounts, report); err != nil {
+			return report, fmt.Errorf("failed to import channel %s: %w", channel.Name, err)
+		}
+	}
+	return report, nil
+}
+
+// importUsers maps the archive's users to accounts in batches, so archives
+// of any size stay within what auth-service takes per call.
+func (i *Importer) importUsers(ctx context.Context, users []*User, report *Report) (map[string]account, error) {
+	results := make([]*proto.ImportedUser, 0, len(users))
+	for start := 0; start < len(users); start += userBatchSize {
+		batch := users[start:min(start+userBatchSize, len(users))]
+		requests := make([]*proto.ImportUser, len(batch))
+		for n, user := range batch {
+			requests[n] = &proto.ImportUser{Username: user.Username, Email: user.Email}
+		}
+
+		imported, err := i.accounts.ImportUsers(ctx, i.orgID, requests, i.dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if len(imported) != len(batch) {
+			return nil, fmt.Errorf("expected %d users, got %d", len(batch), len(imported))
+		}
+		results = append(results, imported...)
+	}
+
+	accounts := make(map[string]account, len(users))
+	for n, result := range results {
+		if result.Created {
+			report.UsersCreated++
+		} else {
+			report.UsersMatched++
+		}
+
+		imported := account{Username: users[n].Username}
+		if result.User != nil {
+			imported = account{ID: result.User.Id, Username: result.User.Username}
+		}
+		accounts[users[n].ID] = imported
+	}
+	return accounts, nil
+}
+
+func (i *Importer) importChannel(ctx context.Context, channel *Channel, accounts map[string]account, report *Report) error {
+	roomReport := &RoomReport{Name: channel.Name, ExternalID: channel.ID}
+	report.Rooms = append(report.Rooms, roomReport)
+
+	room, err := i.repo.GetRoomByExternalID(channel.ID)
+	if err != nil && !errors.Is(err, repository.ErrRoomNotFound) {
+		return err
+	}
+	if room == nil {
+		roomReport.Created = true
+		if room, err = i.createRoom(channel, accounts); err != nil {
+			return err
+		}
+	}
+	roomReport.RoomID = room.ID
+
+	for _, memberID := range channel.MemberIDs {
+		member, ok := accounts[memberID]
+		if !ok {
+			continue
+		}
+		added, err := i.addMember(room, member)
+		if err != nil {
+			return err
+		}
+		if added {
+			roomReport.MembersAdded++
+		}
+	}
+
+	// Messages of the archive, and those already in the room, by external ID
+	known := make(map[string]bool)
+	for start := 0; start < len(channel.Messages); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := min(start+batchSize, len(channel.Messages))
+		batch := i.prepareMessages(channel.Messages[start:end], accounts, roomReport)
+		if err := i.importMessages(room, batch, known, roomReport); err != nil {
+			return err
+		}
+	}
+
+	report.add(roomReport)
+	return nil
+}
+
+// createRoom creates the channel's room, owned by the channel's creator if
+// known. A dry run returns an unsaved room instead.
+func (i *Importer) createRoom(channel *Channel, accounts map[string]account) (*models.Room, error) {
+	ownerID := i.ownerID
+	if creator, ok := accounts[channel.CreatorID]; ok {
+		ownerID = creator.ID
+	}
+	if ownerID == "" && !i.dryRun {
+		return nil, errors.New("the channel's creator is unknown and no owner was given")
+	}
+
+	room := &models.Room{
+		Name:        channel.Name,
+		Description: channel.Description,
+		CreatedBy:   ownerID,
+		CreatedAt:   channel.CreatedAt,
+		ExternalID:  channel.ID,
+	}
+	if i.dryRun {
+		return room, nil
+	}
+
+	if err := i.repo.CreateRoom(room); err != nil {
+		return nil, err
+	}
+	if err := i.repo.AddRoomMember(room.ID, ownerID, models.RoleOwner); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// addMember adds the account to the room and reports whether it was not a
+// member yet.
+func (i *Importer) addMember(room *models.Room, member account) (bool, error) {
+	if i.dryRun {
+		if room.ID == "" || member.ID == "" {
+			// New rooms and accounts have no members or rooms yet, except
+			// for the owner, who is added with the room
+			return member.ID == "" || member.ID != room.CreatedBy, nil
+		}
+		isMember, err := i.repo.IsRoomMember(room.ID, member.ID)
+		return !isMember, err
+	}
+
+	err := i.repo.AddRoomMember(room.ID, member.ID, models.RoleMember)
+	if errors.Is(err, repository.ErrAlreadyMember) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// prepareMessages maps a batch of archived messages to the messages to
+// import, leaving out those whose author has no account.
+func (i *Importer) prepareMessages(messages []*Message, accounts map[string]account, roomReport *RoomReport) []*repository.ImportMessage {
+	batch := make([]*repository.ImportMessage, 0, len(messages))
+	for _, message := range messages {
+		author, ok := accounts[message.UserID]
+		if !ok {
+			roomReport.UnknownAuthors++
+			continue
+		}
+
+		text := message.Text
+		if message.Files > 0 {
+			roomReport.FilesSkipped += message.Files
+			if text == "" {
+				// Keep the message, as it may start a thread
+				text = "_(attachment not imported)_"
+			}
+		}
+		if text == "" {
+			roomReport.Empty++
+			continue
+		}
+
+		imported := &repository.ImportMessage{
+			Message: &models.Message{
+				UserID:     author.ID,
+				Username:   author.Username,
+				Content:    text,
+				Type:       message.Type,
+				CreatedAt:  message.CreatedAt,
+				EditedAt:   message.EditedAt,
+				ExternalID: message.ID,
+			},
+		}
+		if message.ThreadID != "" && message.ThreadID != message.ID {
+			imported.ParentExternalID = message.ThreadID
+			roomReport.Replies++
+		}
+		for _, reaction := range message.Reactions {
+			user, ok := accounts[reaction.UserID]
+			if !ok {
+				continue
+			}
+			imported.Reactions = append(imported.Reactions, &models.Reaction{
+				UserID: user.ID,
+				Emoji:  reaction.Emoji,
+			})
+		}
+		batch = append(batch, imported)
+	}
+	return batch
+}
+
+func (i *Importer) importMessages(room *models.Room, batch []*repository.ImportMessage, known map[string]bool, roomReport *RoomReport) error {
+	if !i.dryRun {
+		result, err := i.repo.ImportMessages(room.ID, batch)
+		if err != nil {
+			return err
+		}
+		roomReport.MessagesCreated += result.Created
+		roomReport.MessagesSkipped += result.Skipped
+		roomReport.Orphans += result.Orphans
+		roomReport.Reactions += result.Reactions
+		return nil
+	}
+
+	// A dry run works out the same numbers without writing; reactions are
+	// counted whether or not they exist already
+	externalIDs := make([]string, 0, 2*len(batch))
+	for _, message := range batch {
+		externalIDs = append(externalIDs, message.Message.ExternalID)
+		if message.ParentExternalID != "" {
+			externalIDs = append(externalIDs, message.ParentExternalID)
+		}
+	}
+	existing := map[string]string{}
+	if room.ID != "" {
+		ids, err := i.repo.GetImportedMessageIDs(room.ID, externalIDs)
+		if err != nil {
+			return err
+		}
+		existing = ids
+	}
+
+	for _, message := range batch {
+		externalID := message.Message.ExternalID
+		if existing[externalID] != "" || known[externalID] {
+			roomReport.MessagesSkipped++
+		} else {
+			if parent := message.ParentExternalID; parent != "" && existing[parent] == "" && !known[parent] {
+				roomReport.Orphans++
+			}
+			roomReport.MessagesCreated++
+		}
+		known[externalID] = true
+		roomReport.Reactions += int64(len(message.Reactions))
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,215 @@
+package importer
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+)
+
+// mattermostMaxLine is the longest line read from a bulk import file.
+const mattermostMaxLine = 16 << 20
+
+type mattermostLine struct {
+	Type    string             `json:"type"`
+	Channel *mattermostChannel `json:"channel"`
+	User    *mattermostUser    `json:"user"`
+	Post    *mattermostPost    `json:"post"`
+}
+
+type mattermostChannel struct {
+	Team        string `json:"team"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Purpose     string `json:"purpose"`
+}
+
+type mattermostUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Teams    []struct {
+		Name     string `json:"name"`
+		Channels []struct {
+			Name string `json:"name"`
+		} `json:"channels"`
+	} `json:"teams"`
+}
+
+type mattermostPost struct {
+	Team    string `json:"team"`
+	Channel string `json:"channel"`
+	mattermostReply
+	Replies []mattermostReply `json:"replies"`
+}
+
+type mattermostReply struct {
+	User      string `json:"user"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	CreateAt  int64  `json:"create_at"`
+	EditAt    int64  `json:"edit_at"`
+	Reactions []struct {
+		User      string `json:"user"`
+		EmojiName string `json:"emoji_name"`
+	} `json:"reactions"`
+	Attachments []json.RawMessage `json:"attachments"`
+}
+
+// ReadMattermost reads a Mattermost bulk import file: the JSONL file itself,
+// or a ZIP file containing it as written by mmctl export. Direct messages
+// have no equivalent here and are not imported.
+func ReadMattermost(file string) (*Archive, error) {
+	if path.Ext(file) == ".zip" {
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		for _, f := range zr.File {
+			if path.Ext(f.Name) != ".jsonl" {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			return readMattermost(r)
+		}
+		return nil, errors.New("no .jsonl file in the archive")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readMattermost(f)
+}
+
+func readMattermost(r io.Reader) (*Archive, error) {
+	m := &mattermostReader{
+		archive:  &Archive{Source: "mattermost"},
+		channels: make(map[string]*Channel),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), mattermostMaxLine)
+	for number := 1; scanner.Scan(); number++ {
+		var line mattermostLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		switch {
+		case line.Type == "channel" && line.Channel != nil:
+			m.addChannel(line.Channel)
+		case line.Type == "user" && line.User != nil:
+			m.addUser(line.User)
+		case line.Type == "post" && line.Post != nil:
+			m.addPost(line.Post)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m.archive, nil
+}
+
+type mattermostReader struct {
+	archive  *Archive
+	channels map[string]*Channel
+}
+
+// channel returns the channel, adding it if the file has not described it.
+func (m *mattermostReader) channel(team, name string) *Channel {
+	id := "mattermost:" + team + ":" + name
+	if channel, ok := m.channels[id]; ok {
+		return channel
+	}
+	channel := &Channel{ID: id, Name: name}
+	m.channels[id] = channel
+	m.archive.Channels = append(m.archive.Channels, channel)
+	return channel
+}
+
+func (m *mattermostReader) addChannel(line *mattermostChannel) {
+	channel := m.channel(line.Team, line.Name)
+	if line.DisplayName != "" {
+		channel.Name = line.DisplayName
+	}
+	channel.Description = line.Purpose
+}
+
+func (m *mattermostReader) addUser(line *mattermostUser) {
+	userID := "mattermost:" + line.Username
+	m.archive.Users = append(m.archive.Users, &User{
+		ID:       userID,
+		Username: line.Username,
+		Email:    line.Email,
+	})
+	for _, team := range line.Teams {
+		for _, channel := range team.Channels {
+			joined := m.channel(team.Name, channel.Name)
+			joined.MemberIDs = append(joined.MemberIDs, userID)
+		}
+	}
+}
+
+func (m *mattermostReader) addPost(line *mattermostPost) {
+	channel := m.channel(line.Team, line.Channel)
+	post := convertMattermostPost(channel.ID, &line.mattermostReply)
+	if post == nil {
+		return
+	}
+	channel.Messages = append(channel.Messages, post)
+
+	for i := range line.Replies {
+		if reply := convertMattermostPost(channel.ID, &line.Replies[i]); reply != nil {
+			reply.ThreadID = post.ID
+			channel.Messages = append(channel.Messages, reply)
+		}
+	}
+}
+
+// convertMattermostPost converts a post or reply. Posts have no ID in a bulk
+// import file, so one is made of the channel, the time and the author.
+func convertMattermostPost(channelID string, post *mattermostReply) *Message {
+	if strings.HasPrefix(post.Type, "system_") {
+		return nil
+	}
+
+	userID := "mattermost:" + post.User
+	converted := &Message{
+		ID:        channelID + ":" + strconv.FormatInt(post.CreateAt, 10) + ":" + post.User,
+		UserID:    userID,
+		Text:      post.Message,
+		Type:      models.MessageTypeText,
+		CreatedAt: time.UnixMilli(post.CreateAt),
+		Files:     len(post.Attachments),
+	}
+	if post.Type == "me" {
+		converted.Type = models.MessageTypeAction
+	}
+	if post.EditAt > 0 {
+		editedAt := time.UnixMilli(post.EditAt)
+		converted.EditedAt = &editedAt
+	}
+	for _, reaction := range post.Reactions {
+		converted.Reactions = append(converted.Reactions, &Reaction{
+			Emoji:  reaction.EmojiName,
+			UserID: "mattermost:" + reaction.User,
+		})
+	}
+	return converted
+}
\ No newline at end of file
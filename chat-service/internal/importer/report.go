@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Report tells what an import did, or would do in a dry run.
+type Report struct {
+	Source string
+	DryRun bool
+
+	UsersMatched int
+	UsersCreated int
+	RoomsCreated int
+	RoomsMatched int
+
+	MessagesCreated int
+	MessagesSkipped int
+	Reactions       int64
+
+	Rooms []*RoomReport
+}
+
+// RoomReport is the part of a report about one channel.
+type RoomReport struct {
+	Name       string
+	ExternalID string
+	// RoomID is empty for rooms a dry run would create.
+	RoomID  string
+	Created bool
+
+	MembersAdded int
+	MessagesCreated int
+	// MessagesSkipped had been imported before.
+	MessagesSkipped int
+	Replies         int
+	// Orphans are replies whose thread is not in the archive or the room;
+	// they are imported as top-level messages.
+	Orphans   int
+	Reactions int64
+
+	// Messages left out: their author has no account, or they have no text.
+	UnknownAuthors int
+	Empty          int
+	// FilesSkipped counts attached files, which are not imported.
+	FilesSkipped int
+}
+
+func (r *Report) add(room *RoomReport) {
+	if room.Created {
+		r.RoomsCreated++
+	} else {
+		r.RoomsMatched++
+	}
+	r.MessagesCreated += room.MessagesCreated
+	r.MessagesSkipped += room.MessagesSkipped
+	r.Reactions += room.Reactions
+}
+
+// Print writes the report, with a table of one line per room.
+func (r *Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	mode := "Import"
+	if r.DryRun {
+		mode = "Dry run"
+	}
+	fmt.Fprintf(tw, "%s of %s archive\n", mode, r.Source)
+	fmt.Fprintf(tw, "Users: %d matched, %d new\n", r.UsersMatched, r.UsersCreated)
+	fmt.Fprintf(tw, "Rooms: %d matched, %d new\n", r.RoomsMatched, r.RoomsCreated)
+	fmt.Fprintf(tw, "Messages: %d new, %d already imported, %d reactions\n\n", r.MessagesCreated, r.MessagesSkipped, r.Reactions)
+
+	fmt.Fprintln(tw, "CHANNEL\tROOM\tMEMBERS\tNEW\tSKIPPED\tREPLIES\tORPHANS\tREACTIONS\tNO AUTHOR\tEMPTY\tFILES")
+	for _, room := range r.Rooms {
+		roomID := room.RoomID
+		if room.Created && roomID == "" {
+			roomID = "(new)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			room.Name, roomID, room.MembersAdded, room.MessagesCreated, room.MessagesSkipped,
+			room.Replies, room.Orphans, room.Reactions, room.UnknownAuthors, room.Empty, room.FilesSkipped)
+	}
+	return tw.Flush()
+}
\ No newline at end of file
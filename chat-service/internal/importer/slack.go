@@ -0,0 +1,313 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+)
+
+// slackSkippedSubtypes are messages Slack generates about the channel itself.
+var slackSkippedSubtypes = map[string]bool{
+	"channel_join":      true,
+	"channel_leave":     true,
+	"channel_topic":     true,
+	"channel_purpose":   true,
+	"channel_name":      true,
+	"channel_archive":   true,
+	"channel_unarchive": true,
+	"group_join":        true,
+	"group_leave":       true,
+	"group_topic":       true,
+	"group_purpose":     true,
+	"group_name":        true,
+	"group_archive":     true,
+	"group_unarchive":   true,
+	"pinned_item":       true,
+	"unpinned_item":     true,
+	"tombstone":         true,
+}
+
+type slackUser struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Profile struct {
+		Email string `json:"email"`
+	} `json:"profile"`
+}
+
+type slackChannel struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Created int64    `json:"created"`
+	Creator string   `json:"creator"`
+	Members []string `json:"members"`
+	Purpose struct {
+		Value string `json:"value"`
+	} `json:"purpose"`
+}
+
+type slackMessage struct {
+	Type       string `json:"type"`
+	Subtype    string `json:"subtype"`
+	User       string `json:"user"`
+	BotID      string `json:"bot_id"`
+	Username   string `json:"username"`
+	Text       string `json:"text"`
+	TS         string `json:"ts"`
+	ThreadTS   string `json:"thread_ts"`
+	BotProfile *struct {
+		Name string `json:"name"`
+	} `json:"bot_profile"`
+	Edited *struct {
+		TS string `json:"ts"`
+	} `json:"edited"`
+	Reactions []struct {
+		Name  string   `json:"name"`
+		Users []string `json:"users"`
+	} `json:"reactions"`
+	Files []json.RawMessage `json:"files"`
+}
+
+// ReadSlack reads a Slack workspace export: the ZIP file with users.json,
+// channels.json and a folder of daily message files per channel. Private
+// channels in groups.json are included when present. Direct messages have no
+// equivalent here and are not imported.
+func ReadSlack(file string) (*Archive, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var users []slackUser
+	if err := readZipJSON(files, "users.json", &users); err != nil {
+		return nil, err
+	}
+	var channels []slackChannel
+	if err := readZipJSON(files, "channels.json", &channels); err != nil {
+		return nil, err
+	}
+	if _, ok := files["groups.json"]; ok {
+		var groups []slackChannel
+		if err := readZipJSON(files, "groups.json", &groups); err != nil {
+			return nil, err
+		}
+		channels = append(channels, groups...)
+	}
+
+	s := &slackReader{
+		archive:   &Archive{Source: "slack"},
+		users:     make(map[string]*User, len(users)),
+		usernames: make(map[string]string, len(users)),
+		channels:  make(map[string]string, len(channels)),
+	}
+	for _, user := range users {
+		s.addUser(&User{ID: "slack:" + user.ID, Username: user.Name, Email: user.Profile.Email})
+		s.usernames[user.ID] = user.Name
+	}
+	for _, channel := range channels {
+		s.channels[channel.ID] = channel.Name
+	}
+
+	for _, channel := range channels {
+		imported, err := s.readChannel(zr.File, channel)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channel.Name, err)
+		}
+		s.archive.Channels = append(s.archive.Channels, imported)
+	}
+	return s.archive, nil
+}
+
+type slackReader struct {
+	archive *Archive
+	users   map[string]*User
+	// usernames and channels map Slack IDs to names, to resolve mentions.
+	usernames map[string]string
+	channels  map[string]string
+}
+
+func (s *slackReader) addUser(user *User) {
+	if _, ok := s.users[user.ID]; ok {
+		return
+	}
+	s.users[user.ID] = user
+	s.archive.Users = append(s.archive.Users, user)
+}
+
+func (s *slackReader) readChannel(files []*zip.File, channel slackChannel) (*Channel, error) {
+	channelID := "slack:" + channel.ID
+	imported := &Channel{
+		ID:          channelID,
+		Name:        channel.Name,
+		Description: channel.Purpose.Value,
+		CreatedAt:   time.Unix(channel.Created, 0),
+	}
+	if channel.Creator != "" {
+		imported.CreatorID = "slack:" + channel.Creator
+	}
+	for _, member := range channel.Members {
+		imported.MemberIDs = append(imported.MemberIDs, "slack:"+member)
+	}
+
+	// One file of messages per day, named YYYY-MM-DD.json
+	var days []*zip.File
+	for _, f := range files {
+		if path.Dir(f.Name) == channel.Name && path.Ext(f.Name) == ".json" {
+			days = append(days, f)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Name < days[j].Name })
+
+	for _, day := range days {
+		var messages []slackMessage
+		if err := decodeZipJSON(day, &messages); err != nil {
+			return nil, fmt.Errorf("%s: %w", day.Name, err)
+		}
+		for _, message := range messages {
+			if converted := s.convertMessage(channelID, message); converted != nil {
+				imported.Messages = append(imported.Messages, converted)
+			}
+		}
+	}
+	return imported, nil
+}
+
+func (s *slackReader) convertMessage(channelID string, message slackMessage) *Message {
+	if message.Type != "message" || slackSkippedSubtypes[message.Subtype] {
+		return nil
+	}
+	createdAt, ok := slackTime(message.TS)
+	if !ok {
+		return nil
+	}
+
+	userID := "slack:" + message.User
+	if message.User == "" {
+		if message.BotID == "" {
+			return nil
+		}
+		// Bots without a user of their own post under their bot ID
+		name := message.Username
+		if name == "" && message.BotProfile != nil {
+			name = message.BotProfile.Name
+		}
+		if name == "" {
+			name = message.BotID
+		}
+		userID = "slack:" + message.BotID
+		s.addUser(&User{ID: userID, Username: name})
+	}
+
+	converted := &Message{
+		ID:        channelID + ":" + message.TS,
+		UserID:    userID,
+		Text:      s.convertText(message.Text),
+		Type:      models.MessageTypeText,
+		CreatedAt: createdAt,
+		Files:     len(message.Files),
+	}
+	if message.Subtype == "me_message" {
+		converted.Type = models.MessageTypeAction
+	}
+	if message.ThreadTS != "" {
+		converted.ThreadID = channelID + ":" + message.ThreadTS
+	}
+	if message.Edited != nil {
+		if editedAt, ok := slackTime(message.Edited.TS); ok {
+			converted.EditedAt = &editedAt
+		}
+	}
+	for _, reaction := range message.Reactions {
+		for _, user := range reaction.Users {
+			converted.Reactions = append(converted.Reactions, &Reaction{
+				Emoji:  reaction.Name,
+				UserID: "slack:" + user,
+			})
+		}
+	}
+	return converted
+}
+
+var (
+	slackEntityPattern = regexp.MustCompile(`<([^<>\n]+)>`)
+	slackBoldPattern   = regexp.MustCompile(`(^|[\s(])\*([^*\n]+)\*`)
+	slackUnescaper     = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+// convertText turns Slack's mrkdwn into the Markdown messages use here:
+// *bold* becomes **bold**, links become [label](url), and user and channel
+// mentions are replaced with names.
+func (s *slackReader) convertText(text string) string {
+	text = slackBoldPattern.ReplaceAllString(text, "$1**$2**")
+	text = slackEntityPattern.ReplaceAllStringFunc(text, func(match string) string {
+		target, label, _ := strings.Cut(match[1:len(match)-1], "|")
+		switch {
+		case strings.HasPrefix(target, "@"):
+			if name, ok := s.usernames[target[1:]]; ok {
+				return "@" + name
+			}
+			return "@" + strings.TrimPrefix(label, "@")
+		case strings.HasPrefix(target, "#"):
+			if name, ok := s.channels[target[1:]]; ok {
+				return "#" + name
+			}
+			return "#" + label
+		case strings.HasPrefix(target, "!"):
+			switch target {
+			case "!here", "!channel", "!everyone":
+				return "@" + target[1:]
+			}
+			return label
+		case label != "":
+			return "[" + label + "](" + target + ")"
+		}
+		return target
+	})
+	return slackUnescaper.Replace(text)
+}
+
+// slackTime parses a message timestamp such as "1503435956.000247", which
+// is also the message's ID within its channel.
+func slackTime(ts string) (time.Time, bool) {
+	seconds, micros, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	usec, _ := strconv.ParseInt(micros, 10, 64)
+	return time.Unix(sec, usec*int64(time.Microsecond)), true
+}
+
+func readZipJSON(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s is missing from the archive", name)
+	}
+	if err := decodeZipJSON(f, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func decodeZipJSON(f *zip.File, v any) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return json.NewDecoder(r).Decode(v)
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"log"
+	"os"
+)
+
+const defaultFrom = "Chat <no-reply@localhost>"
+
+// MailerFromEnv returns the mailer the environment configures: SMTP when
+// MAIL_SMTP_ADDR is set, or else a drop directory when MAIL_DROP_DIR is set.
+// It returns nil when neither is, and nothing should be mailed.
+func MailerFromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = defaultFrom
+	}
+
+	var mailer Mailer
+	var err error
+	if addr := os.Getenv("MAIL_SMTP_ADDR"); addr != "" {
+		mailer, err = NewSMTPMailer(SMTPConfig{
+			Addr:     addr,
+			Username: os.Getenv("MAIL_SMTP_USERNAME"),
+			Password: os.Getenv("MAIL_SMTP_PASSWORD"),
+			From:     from,
+		})
+	} else if dir := os.Getenv("MAIL_DROP_DIR"); dir != "" {
+		mailer, err = NewFileMailer(dir, from)
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Mailer: %s", mailer.Name())
+	return mailer, nil
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer writes each message to a .eml file in a directory instead of
+// sending it, for development and tests.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if _, err := parseAddress(from); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Name() string {
+	return "file (" + m.dir + ")"
+}
+
+func (m *FileMailer) Send(ctx context.Context, message *Message) error {
+	email, err := compose(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	// Files sort in the order they were sent
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	path := filepath.Join(m.dir, name)
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, email, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
\ No newline at end of file
@@ -0,0 +1,111 @@
+// Package mail sends email. Each way of delivering it is a Mailer: SMTP for
+// a mail server, such as MailHog in development, or a drop directory that
+// keeps every message as a file.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is an email to one recipient, with plain-text and HTML versions
+// of the same body.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// Headers are added to the standard ones, such as List-Unsubscribe.
+	Headers map[string]string
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	Name() string
+	Send(ctx context.Context, message *Message) error
+}
+
+// compose renders the message as a MIME multipart/alternative email.
+func compose(from string, message *Message) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	if err := writePart(parts, "text/plain; charset=utf-8", message.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart(parts, "text/html; charset=utf-8", message.HTML); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	messageID, err := newMessageID(from)
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"From":         from,
+		"To":           message.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"Message-ID":   messageID,
+		"MIME-Version": "1.0",
+		"Content-Type": "multipart/alternative; boundary=" + parts.Boundary(),
+	}
+	for name, value := range message.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var email bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&email, "%s: %s\r\n", name, headers[name])
+	}
+	email.WriteString("\r\n")
+	email.Write(body.Bytes())
+	return email.Bytes(), nil
+}
+
+func writePart(parts *multipart.Writer, contentType, content string) error {
+	part, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func newMessageID(from string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if address, err := parseAddress(from); err == nil {
+		if at := strings.LastIndexByte(address, '@'); at >= 0 {
+			domain = address[at+1:]
+		}
+	}
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">", nil
+}
\ No newline at end of file
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+)
+
+// SMTPConfig configures an SMTPMailer. Username and Password are optional;
+// MailHog, for one, needs neither.
+type SMTPConfig struct {
+	// Addr is the server as host:port, such as "mailhog:1025".
+	Addr     string
+	Username string
+	Password string
+	// From is the sender, such as "Chat <no-reply@example.com>".
+	From string
+}
+
+// SMTPMailer sends messages through an SMTP server, upgrading the
+// connection with STARTTLS when the server offers it.
+type SMTPMailer struct {
+	config SMTPConfig
+	sender string
+}
+
+func NewSMTPMailer(config SMTPConfig) (*SMTPMailer, error) {
+	sender, err := parseAddress(config.From)
+	if err != nil {
+		return nil, err
+	}
+	return &SMTPMailer{config: config, sender: sender}, nil
+}
+
+func (m *SMTPMailer) Name() string {
+	return "smtp (" + m.config.Addr + ")"
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message *Message) error {
+	recipient, err := parseAddress(message.To)
+	if err != nil {
+		return err
+	}
+	email, err := compose(m.config.From, message)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", m.config.Addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	host, _, err := net.SplitHostPort(m.config.Addr)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(m.sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(recipient); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(email); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// parseAddress returns the bare address of a header address such as
+// "Chat <no-reply@example.com>".
+func parseAddress(address string) (string, error) {
+	parsed, err := netmail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Address, nil
+}
\ No newline at end of file
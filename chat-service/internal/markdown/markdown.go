@@ -0,0 +1,346 @@
+// Package markdown parses the Markdown subset supported in chat messages
+// into plain text plus a list of formatting entities. Clients render the
+// entities themselves, so no markup from the sender ever reaches them as HTML.
+package markdown
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Entity types.
+const (
+	EntityBold   = "bold"
+	EntityItalic = "italic"
+	EntityCode   = "code"
+	EntityPre    = "pre"
+	EntityLink   = "link"
+	EntityQuote  = "quote"
+)
+
+// Entity formats a span of Document.Text. Offset and Length count Unicode
+// code points.
+type Entity struct {
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	URL      string `json:"url,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// Document is a parsed message.
+type Document struct {
+	// Text is the message with all markup removed.
+	Text     string
+	Entities []Entity
+}
+
+// allowedSchemes are the link schemes kept as link entities. Links with any
+// other scheme, such as javascript:, keep their text but lose the link.
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// Parse parses **bold**, *italic* or _italic_, `code`, fenced code blocks,
+// [links](https://example.com) and > quotes. Anything that does not form
+// valid markup is kept as literal text.
+func Parse(source string) Document {
+	p := &parser{}
+
+	lines := strings.Split(sanitize(source), "\n")
+	for i := 0; i < len(lines); {
+		if language, ok := openingFence(lines[i]); ok {
+			if end := closingFence(lines, i+1); end != -1 {
+				p.pre(strings.Join(lines[i+1:end], "\n"), language)
+				i = end + 1
+				continue
+			}
+		}
+
+		if isQuote(lines[i]) {
+			end := i
+			for end < len(lines) && isQuote(lines[end]) {
+				end++
+			}
+			p.quote(lines[i:end])
+			i = end
+			continue
+		}
+
+		p.newLine()
+		p.inline([]rune(lines[i]))
+		i++
+	}
+
+	sort.SliceStable(p.entities, func(i, j int) bool {
+		if p.entities[i].Offset != p.entities[j].Offset {
+			return p.entities[i].Offset < p.entities[j].Offset
+		}
+		return p.entities[i].Length > p.entities[j].Length
+	})
+
+	return Document{Text: string(p.text), Entities: p.entities}
+}
+
+// PlainText returns the message without markup, for notifications and other
+// places that cannot render entities.
+func PlainText(source string) string {
+	return Parse(source).Text
+}
+
+type parser struct {
+	text     []rune
+	entities []Entity
+	lines    int
+}
+
+// newLine separates output lines.
+func (p *parser) newLine() {
+	if p.lines > 0 {
+		p.text = append(p.text, '\n')
+	}
+	p.lines++
+}
+
+func (p *parser) add(entityType string, start int, entity Entity) {
+	length := len(p.text) - start
+	if length == 0 {
+		return
+	}
+	entity.Type = entityType
+	entity.Offset = start
+	entity.Length = length
+	p.entities = append(p.entities, entity)
+}
+
+func (p *parser) pre(code, language string) {
+	p.newLine()
+	start := len(p.text)
+	p.text = append(p.text, []rune(code)...)
+	p.add(EntityPre, start, Entity{Language: language})
+}
+
+func (p *parser) quote(lines []string) {
+	p.newLine()
+	start := len(p.text)
+	for i, line := range lines {
+		if i > 0 {
+			p.text = append(p.text, '\n')
+		}
+		line = strings.TrimPrefix(line, ">")
+		line = strings.TrimPrefix(line, " ")
+		p.inline([]rune(line))
+	}
+	p.add(EntityQuote, start, Entity{})
+}
+
+func (p *parser) inline(src []rune) {
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\\' && i+1 < len(src) && isMarker(src[i+1]):
+			p.text = append(p.text, src[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			// Code spans are literal, markup inside them is not parsed
+			if end := indexRune(src, '`', i+1); end > i+1 {
+				start := len(p.text)
+				p.text = append(p.text, src[i+1:end]...)
+				p.add(EntityCode, start, Entity{})
+				i = end + 1
+				continue
+			}
+
+		case c == '*' && i+1 < len(src) && src[i+1] == '*':
+			if end := indexDouble(src, '*', i+2); end > i+2 {
+				p.wrap(EntityBold, src[i+2:end])
+				i = end + 2
+				continue
+			}
+
+		case (c == '*' || c == '_') && opensEmphasis(src, i):
+			if end := closingEmphasis(src, c, i+1); end != -1 {
+				p.wrap(EntityItalic, src[i+1:end])
+				i = end + 1
+				continue
+			}
+
+		case c == '[':
+			if textEnd, urlEnd, ok := link(src, i); ok {
+				start := len(p.text)
+				p.inline(src[i+1 : textEnd])
+				if target := strings.TrimSpace(string(src[textEnd+2 : urlEnd])); safeURL(target) {
+					p.add(EntityLink, start, Entity{URL: target})
+				}
+				i = urlEnd + 1
+				continue
+			}
+		}
+
+		p.text = append(p.text, c)
+		i++
+	}
+}
+
+// wrap parses inner and marks the resulting text with an entity.
+func (p *parser) wrap(entityType string, inner []rune) {
+	start := len(p.text)
+	p.inline(inner)
+	p.add(entityType, start, Entity{})
+}
+
+// sanitize normalises line endings and drops control characters.
+func sanitize(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, source)
+}
+
+func openingFence(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "```") {
+		return "", false
+	}
+
+	language := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+	for _, r := range language {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#-_.", r) {
+			return "", true
+		}
+	}
+	return language, true
+}
+
+func closingFence(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "```" {
+			return i
+		}
+	}
+	return -1
+}
+
+func isQuote(line string) bool {
+	return strings.HasPrefix(line, ">")
+}
+
+func isMarker(r rune) bool {
+	return strings.ContainsRune("\\`*_[]()>", r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// opensEmphasis requires the marker to start a word, so snake_case and
+// 2*3*4 stay literal.
+func opensEmphasis(src []rune, i int) bool {
+	if i+1 >= len(src) || unicode.IsSpace(src[i+1]) {
+		return false
+	}
+	return i == 0 || !isWordRune(src[i-1])
+}
+
+func closingEmphasis(src []rune, marker rune, from int) int {
+	for j := from + 1; j < len(src); j++ {
+		if src[j] == '\\' {
+			j++
+			continue
+		}
+		if src[j] != marker || unicode.IsSpace(src[j-1]) {
+			continue
+		}
+		if j+1 < len(src) && (isWordRune(src[j+1]) || src[j+1] == marker) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+func indexRune(src []rune, r rune, from int) int {
+	for j := from; j < len(src); j++ {
+		if src[j] == r {
+			return j
+		}
+	}
+	return -1
+}
+
+// indexDouble finds the next unescaped pair of r.
+func indexDouble(src []rune, r rune, from int) int {
+	for j := from; j+1 < len(src); j++ {
+		if src[j] == '\\' {
+			j++
+			continue
+		}
+		if src[j] == r && src[j+1] == r {
+			return j
+		}
+	}
+	return -1
+}
+
+// link matches [text](url) starting at i and returns the positions of the
+// closing bracket and the closing parenthesis.
+func link(src []rune, i int) (int, int, bool) {
+	textEnd := -1
+	for j := i + 1; j < len(src); j++ {
+		if src[j] == '\\' {
+			j++
+			continue
+		}
+		if src[j] == '[' {
+			return 0, 0, false
+		}
+		if src[j] == ']' {
+			textEnd = j
+			break
+		}
+	}
+	if textEnd <= i+1 || textEnd+1 >= len(src) || src[textEnd+1] != '(' {
+		return 0, 0, false
+	}
+
+	// URLs may contain balanced parentheses, as in Wikipedia links
+	depth := 0
+	for j := textEnd + 2; j < len(src); j++ {
+		switch {
+		case unicode.IsSpace(src[j]):
+			return 0, 0, false
+		case src[j] == '(':
+			depth++
+		case src[j] == ')' && depth > 0:
+			depth--
+		case src[j] == ')':
+			return textEnd, j, j > textEnd+2
+		}
+	}
+	return 0, 0, false
+}
+
+func safeURL(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if !allowedSchemes[scheme] {
+		return false
+	}
+	if scheme != "mailto" && parsed.Host == "" {
+		return false
+	}
+	return true
+}
\ No newline at end of file
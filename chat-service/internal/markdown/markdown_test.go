@@ -0,0 +1,246 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		text     string
+		entities []Entity
+	}{
+		{
+			name:     "plain text",
+			source:   "hello world",
+			text:     "hello world",
+			entities: nil,
+		},
+		{
+			name:     "bold",
+			source:   "**bold**",
+			text:     "bold",
+			entities: []Entity{{Type: EntityBold, Offset: 0, Length: 4}},
+		},
+		{
+			name:   "italic inside bold",
+			source: "**bold *it* x**",
+			text:   "bold it x",
+			entities: []Entity{
+				{Type: EntityBold, Offset: 0, Length: 9},
+				{Type: EntityItalic, Offset: 5, Length: 2},
+			},
+		},
+		{
+			name:   "bold inside italic",
+			source: "_a **b** c_",
+			text:   "a b c",
+			entities: []Entity{
+				{Type: EntityItalic, Offset: 0, Length: 5},
+				{Type: EntityBold, Offset: 2, Length: 1},
+			},
+		},
+		{
+			name:     "unterminated bold",
+			source:   "**bold",
+			text:     "**bold",
+			entities: nil,
+		},
+		{
+			name:     "unterminated italic",
+			source:   "*italic and _more",
+			text:     "*italic and _more",
+			entities: nil,
+		},
+		{
+			name:     "unterminated code span",
+			source:   "`code",
+			text:     "`code",
+			entities: nil,
+		},
+		{
+			name:     "unterminated link",
+			source:   "[text](https://example.com",
+			text:     "[text](https://example.com",
+			entities: nil,
+		},
+		{
+			name:     "empty markers",
+			source:   "**** ``",
+			text:     "**** ``",
+			entities: nil,
+		},
+		{
+			name:     "intraword underscores",
+			source:   "snake_case_name and 2*3*4",
+			text:     "snake_case_name and 2*3*4",
+			entities: nil,
+		},
+		{
+			name:     "escaped markers",
+			source:   `\*not italic\* \[x\]`,
+			text:     "*not italic* [x]",
+			entities: nil,
+		},
+		{
+			name:     "markup inside code span is literal",
+			source:   "`**x** [a](https://a.example)`",
+			text:     "**x** [a](https://a.example)",
+			entities: []Entity{{Type: EntityCode, Offset: 0, Length: 28}},
+		},
+		{
+			name:     "fenced code block",
+			source:   "```go\nfmt.Println(\"*hi*\")\n```",
+			text:     "fmt.Println(\"*hi*\")",
+			entities: []Entity{{Type: EntityPre, Offset: 0, Length: 19, Language: "go"}},
+		},
+		{
+			name:     "unterminated fence",
+			source:   "```go\ncode",
+			text:     "```go\ncode",
+			entities: nil,
+		},
+		{
+			name:     "fence language with markup is dropped",
+			source:   "```<script>\nx\n```",
+			text:     "x",
+			entities: []Entity{{Type: EntityPre, Offset: 0, Length: 1}},
+		},
+		{
+			name:   "quote with inline markup",
+			source: "> hi\n> **b**\nafter",
+			text:   "hi\nb\nafter",
+			entities: []Entity{
+				{Type: EntityQuote, Offset: 0, Length: 4},
+				{Type: EntityBold, Offset: 3, Length: 1},
+			},
+		},
+		{
+			name:     "offsets count code points after an emoji",
+			source:   "😀 **hi**",
+			text:     "😀 hi",
+			entities: []Entity{{Type: EntityBold, Offset: 2, Length: 2}},
+		},
+		{
+			name:     "offsets count each code point of a modified emoji",
+			source:   "👍🏽 [go](https://go.dev)",
+			text:     "👍🏽 go",
+			entities: []Entity{{Type: EntityLink, Offset: 3, Length: 2, URL: "https://go.dev"}},
+		},
+		{
+			name:     "length counts code points inside the entity",
+			source:   "*héllo 🌍*",
+			text:     "héllo 🌍",
+			entities: []Entity{{Type: EntityItalic, Offset: 0, Length: 7}},
+		},
+		{
+			name:     "control characters are dropped",
+			source:   "a\x00b\x1b[31mc\r\nd",
+			text:     "ab[31mc\nd",
+			entities: nil,
+		},
+		{
+			name:     "https link",
+			source:   "see [docs](https://example.com/a_(b))",
+			text:     "see docs",
+			entities: []Entity{{Type: EntityLink, Offset: 4, Length: 4, URL: "https://example.com/a_(b)"}},
+		},
+		{
+			name:     "mailto link",
+			source:   "[mail me](mailto:a@example.com)",
+			text:     "mail me",
+			entities: []Entity{{Type: EntityLink, Offset: 0, Length: 7, URL: "mailto:a@example.com"}},
+		},
+		{
+			name:   "formatted link text",
+			source: "[**b**](https://example.com)",
+			text:   "b",
+			entities: []Entity{
+				{Type: EntityBold, Offset: 0, Length: 1},
+				{Type: EntityLink, Offset: 0, Length: 1, URL: "https://example.com"},
+			},
+		},
+		{
+			name:     "javascript link",
+			source:   "[click](javascript:alert(1))",
+			text:     "click",
+			entities: nil,
+		},
+		{
+			name:     "javascript link in mixed case",
+			source:   "[click](JavaScript:alert(document.cookie))",
+			text:     "click",
+			entities: nil,
+		},
+		{
+			name:     "data link",
+			source:   "[click](data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==)",
+			text:     "click",
+			entities: nil,
+		},
+		{
+			name:     "vbscript link",
+			source:   "[click](vbscript:msgbox)",
+			text:     "click",
+			entities: nil,
+		},
+		{
+			name:     "scheme-relative link",
+			source:   "[click](//evil.example)",
+			text:     "click",
+			entities: nil,
+		},
+		{
+			name:     "http link without a host",
+			source:   "[click](https:evil)",
+			text:     "click",
+			entities: nil,
+		},
+		{
+			name:     "link with a space is literal",
+			source:   "[click](https://a.example javascript:x)",
+			text:     "[click](https://a.example javascript:x)",
+			entities: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Parse(tt.source)
+			if doc.Text != tt.text {
+				t.Errorf("text = %q, want %q", doc.Text, tt.text)
+			}
+			if !reflect.DeepEqual(doc.Entities, tt.entities) {
+				t.Errorf("entities = %+v, want %+v", doc.Entities, tt.entities)
+			}
+		})
+	}
+}
+
+func TestEntitiesStayInsideText(t *testing.T) {
+	sources := []string{
+		"**a *b _c_ d* e**",
+		"> **unterminated\n> [x](https://a.example)",
+		"🎉*🎉*🎉 `🎉` [🎉](https://a.example)",
+		"```\n🎉\n```\n**🎉**",
+	}
+
+	for _, source := range sources {
+		doc := Parse(source)
+		length := len([]rune(doc.Text))
+		for _, entity := range doc.Entities {
+			if entity.Offset < 0 || entity.Length <= 0 || entity.Offset+entity.Length > length {
+				t.Errorf("Parse(%q): entity %+v outside text of %d code points", source, entity, length)
+			}
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText("**hi** [there](javascript:alert(1))")
+	if want := "hi there"; got != want {
+		t.Errorf("PlainText = %q, want %q", got, want)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Bookmark is a message a user saved for later, optionally with a note and
+// in a folder. Bookmarks outlive their messages: once a message is deleted
+// its bookmarks are listed without it, so users can still see and remove them.
+type Bookmark struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID    string    `gorm:"not null;uniqueIndex:idx_bookmark_user_message;index:idx_bookmark_user_folder" json:"user_id"`
+	MessageID string    `gorm:"type:uuid;not null;uniqueIndex:idx_bookmark_user_message" json:"message_id"`
+	RoomID    string    `gorm:"not null;index" json:"room_id"`
+	Folder    string    `gorm:"not null;default:'';index:idx_bookmark_user_folder" json:"folder,omitempty"`
+	Note      string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Message is the bookmarked message as it is now, and RoomName the name
+	// of its room. Message is nil once the message is gone or the user can
+	// no longer read its room.
+	Message  *Message `gorm:"-" json:"message,omitempty"`
+	RoomName string   `gorm:"-" json:"room_name,omitempty"`
+}
+
+// Available reports whether the bookmarked message can still be shown.
+func (b *Bookmark) Available() bool {
+	return b.Message != nil
+}
+
+// BookmarkFolder is a folder of a user's bookmarks. Bookmarks outside any
+// folder are counted under the empty name.
+type BookmarkFolder struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
\ No newline at end of file
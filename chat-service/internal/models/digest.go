@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DigestEntry is a notification the user was offline for, kept for their
+// next email digest. Entries of a room are dropped once the user reads or
+// posts in it.
+type DigestEntry struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID    string    `gorm:"not null;index" json:"user_id"`
+	RoomID    string    `gorm:"not null" json:"room_id"`
+	MessageID string    `gorm:"type:uuid;not null" json:"message_id"`
+	Mention   bool      `gorm:"not null;default:false" json:"mention"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DigestState tracks when a user was last seen online and last sent a
+// digest. The digest worker leases the row while it sends the user's digest.
+type DigestState struct {
+	UserID     string     `gorm:"primaryKey" json:"user_id"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	LeaseUntil *time.Time `json:"-"`
+}
\ No newline at end of file
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ExportFormatJSONL = "jsonl"
+	ExportFormatCSV   = "csv"
+	ExportFormatHTML  = "html"
+)
+
+var ExportFormats = []string{ExportFormatJSONL, ExportFormatCSV, ExportFormatHTML}
+
+func IsExportFormat(format string) bool {
+	return slices.Contains(ExportFormats, format)
+}
+
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+	ExportStatusExpired   = "expired"
+)
+
+// ExportJob writes a room's history to a file in the background. While a
+// worker holds it in the running state, LeaseUntil tells other replicas when
+// they may take it over. The file is removed once ExpiresAt passes.
+type ExportJob struct {
+	ID          string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID      string `gorm:"not null;index" json:"room_id"`
+	RequestedBy string `gorm:"not null" json:"requested_by"`
+	Format      string `gorm:"not null" json:"format"`
+	Status      string `gorm:"not null;default:pending;index" json:"status"`
+	// TotalMessages is counted when the export starts; ExportedMessages
+	// tracks progress towards it.
+	TotalMessages    int64      `gorm:"not null;default:0" json:"total_messages"`
+	ExportedMessages int64      `gorm:"not null;default:0" json:"exported_messages"`
+	FileName         string     `json:"file_name,omitempty"`
+	FileSize         int64      `gorm:"not null;default:0" json:"file_size"`
+	Attempts         int        `gorm:"not null;default:0" json:"attempts"`
+	LeaseUntil       *time.Time `json:"-"`
+	Error            string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt        *time.Time `json:"started_at,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func (j *ExportJob) BeforeCreate(tx *gorm.DB) error {
+	j.CreatedAt = time.Now()
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+func (j *ExportJob) BeforeUpdate(tx *gorm.DB) error {
+	j.UpdatedAt = time.Now()
+	return nil
+}
+
+// Downloadable reports whether the export's file can be downloaded.
+func (j *ExportJob) Downloadable(now time.Time) bool {
+	return j.Status == ExportStatusCompleted && (j.ExpiresAt == nil || now.Before(*j.ExpiresAt))
+}
\ No newline at end of file
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IncomingWebhook lets external systems post to a room with a token instead
+// of a user JWT. Only a SHA-256 hash of the token is stored. Messages are
+// sent as the webhook's bot user, which is a regular room member, so mutes,
+// slow mode and send rate limits apply to each token separately.
+type IncomingWebhook struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID     string     `gorm:"not null;index" json:"room_id"`
+	Name       string     `gorm:"not null" json:"name"`
+	TokenHash  string     `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedBy  string     `gorm:"not null" json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (iw *IncomingWebhook) BeforeCreate(tx *gorm.DB) error {
+	iw.CreatedAt = time.Now()
+	return nil
+}
+
+const botUserIDPrefix = "bot:"
+
+// BotUserID is the user ID the webhook's messages are sent as.
+func (iw *IncomingWebhook) BotUserID() string {
+	return botUserIDPrefix + iw.ID
+}
+
+// IsBotUserID reports whether userID belongs to an incoming webhook.
+func IsBotUserID(userID string) bool {
+	return strings.HasPrefix(userID, botUserIDPrefix)
+}
+
+func (iw *IncomingWebhook) IsRevoked() bool {
+	return iw.RevokedAt != nil
+}
\ No newline at end of file
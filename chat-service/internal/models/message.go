@@ -3,19 +3,162 @@ package models
 import (
 	"time"
 
+	"chat-service/internal/markdown"
 	"gorm.io/gorm"
 )
 
+const (
+	MessageTypeText   = "text"
+	MessageTypePoll   = "poll"
+	MessageTypeAction = "action"
+	// MessageTypeEphemeral messages are shown only to one user and are
+	// never stored.
+	MessageTypeEphemeral = "ephemeral"
+)
+
 type Message struct {
-	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
-	RoomID    string    `gorm:"not null;index" json:"room_id"`
-	UserID    string    `gorm:"not null" json:"user_id"`
-	Username  string    `gorm:"not null" json:"username"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID          string     `gorm:"not null;index" json:"room_id"`
+	Seq             int64      `gorm:"not null;default:0" json:"seq"`
+	UserID          string     `gorm:"not null" json:"user_id"`
+	Username        string     `gorm:"not null" json:"username"`
+	Content         string     `gorm:"type:text;not null" json:"content"`
+	ClientMessageID string     `gorm:"type:varchar(64)" json:"client_message_id,omitempty"`
+	Type            string     `gorm:"not null;default:text" json:"type"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EditedAt        *time.Time `json:"edited_at,omitempty"`
+
+	// ExpiresAt is when a disappearing message expires. Expired messages are
+	// never returned, and the reaper deletes them soon after.
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at,omitempty"`
+
+	// ParentID is the first message of the thread this message replies to.
+	ParentID *string `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+
+	// Reference is set on forwarded messages and quoted replies.
+	Reference MessageReference `gorm:"embedded;embeddedPrefix:reference_" json:"reference"`
+
+	// ExternalID identifies messages imported from another chat system, so
+	// that re-running an import skips them. It is unique within a room.
+	ExternalID string `gorm:"type:varchar(191)" json:"external_id,omitempty"`
+
+	// PlainText and Entities are Content parsed as Markdown. Entity offsets
+	// refer to PlainText.
+	PlainText string            `gorm:"type:text" json:"plain_text"`
+	Entities  []markdown.Entity `gorm:"type:jsonb;serializer:json" json:"entities,omitempty"`
+
+	// Poll is set on messages of type MessageTypePoll.
+	Poll *Poll `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"poll,omitempty"`
+
+	// Reactions is only populated when messages are listed.
+	Reactions []ReactionCount `gorm:"-" json:"reactions,omitempty"`
+
+	// Duplicate is set when a retried send returned the original message.
+	Duplicate bool `gorm:"-" json:"-"`
 }
 
 func (m *Message) BeforeCreate(tx *gorm.DB) error {
-	m.CreatedAt = time.Now()
+	// Imported messages keep their original time
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if m.Type == "" {
+		m.Type = MessageTypeText
+	}
+	if m.PlainText == "" {
+		m.Render()
+	}
 	return nil
+}
+
+const (
+	ReferenceForward = "forward"
+	ReferenceQuote   = "quote"
+)
+
+// referenceExcerptLength is how many characters of the original a reference
+// shows.
+const referenceExcerptLength = 200
+
+// MessageReference points from a forwarded message or a quoted reply to the
+// original. The original's room and author are kept with the reference, so
+// it still tells where it came from once the original is deleted.
+type MessageReference struct {
+	Type      string     `gorm:"type:varchar(16)" json:"type,omitempty"`
+	MessageID *string    `gorm:"type:uuid;index" json:"message_id,omitempty"`
+	RoomID    string     `json:"room_id,omitempty"`
+	UserID    string     `json:"user_id,omitempty"`
+	Username  string     `json:"username,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Content and Available are looked up whenever the message is read.
+	// Content is a plain text excerpt of the original, empty once the
+	// original has been deleted.
+	Content   string `gorm:"-" json:"content,omitempty"`
+	Available bool   `gorm:"-" json:"available"`
+}
+
+// NewMessageReference references the original message.
+func NewMessageReference(referenceType string, original *Message) MessageReference {
+	messageID := original.ID
+	createdAt := original.CreatedAt
+	reference := MessageReference{
+		Type:      referenceType,
+		MessageID: &messageID,
+		RoomID:    original.RoomID,
+		UserID:    original.UserID,
+		Username:  original.Username,
+		CreatedAt: &createdAt,
+	}
+	reference.SetOriginal(original)
+	return reference
+}
+
+// IsSet reports whether the message references another one.
+func (r *MessageReference) IsSet() bool {
+	return r.Type != ""
+}
+
+// SetOriginal fills in the excerpt of the original, or marks the reference
+// unavailable if original is nil.
+func (r *MessageReference) SetOriginal(original *Message) {
+	if original == nil {
+		r.Content = ""
+		r.Available = false
+		return
+	}
+
+	text := original.PlainText
+	if text == "" {
+		text = original.Content
+	}
+	if runes := []rune(text); len(runes) > referenceExcerptLength {
+		text = string(runes[:referenceExcerptLength]) + "…"
+	}
+	r.Content = text
+	r.Available = true
+}
+
+// MessageIdempotencyKey remembers which message a client-generated ID produced,
+// so that retried sends return the original message instead of a duplicate.
+type MessageIdempotencyKey struct {
+	RoomID          string    `gorm:"primaryKey" json:"room_id"`
+	UserID          string    `gorm:"primaryKey" json:"user_id"`
+	ClientMessageID string    `gorm:"primaryKey;type:varchar(64)" json:"client_message_id"`
+	MessageID       *string   `gorm:"type:uuid" json:"message_id"`
+	CreatedAt       time.Time `gorm:"not null;index" json:"created_at"`
+}
+
+// Render parses Content into PlainText and Entities. Polls keep their
+// content as plain text; every other type carries formatting.
+func (m *Message) Render() {
+	if m.Type == MessageTypePoll {
+		m.PlainText = m.Content
+		m.Entities = nil
+		return
+	}
+
+	doc := markdown.Parse(m.Content)
+	m.PlainText = doc.Text
+	m.Entities = doc.Entities
 }
\ No newline at end of file
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MessageRevisionEdit   = "edit"
+	MessageRevisionDelete = "delete"
+	// MessageRevisionExpire records a message whose time-to-live ran out.
+	// It keeps no content, since the message was meant to disappear.
+	MessageRevisionExpire = "expire"
+)
+
+// MessageRevision records an edit or deletion of a message together with the
+// content it replaced, so room exports can show a message's full history.
+// Revisions of deleted messages are all that remains of them.
+type MessageRevision struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	MessageID string `gorm:"type:uuid;not null;index" json:"message_id"`
+	RoomID    string `gorm:"not null;index:idx_message_revisions_room_seq" json:"room_id"`
+	// Seq is the message's sequence number, for ordering deleted messages
+	// among the remaining ones.
+	Seq      int64  `gorm:"not null;index:idx_message_revisions_room_seq" json:"seq"`
+	Action   string `gorm:"not null" json:"action"`
+	UserID   string `gorm:"not null" json:"user_id"`
+	Username string `gorm:"not null" json:"username"`
+	// Content is the message's content before the edit or deletion.
+	Content          string    `gorm:"type:text;not null" json:"content"`
+	MessageType      string    `gorm:"not null" json:"message_type"`
+	ActorID          string    `gorm:"not null" json:"actor_id"`
+	MessageCreatedAt time.Time `gorm:"not null" json:"message_created_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (mr *MessageRevision) BeforeCreate(tx *gorm.DB) error {
+	mr.CreatedAt = time.Now()
+	return nil
+}
+
+// NewMessageRevision records the message as it is before the action.
+func NewMessageRevision(message *Message, action, actorID string) *MessageRevision {
+	return &MessageRevision{
+		MessageID:        message.ID,
+		RoomID:           message.RoomID,
+		Seq:              message.Seq,
+		Action:           action,
+		UserID:           message.UserID,
+		Username:         message.Username,
+		Content:          message.Content,
+		MessageType:      message.Type,
+		ActorID:          actorID,
+		MessageCreatedAt: message.CreatedAt,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ModerationSourceFilter = "filter"
+	ModerationSourceReport = "report"
+)
+
+const (
+	ModerationStatusPending  = "pending"
+	ModerationStatusClaimed  = "claimed"
+	ModerationStatusResolved = "resolved"
+)
+
+// Resolutions a moderator can apply to a queue item. Each is also recorded as
+// a ModerationAction.
+const (
+	ResolutionDismiss       = "dismiss"
+	ResolutionDeleteMessage = "delete_message"
+	ResolutionMute          = "mute"
+	ResolutionBan           = "ban"
+)
+
+// ModerationActionClaim is recorded when a moderator claims a queue item.
+const ModerationActionClaim = "claim"
+
+// Actions moderators take directly with slash commands.
+const (
+	ModerationActionKick   = "kick"
+	ModerationActionUnmute = "unmute"
+)
+
+// ModerationItem is an entry in the moderation review queue, raised either by
+// a moderation filter or by a user report. Content keeps a copy of the
+// message so the item stays reviewable after the message is deleted.
+type ModerationItem struct {
+	ID             string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID         string     `gorm:"not null;index" json:"room_id"`
+	MessageID      *string    `gorm:"type:uuid;index" json:"message_id"`
+	AuthorID       string     `gorm:"not null" json:"author_id"`
+	Content        string     `gorm:"type:text" json:"content"`
+	Source         string     `gorm:"not null" json:"source"`
+	Filter         string     `json:"filter,omitempty"`
+	ReporterID     string     `gorm:"index" json:"reporter_id,omitempty"`
+	Reason         string     `gorm:"not null" json:"reason"`
+	Detail         string     `gorm:"type:text" json:"detail"`
+	Status         string     `gorm:"not null;default:pending;index" json:"status"`
+	ClaimedBy      string     `json:"claimed_by,omitempty"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty"`
+	ResolvedBy     string     `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolutionNote string     `gorm:"type:text" json:"resolution_note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Message        *Message   `gorm:"foreignKey:MessageID;constraint:OnDelete:SET NULL" json:"message,omitempty"`
+}
+
+func (mi *ModerationItem) BeforeCreate(tx *gorm.DB) error {
+	mi.CreatedAt = time.Now()
+	if mi.Status == "" {
+		mi.Status = ModerationStatusPending
+	}
+	return nil
+}
+
+// IsOpen reports whether the item still awaits a resolution.
+func (mi *ModerationItem) IsOpen() bool {
+	return mi.Status != ModerationStatusResolved
+}
+
+// ModerationAction is the audit record of a moderator action. DurationSeconds
+// is only set for mutes.
+type ModerationAction struct {
+	ID              string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID          string    `gorm:"not null;index" json:"room_id"`
+	ItemID          *string   `gorm:"type:uuid;index" json:"item_id,omitempty"`
+	ModeratorID     string    `gorm:"not null" json:"moderator_id"`
+	Action          string    `gorm:"not null" json:"action"`
+	TargetUserID    string    `json:"target_user_id,omitempty"`
+	MessageID       string    `json:"message_id,omitempty"`
+	Note            string    `gorm:"type:text" json:"note,omitempty"`
+	DurationSeconds int64     `json:"duration_seconds,omitempty"`
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`
+}
+
+func (ma *ModerationAction) BeforeCreate(tx *gorm.DB) error {
+	ma.CreatedAt = time.Now()
+	return nil
+}
+
+// RoomBan keeps a user out of a room.
+type RoomBan struct {
+	RoomID    string    `gorm:"primaryKey" json:"room_id"`
+	UserID    string    `gorm:"primaryKey" json:"user_id"`
+	BannedBy  string    `gorm:"not null" json:"banned_by"`
+	Reason    string    `gorm:"type:text" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// NotificationSettings are a user's notification settings across rooms.
+// While do-not-disturb is on, the user gets no notifications between
+// DNDStart and DNDEnd, times of day as "15:04" in the user's time zone. A
+// window whose end is before its start runs past midnight. DigestOptOut
+// stops the email digests of unread messages.
+type NotificationSettings struct {
+	UserID       string    `gorm:"primaryKey" json:"user_id"`
+	DNDEnabled   bool      `gorm:"not null;default:false" json:"dnd_enabled"`
+	DNDStart     string    `gorm:"type:varchar(5)" json:"dnd_start,omitempty"`
+	DNDEnd       string    `gorm:"type:varchar(5)" json:"dnd_end,omitempty"`
+	TimeZone     string    `json:"time_zone,omitempty"`
+	DigestOptOut bool      `gorm:"not null;default:false" json:"digest_opt_out"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// InDoNotDisturb reports whether the user's do-not-disturb window covers
+// the given time. Unknown time zones are taken as UTC.
+func (ns *NotificationSettings) InDoNotDisturb(now time.Time) bool {
+	if !ns.DNDEnabled {
+		return false
+	}
+	start, okStart := ParseTimeOfDay(ns.DNDStart)
+	end, okEnd := ParseTimeOfDay(ns.DNDEnd)
+	if !okStart || !okEnd || start == end {
+		return false
+	}
+
+	location, err := time.LoadLocation(ns.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// ParseTimeOfDay parses a time of day such as "22:30" into minutes after
+// midnight.
+func ParseTimeOfDay(value string) (int, bool) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
\ No newline at end of file
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PinnedMessage struct {
+	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID    string    `gorm:"not null;uniqueIndex:idx_pinned_room_message" json:"room_id"`
+	MessageID string    `gorm:"type:uuid;not null;uniqueIndex:idx_pinned_room_message" json:"message_id"`
+	PinnedBy  string    `gorm:"not null" json:"pinned_by"`
+	PinnedAt  time.Time `json:"pinned_at"`
+	Message   Message   `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"message"`
+}
+
+func (pm *PinnedMessage) BeforeCreate(tx *gorm.DB) error {
+	pm.PinnedAt = time.Now()
+	return nil
+}
\ No newline at end of file
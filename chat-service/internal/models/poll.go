@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Poll is the structured part of a message of type MessageTypePoll. Vote
+// counts are kept on the options and updated in the same transaction as the
+// votes themselves.
+type Poll struct {
+	ID             string       `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	MessageID      string       `gorm:"type:uuid;not null;uniqueIndex" json:"message_id"`
+	RoomID         string       `gorm:"not null;index" json:"room_id"`
+	Question       string       `gorm:"type:text;not null" json:"question"`
+	MultipleChoice bool         `gorm:"not null;default:false" json:"multiple_choice"`
+	Anonymous      bool         `gorm:"not null;default:false" json:"anonymous"`
+	ClosesAt       *time.Time   `json:"closes_at,omitempty"`
+	ClosedAt       *time.Time   `json:"closed_at,omitempty"`
+	TotalVoters    int          `gorm:"not null;default:0" json:"total_voters"`
+	CreatedBy      string       `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Options        []PollOption `gorm:"foreignKey:PollID;constraint:OnDelete:CASCADE" json:"options"`
+	Votes          []PollVote   `gorm:"foreignKey:PollID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (p *Poll) BeforeCreate(tx *gorm.DB) error {
+	p.CreatedAt = time.Now()
+	return nil
+}
+
+// IsClosed reports whether the poll stopped accepting votes, either because
+// it was closed or because its close time has passed.
+func (p *Poll) IsClosed(now time.Time) bool {
+	if p.ClosedAt != nil {
+		return true
+	}
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
+
+type PollOption struct {
+	ID        string `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	PollID    string `gorm:"type:uuid;not null;index" json:"poll_id"`
+	Position  int    `gorm:"not null" json:"position"`
+	Text      string `gorm:"not null" json:"text"`
+	VoteCount int    `gorm:"not null;default:0" json:"vote_count"`
+
+	// Voters is only populated for polls that are not anonymous.
+	Voters []string `gorm:"-" json:"voters,omitempty"`
+}
+
+type PollVote struct {
+	PollID    string    `gorm:"primaryKey;type:uuid" json:"poll_id"`
+	UserID    string    `gorm:"primaryKey;index" json:"user_id"`
+	OptionID  string    `gorm:"primaryKey;type:uuid" json:"option_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (pv *PollVote) BeforeCreate(tx *gorm.DB) error {
+	pv.CreatedAt = time.Now()
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PushDevice is a device registered for push notifications. Platform is
+// "fcm" or "apns". A token belongs to the user who registered it last.
+type PushDevice struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	UserID     string     `gorm:"not null;index" json:"user_id"`
+	Platform   string     `gorm:"type:varchar(16);not null" json:"platform"`
+	Token      string     `gorm:"type:text;not null;uniqueIndex" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastPushAt *time.Time `json:"last_push_at,omitempty"`
+}
+
+// PushJob is a notification of a new message waiting to be pushed to one
+// device. A device's jobs are pushed together, as one notification, once the
+// oldest of them has waited out the batching window. Jobs are leased like
+// webhook deliveries while they are being sent.
+type PushJob struct {
+	ID         string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	DeviceID   string     `gorm:"type:uuid;not null;index" json:"device_id"`
+	RoomID     string     `gorm:"not null" json:"room_id"`
+	MessageID  string     `gorm:"type:uuid;not null" json:"message_id"`
+	Title      string     `gorm:"not null" json:"title"`
+	Body       string     `gorm:"type:text;not null" json:"body"`
+	Mention    bool       `gorm:"not null;default:false" json:"mention"`
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"`
+	LeaseUntil *time.Time `json:"-"`
+	CreatedAt  time.Time  `gorm:"not null;index" json:"created_at"`
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SendRateBucket is a per-user token bucket shared by all chat-service
+// replicas.
+type SendRateBucket struct {
+	UserID    string    `gorm:"primaryKey" json:"user_id"`
+	Tokens    float64   `gorm:"not null" json:"tokens"`
+	UpdatedAt time.Time `gorm:"not null;autoUpdateTime:false" json:"updated_at"`
+}
+
+// SlowModeEntry records when a user last sent a message to a slow-mode room.
+type SlowModeEntry struct {
+	RoomID     string    `gorm:"primaryKey" json:"room_id"`
+	UserID     string    `gorm:"primaryKey" json:"user_id"`
+	LastSentAt time.Time `gorm:"not null" json:"last_sent_at"`
+}
\ No newline at end of file
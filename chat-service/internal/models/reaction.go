@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reaction is an emoji a user added to a message. Emoji is the short name
+// without colons, such as "thumbsup".
+type Reaction struct {
+	MessageID string    `gorm:"primaryKey;type:uuid" json:"message_id"`
+	UserID    string    `gorm:"primaryKey" json:"user_id"`
+	Emoji     string    `gorm:"primaryKey;type:varchar(64)" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+	Message   *Message  `gorm:"foreignKey:MessageID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (r *Reaction) BeforeCreate(tx *gorm.DB) error {
+	// Imported reactions keep their original time
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// ReactionCount sums up the reactions to a message with one emoji.
+type ReactionCount struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []string `json:"user_ids"`
+}
\ No newline at end of file
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RetentionDefault defers to the globally configured policy.
+	RetentionDefault  = ""
+	RetentionForever  = "forever"
+	RetentionDays     = "days"
+	RetentionMessages = "messages"
+)
+
+const (
+	PurgeActionDelete  = "delete"
+	PurgeActionArchive = "archive"
+)
+
+// RetentionPolicy describes how long messages are kept in a room.
+type RetentionPolicy struct {
+	Type  string `json:"type"`
+	Value int    `json:"value"`
+}
+
+// ParseRetentionPolicy parses policies in the "days:30" or "messages:1000" form.
+// An empty string or "forever" keeps messages indefinitely.
+func ParseRetentionPolicy(value string) (RetentionPolicy, error) {
+	if value == "" || value == RetentionForever {
+		return RetentionPolicy{Type: RetentionForever}, nil
+	}
+
+	kind, amount, ok := strings.Cut(value, ":")
+	if !ok {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q", value)
+	}
+
+	n, err := strconv.Atoi(amount)
+	if err != nil {
+		return RetentionPolicy{}, fmt.Errorf("invalid retention policy %q: %w", value, err)
+	}
+
+	policy := RetentionPolicy{Type: kind, Value: n}
+	if err := policy.Validate(); err != nil {
+		return RetentionPolicy{}, err
+	}
+	return policy, nil
+}
+
+func (p RetentionPolicy) Validate() error {
+	switch p.Type {
+	case RetentionDefault, RetentionForever:
+		return nil
+	case RetentionDays, RetentionMessages:
+		if p.Value <= 0 {
+			return fmt.Errorf("retention value must be positive")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown retention type %q", p.Type)
+}
+
+func (p RetentionPolicy) String() string {
+	if p.Type == RetentionDays || p.Type == RetentionMessages {
+		return fmt.Sprintf("%s:%d", p.Type, p.Value)
+	}
+	return p.Type
+}
+
+// ArchivedMessage keeps a JSON snapshot of a message removed by the retention job.
+type ArchivedMessage struct {
+	ID         string    `gorm:"primaryKey;type:uuid" json:"id"`
+	RoomID     string    `gorm:"not null;index" json:"room_id"`
+	Data       string    `gorm:"type:jsonb;not null" json:"data"`
+	CreatedAt  time.Time `json:"created_at"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// PurgeRecord records a single retention run for a room.
+type PurgeRecord struct {
+	ID              string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID          string     `gorm:"not null;index" json:"room_id"`
+	Policy          string     `gorm:"not null" json:"policy"`
+	Action          string     `gorm:"not null" json:"action"`
+	MessagesRemoved int64      `gorm:"not null" json:"messages_removed"`
+	OldestRemovedAt *time.Time `json:"oldest_removed_at"`
+	NewestRemovedAt *time.Time `json:"newest_removed_at"`
+	RoomCleared     bool       `gorm:"not null;default:false" json:"room_cleared"`
+	StartedAt       time.Time  `json:"started_at"`
+	FinishedAt      time.Time  `json:"finished_at"`
+}
\ No newline at end of file
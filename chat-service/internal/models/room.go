@@ -3,9 +3,14 @@ package models
 import (
 	"time"
 
+	"chat-service/internal/moderation"
 	"gorm.io/gorm"
 )
 
+// DefaultOrganizationID is the organization that rooms created before
+// organizations existed are moved into. auth-service uses the same ID.
+const DefaultOrganizationID = "00000000-0000-0000-0000-000000000001"
+
 type Room struct {
 	ID          string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	Name        string    `gorm:"not null" json:"name"`
@@ -13,10 +18,51 @@ type Room struct {
 	CreatedBy   string    `gorm:"not null" json:"created_by"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// OrgID is the organization the room belongs to. Only its members see
+	// the room.
+	OrgID string `gorm:"type:uuid;index" json:"org_id"`
+
+	// ArchivedAt marks the room as read-only and hides it from the directory.
+	ArchivedAt *time.Time `gorm:"index" json:"archived_at,omitempty"`
+
+	// LastSeq is the sequence number of the newest message in the room.
+	LastSeq int64 `gorm:"not null;default:0" json:"last_seq"`
+
+	RetentionType  string `gorm:"not null;default:''" json:"retention_type"`
+	RetentionValue int    `gorm:"not null;default:0" json:"retention_value"`
+
+	// SlowModeSeconds is the minimum gap between two messages of the same user.
+	SlowModeSeconds int `gorm:"not null;default:0" json:"slow_mode_seconds"`
+
+	// MessageTTLSeconds makes new messages disappear that long after they
+	// are sent; 0 keeps them.
+	MessageTTLSeconds int `gorm:"not null;default:0" json:"message_ttl_seconds"`
+
+	// ModerationFilters overrides the default moderation pipeline when set.
+	ModerationFilters []moderation.FilterConfig `gorm:"type:jsonb;serializer:json" json:"moderation_filters,omitempty"`
+
+	// ExternalID identifies rooms imported from another chat system.
+	ExternalID string `gorm:"type:varchar(191)" json:"external_id,omitempty"`
+
+	// PinnedCount is only populated when explicitly requested.
+	PinnedCount int64 `gorm:"-" json:"pinned_count,omitempty"`
+}
+
+func (r *Room) IsArchived() bool {
+	return r.ArchivedAt != nil
+}
+
+// RetentionPolicy returns the room's own policy, which may defer to the global default.
+func (r *Room) RetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{Type: r.RetentionType, Value: r.RetentionValue}
 }
 
 func (r *Room) BeforeCreate(tx *gorm.DB) error {
-	r.CreatedAt = time.Now()
+	// Imported rooms keep their original time
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
 	r.UpdatedAt = time.Now()
 	return nil
 }
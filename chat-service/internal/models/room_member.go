@@ -6,14 +6,63 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleOwner     = "owner"
+	RoleModerator = "moderator"
+	RoleMember    = "member"
+)
+
+// Notification levels of a room member.
+const (
+	NotifyAll      = "all"
+	NotifyMentions = "mentions"
+	NotifyNone     = "none"
+)
+
 type RoomMember struct {
 	ID        string    `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
 	RoomID    string    `gorm:"not null;index" json:"room_id"`
 	UserID    string    `gorm:"not null" json:"user_id"`
+	Role      string    `gorm:"not null;default:member" json:"role"`
 	JoinedAt  time.Time `json:"joined_at"`
+
+	// MutedUntil stops the member from sending messages until then.
+	MutedUntil *time.Time `json:"muted_until,omitempty"`
+
+	// NotifyLevel is which of the room's messages notify the member, and
+	// NotificationsMutedUntil silences the room for the member until then.
+	NotifyLevel             string     `gorm:"not null;default:all" json:"notify_level"`
+	NotificationsMutedUntil *time.Time `json:"notifications_muted_until,omitempty"`
 }
 
 func (rm *RoomMember) BeforeCreate(tx *gorm.DB) error {
 	rm.JoinedAt = time.Now()
+	if rm.Role == "" {
+		rm.Role = RoleMember
+	}
+	if rm.NotifyLevel == "" {
+		rm.NotifyLevel = NotifyAll
+	}
 	return nil
+}
+
+// CanModerate reports whether the member may perform moderator actions in the room.
+func (rm *RoomMember) CanModerate() bool {
+	return rm.Role == RoleOwner || rm.Role == RoleModerator
+}
+
+// IsMuted reports whether the member may not send messages at the given time.
+func (rm *RoomMember) IsMuted(now time.Time) bool {
+	return rm.MutedUntil != nil && now.Before(*rm.MutedUntil)
+}
+
+// NotificationsMuted reports whether the member silenced the room's
+// notifications at the given time.
+func (rm *RoomMember) NotificationsMuted(now time.Time) bool {
+	return rm.NotificationsMutedUntil != nil && now.Before(*rm.NotificationsMutedUntil)
+}
+
+// IsNotifyLevel reports whether level is a valid notification level.
+func IsNotifyLevel(level string) bool {
+	return level == NotifyAll || level == NotifyMentions || level == NotifyNone
 }
\ No newline at end of file
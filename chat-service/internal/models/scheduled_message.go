@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ScheduledStatusPending  = "pending"
+	ScheduledStatusSending  = "sending"
+	ScheduledStatusSent     = "sent"
+	ScheduledStatusFailed   = "failed"
+	ScheduledStatusCanceled = "canceled"
+)
+
+// ScheduledMessage is a message waiting to be sent at ScheduledAt. While a
+// scheduler holds it in the sending state, LeaseUntil tells other replicas
+// when they may take it over.
+type ScheduledMessage struct {
+	ID          string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	RoomID      string     `gorm:"not null;index" json:"room_id"`
+	UserID      string     `gorm:"not null;index" json:"user_id"`
+	Username    string     `gorm:"not null" json:"username"`
+	Content     string     `gorm:"type:text;not null" json:"content"`
+	ScheduledAt time.Time  `gorm:"not null;index" json:"scheduled_at"`
+	Status      string     `gorm:"not null;default:pending;index" json:"status"`
+	LeaseUntil  *time.Time `json:"-"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	MessageID   *string    `gorm:"type:uuid" json:"message_id,omitempty"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ClientMessageID is the idempotency key the scheduler sends the message
+// with, so a delivery retried after a crash cannot post it twice.
+func (sm *ScheduledMessage) ClientMessageID() string {
+	return "scheduled:" + sm.ID
+}
+
+func (sm *ScheduledMessage) BeforeCreate(tx *gorm.DB) error {
+	sm.CreatedAt = time.Now()
+	sm.UpdatedAt = time.Now()
+	if sm.Status == "" {
+		sm.Status = ScheduledStatusPending
+	}
+	return nil
+}
+
+func (sm *ScheduledMessage) BeforeUpdate(tx *gorm.DB) error {
+	sm.UpdatedAt = time.Now()
+	return nil
+}
\ No newline at end of file
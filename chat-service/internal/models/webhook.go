@@ -0,0 +1,86 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Events webhooks can subscribe to.
+const (
+	WebhookEventMessageCreated = "message_created"
+	WebhookEventMessageEdited  = "message_edited"
+	WebhookEventMessageDeleted = "message_deleted"
+	WebhookEventUserJoined     = "user_joined"
+)
+
+var WebhookEvents = []string{
+	WebhookEventMessageCreated,
+	WebhookEventMessageEdited,
+	WebhookEventMessageDeleted,
+	WebhookEventUserJoined,
+}
+
+func IsWebhookEvent(event string) bool {
+	return slices.Contains(WebhookEvents, event)
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySending   = "sending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook posts a room's events to an external URL. A webhook without a room
+// receives events from every room of its organization, and one without
+// events receives every event type. Failures counts consecutive failed
+// attempts; the webhook is disabled once it reaches the configured limit.
+type Webhook struct {
+	ID             string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	OrgID          string     `gorm:"type:uuid;index" json:"org_id"`
+	RoomID         *string    `gorm:"index" json:"room_id,omitempty"`
+	URL            string     `gorm:"type:text;not null" json:"url"`
+	Secret         string     `gorm:"not null" json:"-"`
+	Events         []string   `gorm:"type:jsonb;serializer:json" json:"events"`
+	Active         bool       `gorm:"not null;default:true" json:"active"`
+	Failures       int        `gorm:"not null;default:0" json:"failures"`
+	DisabledAt     *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason string     `json:"disabled_reason,omitempty"`
+	CreatedBy      string     `gorm:"not null" json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+func (w *Webhook) BeforeUpdate(tx *gorm.DB) error {
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// WebhookDelivery is one event queued for one webhook, and doubles as its
+// delivery log. Payload holds the exact body that is signed and sent.
+type WebhookDelivery struct {
+	ID             string     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	WebhookID      string     `gorm:"type:uuid;not null;index" json:"webhook_id"`
+	RoomID         string     `gorm:"not null" json:"room_id"`
+	Event          string     `gorm:"not null" json:"event"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"not null;default:pending;index" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	LeaseUntil     *time.Time `json:"-"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Webhook *Webhook `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE" json:"-"`
+}
\ No newline at end of file
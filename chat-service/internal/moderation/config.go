@@ -0,0 +1,93 @@
+package moderation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Built-in filter types.
+const (
+	FilterWordList      = "word_list"
+	FilterRegex         = "regex"
+	FilterLinks         = "links"
+	FilterRepeatedChars = "repeated_chars"
+	FilterCapsRatio     = "caps_ratio"
+)
+
+// FilterConfig configures one built-in filter. Only the fields relevant to
+// Type are used.
+type FilterConfig struct {
+	Type   string `json:"type"`
+	Action Action `json:"action"`
+
+	Words    []string `json:"words,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+
+	MaxLinks       int      `json:"max_links,omitempty"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+
+	MaxRepeat int `json:"max_repeat,omitempty"`
+
+	MaxCapsRatio float64 `json:"max_caps_ratio,omitempty"`
+	MinLetters   int     `json:"min_letters,omitempty"`
+}
+
+// Build creates a pipeline from filter configurations, in order.
+func Build(configs []FilterConfig) (*Pipeline, error) {
+	filters := make([]Filter, 0, len(configs))
+	for i, config := range configs {
+		filter, err := config.build()
+		if err != nil {
+			return nil, fmt.Errorf("filter %d (%s): %w", i, config.Type, err)
+		}
+		filters = append(filters, filter)
+	}
+	return NewPipeline(filters...), nil
+}
+
+func (c FilterConfig) build() (Filter, error) {
+	switch c.Action {
+	case ActionReject, ActionRedact, ActionFlag:
+	default:
+		return nil, fmt.Errorf("unknown action %q", c.Action)
+	}
+
+	switch c.Type {
+	case FilterWordList:
+		return NewWordListFilter(c.Words, c.Action)
+	case FilterRegex:
+		return NewRegexFilter(c.Patterns, c.Action)
+	case FilterLinks:
+		return NewLinkFilter(c.MaxLinks, c.AllowedDomains, c.BlockedDomains, c.Action), nil
+	case FilterRepeatedChars:
+		return NewRepeatedCharFilter(c.MaxRepeat, c.Action), nil
+	case FilterCapsRatio:
+		return NewCapsRatioFilter(c.MaxCapsRatio, c.MinLetters, c.Action), nil
+	}
+	return nil, fmt.Errorf("unknown filter type %q", c.Type)
+}
+
+// LoadConfigFile reads a JSON array of filter configurations. An empty path
+// yields no filters.
+func LoadConfigFile(path string) ([]FilterConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moderation config: %w", err)
+	}
+
+	var configs []FilterConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation config: %w", err)
+	}
+
+	if _, err := Build(configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
\ No newline at end of file
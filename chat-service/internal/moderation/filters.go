@@ -0,0 +1,290 @@
+package moderation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// WordListFilter matches whole words case-insensitively.
+type WordListFilter struct {
+	action  Action
+	pattern *regexp.Regexp
+}
+
+func NewWordListFilter(words []string, action Action) (*WordListFilter, error) {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil, fmt.Errorf("word list filter needs at least one word")
+	}
+
+	pattern, err := regexp.Compile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	if err != nil {
+		return nil, err
+	}
+	return &WordListFilter{action: action, pattern: pattern}, nil
+}
+
+func (f *WordListFilter) Name() string {
+	return FilterWordList
+}
+
+func (f *WordListFilter) Check(content string) Verdict {
+	match := f.pattern.FindString(content)
+	if match == "" {
+		return Verdict{Action: ActionAllow}
+	}
+	return Verdict{
+		Action:  f.action,
+		Reason:  ReasonBannedWord,
+		Detail:  "message contains a banned word",
+		Content: mask(f.pattern, content),
+	}
+}
+
+// RegexFilter matches any of a list of regular expressions.
+type RegexFilter struct {
+	action   Action
+	patterns []*regexp.Regexp
+}
+
+func NewRegexFilter(patterns []string, action Action) (*RegexFilter, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("regex filter needs at least one pattern")
+	}
+
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		compiled[i] = re
+	}
+	return &RegexFilter{action: action, patterns: compiled}, nil
+}
+
+func (f *RegexFilter) Name() string {
+	return FilterRegex
+}
+
+func (f *RegexFilter) Check(content string) Verdict {
+	redacted := content
+	matched := false
+	for _, pattern := range f.patterns {
+		if pattern.MatchString(redacted) {
+			matched = true
+			redacted = mask(pattern, redacted)
+		}
+	}
+	if !matched {
+		return Verdict{Action: ActionAllow}
+	}
+	return Verdict{
+		Action:  f.action,
+		Reason:  ReasonPatternMatch,
+		Detail:  "message matches a blocked pattern",
+		Content: redacted,
+	}
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+
+// LinkFilter limits the number of links and the domains they point to.
+// When allowed domains are set, links to any other domain are blocked.
+type LinkFilter struct {
+	action         Action
+	maxLinks       int
+	allowedDomains []string
+	blockedDomains []string
+}
+
+func NewLinkFilter(maxLinks int, allowedDomains, blockedDomains []string, action Action) *LinkFilter {
+	return &LinkFilter{
+		action:         action,
+		maxLinks:       maxLinks,
+		allowedDomains: normalizeDomains(allowedDomains),
+		blockedDomains: normalizeDomains(blockedDomains),
+	}
+}
+
+func (f *LinkFilter) Name() string {
+	return FilterLinks
+}
+
+func (f *LinkFilter) Check(content string) Verdict {
+	links := linkPattern.FindAllString(content, -1)
+	if len(links) == 0 {
+		return Verdict{Action: ActionAllow}
+	}
+
+	if f.maxLinks > 0 && len(links) > f.maxLinks {
+		return Verdict{
+			Action:  f.action,
+			Reason:  ReasonTooManyLinks,
+			Detail:  fmt.Sprintf("message contains more than %d links", f.maxLinks),
+			Content: linkPattern.ReplaceAllString(content, "[link removed]"),
+		}
+	}
+
+	blocked := false
+	redacted := linkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		if f.domainBlocked(linkDomain(link)) {
+			blocked = true
+			return "[link removed]"
+		}
+		return link
+	})
+	if blocked {
+		return Verdict{
+			Action:  f.action,
+			Reason:  ReasonBlockedDomain,
+			Detail:  "message links to a domain that is not allowed",
+			Content: redacted,
+		}
+	}
+
+	return Verdict{Action: ActionAllow}
+}
+
+func (f *LinkFilter) domainBlocked(domain string) bool {
+	if len(f.allowedDomains) > 0 && !matchesDomain(domain, f.allowedDomains) {
+		return true
+	}
+	return matchesDomain(domain, f.blockedDomains)
+}
+
+// RepeatedCharFilter catches runs of the same character such as "!!!!!!!!!!".
+type RepeatedCharFilter struct {
+	action    Action
+	maxRepeat int
+}
+
+func NewRepeatedCharFilter(maxRepeat int, action Action) *RepeatedCharFilter {
+	if maxRepeat <= 0 {
+		maxRepeat = 10
+	}
+	return &RepeatedCharFilter{action: action, maxRepeat: maxRepeat}
+}
+
+func (f *RepeatedCharFilter) Name() string {
+	return FilterRepeatedChars
+}
+
+func (f *RepeatedCharFilter) Check(content string) Verdict {
+	var (
+		out     strings.Builder
+		prev    rune
+		run     int
+		tooLong bool
+	)
+	for _, r := range content {
+		if r == prev {
+			run++
+		} else {
+			prev, run = r, 1
+		}
+		if run > f.maxRepeat {
+			tooLong = true
+			continue
+		}
+		out.WriteRune(r)
+	}
+	if !tooLong {
+		return Verdict{Action: ActionAllow}
+	}
+	return Verdict{
+		Action:  f.action,
+		Reason:  ReasonRepeatedCharacters,
+		Detail:  fmt.Sprintf("message repeats a character more than %d times", f.maxRepeat),
+		Content: out.String(),
+	}
+}
+
+// CapsRatioFilter catches messages that are mostly upper case.
+type CapsRatioFilter struct {
+	action     Action
+	maxRatio   float64
+	minLetters int
+}
+
+func NewCapsRatioFilter(maxRatio float64, minLetters int, action Action) *CapsRatioFilter {
+	if maxRatio <= 0 || maxRatio > 1 {
+		maxRatio = 0.7
+	}
+	if minLetters <= 0 {
+		minLetters = 10
+	}
+	return &CapsRatioFilter{action: action, maxRatio: maxRatio, minLetters: minLetters}
+}
+
+func (f *CapsRatioFilter) Name() string {
+	return FilterCapsRatio
+}
+
+func (f *CapsRatioFilter) Check(content string) Verdict {
+	letters, upper := 0, 0
+	for _, r := range content {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters < f.minLetters || float64(upper)/float64(letters) <= f.maxRatio {
+		return Verdict{Action: ActionAllow}
+	}
+	return Verdict{
+		Action:  f.action,
+		Reason:  ReasonExcessiveCaps,
+		Detail:  "message is mostly upper case",
+		Content: strings.ToLower(content),
+	}
+}
+
+// mask replaces every match of pattern with asterisks of the same length.
+func mask(pattern *regexp.Regexp, content string) string {
+	return pattern.ReplaceAllStringFunc(content, func(match string) string {
+		return strings.Repeat("*", len([]rune(match)))
+	})
+}
+
+func linkDomain(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+		if domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+	return normalized
+}
+
+// matchesDomain reports whether domain equals or is a subdomain of any listed domain.
+func matchesDomain(domain string, domains []string) bool {
+	for _, candidate := range domains {
+		if domain == candidate || strings.HasSuffix(domain, "."+candidate) {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"fmt"
+)
+
+// Action is what a filter decides to do with a message.
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionReject Action = "reject"
+	ActionRedact Action = "redact"
+	ActionFlag   Action = "flag"
+)
+
+// Machine-readable reasons reported by the built-in filters.
+const (
+	ReasonBannedWord         = "BANNED_WORD"
+	ReasonPatternMatch       = "PATTERN_MATCH"
+	ReasonTooManyLinks       = "TOO_MANY_LINKS"
+	ReasonBlockedDomain      = "BLOCKED_DOMAIN"
+	ReasonRepeatedCharacters = "REPEATED_CHARACTERS"
+	ReasonExcessiveCaps      = "EXCESSIVE_CAPS"
+)
+
+// Verdict is the outcome of a single filter.
+type Verdict struct {
+	Action Action
+	Filter string
+	Reason string
+	Detail string
+	// Content replaces the message content when Action is ActionRedact.
+	Content string
+}
+
+// Filter inspects message content. Filters that do not object return ActionAllow.
+type Filter interface {
+	Name() string
+	Check(content string) Verdict
+}
+
+// Result is the outcome of running a message through a pipeline.
+type Result struct {
+	// Content is the message content after all redactions.
+	Content  string
+	Rejected *Verdict
+	Flags    []Verdict
+}
+
+// RejectionError is returned to callers when a filter rejects a message.
+type RejectionError struct {
+	Filter string
+	Reason string
+	Detail string
+}
+
+func (e *RejectionError) Error() string {
+	return fmt.Sprintf("message rejected by %s filter: %s", e.Filter, e.Detail)
+}
+
+// Pipeline runs filters in order. Each filter sees the content as redacted by
+// the filters before it, and the first rejection stops the pipeline.
+type Pipeline struct {
+	filters []Filter
+}
+
+func NewPipeline(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters}
+}
+
+func (p *Pipeline) Run(content string) Result {
+	result := Result{Content: content}
+
+	for _, filter := range p.filters {
+		verdict := filter.Check(result.Content)
+		verdict.Filter = filter.Name()
+
+		switch verdict.Action {
+		case ActionReject:
+			result.Rejected = &verdict
+			return result
+		case ActionRedact:
+			result.Content = verdict.Content
+		case ActionFlag:
+			result.Flags = append(result.Flags, verdict)
+		}
+	}
+
+	return result
+}
+
+// Empty reports whether the pipeline has no filters.
+func (p *Pipeline) Empty() bool {
+	return len(p.filters) == 0
+}
\ No newline at end of file
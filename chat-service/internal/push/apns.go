@@ -0,0 +1,206 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apnsDefaultEndpoint = "https://api.push.apple.com"
+	// apnsTokenLifetime is how long a provider token is used. APNs rejects
+	// tokens older than an hour and ones refreshed more than every 20 minutes.
+	apnsTokenLifetime = 40 * time.Minute
+)
+
+// APNsConfig configures the APNs provider.
+type APNsConfig struct {
+	// KeyFile is the .p8 signing key from the Apple developer account, and
+	// KeyID and TeamID identify it.
+	KeyFile string
+	KeyID   string
+	TeamID  string
+	// Topic is the app's bundle ID.
+	Topic string
+	// Endpoint is https://api.push.apple.com by default, or
+	// https://api.sandbox.push.apple.com for development builds. A local
+	// stub can stand in for either.
+	Endpoint string
+}
+
+// APNsProvider sends messages with the APNs HTTP/2 API, one request per
+// device over a shared connection, authenticating with a provider token.
+type APNsProvider struct {
+	client   *http.Client
+	endpoint string
+	topic    string
+	keyID    string
+	teamID   string
+	key      crypto.Signer
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+func NewAPNsProvider(cfg APNsConfig, client *http.Client) (*APNsProvider, error) {
+	data, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APNs key: %w", err)
+	}
+	key, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse APNs key: %w", err)
+	}
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, errors.New("APNs needs a key ID, team ID and topic")
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = apnsDefaultEndpoint
+	}
+	if client == nil {
+		client = &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{ForceAttemptHTTP2: true},
+		}
+	}
+
+	return &APNsProvider{
+		client:   client,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		topic:    cfg.Topic,
+		keyID:    cfg.KeyID,
+		teamID:   cfg.TeamID,
+		key:      key,
+	}, nil
+}
+
+func (p *APNsProvider) Name() string {
+	return "apns"
+}
+
+func (p *APNsProvider) Send(ctx context.Context, messages []Message) []Result {
+	return sendEach(ctx, messages, p.send)
+}
+
+type apnsAlert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type apnsAPS struct {
+	Alert    apnsAlert `json:"alert"`
+	Sound    string    `json:"sound,omitempty"`
+	Badge    *int      `json:"badge,omitempty"`
+	ThreadID string    `json:"thread-id,omitempty"`
+}
+
+func (p *APNsProvider) send(ctx context.Context, message Message) Result {
+	result := Result{Token: message.Token}
+
+	token, err := p.providerToken()
+	if err != nil {
+		result.Err = fmt.Errorf("failed to sign APNs token: %w", err)
+		return result
+	}
+
+	// Custom data sits next to aps in the payload
+	payload := map[string]any{}
+	for key, value := range message.Notification.Data {
+		payload[key] = value
+	}
+	aps := apnsAPS{
+		Alert:    apnsAlert{Title: message.Notification.Title, Body: message.Notification.Body},
+		Sound:    "default",
+		ThreadID: message.Notification.ThreadID,
+	}
+	if message.Notification.Badge > 0 {
+		aps.Badge = &message.Notification.Badge
+	}
+	payload["aps"] = aps
+	body, err := json.Marshal(payload)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/3/device/"+message.Token, bytes.NewReader(body))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	if message.Notification.ThreadID != "" {
+		req.Header.Set("apns-collapse-id", message.Notification.ThreadID)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode == http.StatusOK {
+		return result
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	json.Unmarshal(responseBody, &failure)
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		failure.Reason == "BadDeviceToken",
+		failure.Reason == "Unregistered",
+		failure.Reason == "DeviceTokenNotForTopic":
+		result.Unregistered = true
+		result.Err = ErrUnregistered
+		return result
+	case failure.Reason == "ExpiredProviderToken":
+		p.mu.Lock()
+		p.token = ""
+		p.mu.Unlock()
+	}
+	result.Err = fmt.Errorf("APNs responded with status %d: %s", resp.StatusCode, failure.Reason)
+	return result
+}
+
+// providerToken returns the current provider token, signing a new one when
+// it gets old.
+func (p *APNsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.issuedAt) < apnsTokenLifetime {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	token, err := signJWT(
+		map[string]any{"alg": "ES256", "kid": p.keyID},
+		map[string]any{"iss": p.teamID, "iat": now.Unix()},
+		p.key)
+	if err != nil {
+		return "", err
+	}
+	p.token = token
+	p.issuedAt = now
+	return token, nil
+}
\ No newline at end of file
@@ -0,0 +1,266 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// apnsRequest is what the stub APNs server received.
+type apnsRequest struct {
+	path    string
+	header  http.Header
+	payload map[string]any
+}
+
+// apnsStub is a local APNs server that answers each device token with the
+// status and reason in responses, or 200 when the token is not listed.
+type apnsStub struct {
+	mu        sync.Mutex
+	requests  []apnsRequest
+	responses map[string]apnsResponse
+}
+
+type apnsResponse struct {
+	status int
+	reason string
+}
+
+func (s *apnsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var payload map[string]any
+	json.Unmarshal(body, &payload)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, apnsRequest{path: r.URL.Path, header: r.Header.Clone(), payload: payload})
+	response, ok := s.responses[strings.TrimPrefix(r.URL.Path, "/3/device/")]
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(response.status)
+	json.NewEncoder(w).Encode(map[string]string{"reason": response.reason})
+}
+
+func newTestAPNsProvider(t *testing.T, stub *apnsStub) (*APNsProvider, *ecdsa.PublicKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "AuthKey_KEY123.p8")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	provider, err := NewAPNsProvider(APNsConfig{
+		KeyFile:  keyFile,
+		KeyID:    "KEY123",
+		TeamID:   "TEAM456",
+		Topic:    "com.example.chat",
+		Endpoint: server.URL + "/",
+	}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider, &key.PublicKey
+}
+
+// verifyES256 checks a JWT's ES256 signature and returns its decoded header
+// and claims.
+func verifyES256(t *testing.T, token string, key *ecdsa.PublicKey) (header, claims map[string]any) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token %q is not a JWT", token)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		t.Fatalf("bad ES256 signature encoding: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(key, digest[:], r, s) {
+		t.Fatal("provider token signature does not verify")
+	}
+
+	for i, target := range []*map[string]any{&header, &claims} {
+		data, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return header, claims
+}
+
+func TestAPNsProviderSend(t *testing.T) {
+	stub := &apnsStub{}
+	provider, publicKey := newTestAPNsProvider(t, stub)
+
+	results := provider.Send(context.Background(), []Message{{
+		Token: "device-a",
+		Notification: Notification{
+			Title:    "general",
+			Body:     "alice: hello",
+			ThreadID: "room-1",
+			Badge:    3,
+			Data:     map[string]string{"room_id": "room-1", "message_id": "msg-1"},
+		},
+	}})
+
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].Err != nil || results[0].Unregistered || results[0].Token != "device-a" {
+		t.Fatalf("result = %+v, want a delivered device-a", results[0])
+	}
+	if len(stub.requests) != 1 {
+		t.Fatalf("stub received %d requests, want 1", len(stub.requests))
+	}
+
+	req := stub.requests[0]
+	if req.path != "/3/device/device-a" {
+		t.Errorf("path = %q, want /3/device/device-a", req.path)
+	}
+	for name, want := range map[string]string{
+		"Content-Type":     "application/json",
+		"apns-topic":       "com.example.chat",
+		"apns-push-type":   "alert",
+		"apns-priority":    "10",
+		"apns-collapse-id": "room-1",
+	} {
+		if got := req.header.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+
+	token, ok := strings.CutPrefix(req.header.Get("Authorization"), "bearer ")
+	if !ok {
+		t.Fatalf("Authorization = %q, want a bearer token", req.header.Get("Authorization"))
+	}
+	header, claims := verifyES256(t, token, publicKey)
+	if header["alg"] != "ES256" || header["kid"] != "KEY123" {
+		t.Errorf("token header = %v, want ES256 signed with KEY123", header)
+	}
+	if claims["iss"] != "TEAM456" || claims["iat"] == nil {
+		t.Errorf("token claims = %v, want iss TEAM456 and iat", claims)
+	}
+
+	aps, _ := req.payload["aps"].(map[string]any)
+	alert, _ := aps["alert"].(map[string]any)
+	if alert["title"] != "general" || alert["body"] != "alice: hello" {
+		t.Errorf("alert = %v, want the notification's title and body", alert)
+	}
+	if aps["sound"] != "default" || aps["badge"] != float64(3) || aps["thread-id"] != "room-1" {
+		t.Errorf("aps = %v, want sound, badge 3 and thread-id room-1", aps)
+	}
+	if req.payload["room_id"] != "room-1" || req.payload["message_id"] != "msg-1" {
+		t.Errorf("payload = %v, want the custom data next to aps", req.payload)
+	}
+}
+
+func TestAPNsProviderReusesProviderToken(t *testing.T) {
+	stub := &apnsStub{}
+	provider, _ := newTestAPNsProvider(t, stub)
+
+	provider.Send(context.Background(), []Message{{Token: "device-a"}, {Token: "device-b"}})
+	provider.Send(context.Background(), []Message{{Token: "device-c"}})
+
+	if len(stub.requests) != 3 {
+		t.Fatalf("stub received %d requests, want 3", len(stub.requests))
+	}
+	first := stub.requests[0].header.Get("Authorization")
+	for _, req := range stub.requests[1:] {
+		if got := req.header.Get("Authorization"); got != first {
+			t.Errorf("Authorization = %q, want the cached token %q", got, first)
+		}
+	}
+}
+
+func TestAPNsProviderUnregisteredTokens(t *testing.T) {
+	stub := &apnsStub{responses: map[string]apnsResponse{
+		"gone":        {http.StatusGone, "Unregistered"},
+		"bad":         {http.StatusBadRequest, "BadDeviceToken"},
+		"other-app":   {http.StatusBadRequest, "DeviceTokenNotForTopic"},
+		"throttled":   {http.StatusTooManyRequests, "TooManyRequests"},
+		"bad-request": {http.StatusBadRequest, "PayloadTooLarge"},
+		"unavailable": {http.StatusServiceUnavailable, "ServiceUnavailable"},
+	}}
+	provider, _ := newTestAPNsProvider(t, stub)
+
+	tokens := []string{"ok", "gone", "bad", "other-app", "throttled", "bad-request", "unavailable"}
+	messages := make([]Message, len(tokens))
+	for i, token := range tokens {
+		messages[i] = Message{Token: token, Notification: Notification{Title: "t", Body: "b"}}
+	}
+	results := provider.Send(context.Background(), messages)
+
+	unregistered := map[string]bool{"gone": true, "bad": true, "other-app": true}
+	for i, result := range results {
+		if result.Token != tokens[i] {
+			t.Fatalf("result %d is for %q, want %q", i, result.Token, tokens[i])
+		}
+		switch {
+		case tokens[i] == "ok":
+			if result.Err != nil || result.Unregistered {
+				t.Errorf("%s: result = %+v, want delivered", tokens[i], result)
+			}
+		case unregistered[tokens[i]]:
+			if !result.Unregistered || !errors.Is(result.Err, ErrUnregistered) {
+				t.Errorf("%s: result = %+v, want unregistered", tokens[i], result)
+			}
+		default:
+			if result.Err == nil || result.Unregistered {
+				t.Errorf("%s: result = %+v, want a failure that keeps the token", tokens[i], result)
+			}
+		}
+	}
+}
+
+func TestAPNsProviderRenewsExpiredProviderToken(t *testing.T) {
+	stub := &apnsStub{responses: map[string]apnsResponse{
+		"device-a": {http.StatusForbidden, "ExpiredProviderToken"},
+	}}
+	provider, _ := newTestAPNsProvider(t, stub)
+
+	result := provider.Send(context.Background(), []Message{{Token: "device-a"}})[0]
+	if result.Err == nil || result.Unregistered {
+		t.Fatalf("result = %+v, want a retryable failure", result)
+	}
+	provider.Send(context.Background(), []Message{{Token: "device-b"}})
+
+	if first, second := stub.requests[0].header.Get("Authorization"), stub.requests[1].header.Get("Authorization"); first == second {
+		t.Error("provider token was not renewed after APNs rejected it as expired")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package push
+
+import (
+	"log"
+	"os"
+)
+
+// ProvidersFromEnv returns a provider per platform. FCM is used when
+// PUSH_FCM_CREDENTIALS is set and APNs when PUSH_APNS_KEY_FILE is set; other
+// platforms get a log provider.
+func ProvidersFromEnv() (map[string]PushProvider, error) {
+	providers := map[string]PushProvider{
+		PlatformFCM:  NewLogProvider(PlatformFCM),
+		PlatformAPNs: NewLogProvider(PlatformAPNs),
+	}
+
+	if credentials := os.Getenv("PUSH_FCM_CREDENTIALS"); credentials != "" {
+		fcm, err := NewFCMProvider(FCMConfig{
+			CredentialsFile: credentials,
+			ProjectID:       os.Getenv("PUSH_FCM_PROJECT_ID"),
+			Endpoint:        os.Getenv("PUSH_FCM_ENDPOINT"),
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		providers[PlatformFCM] = fcm
+	}
+
+	if keyFile := os.Getenv("PUSH_APNS_KEY_FILE"); keyFile != "" {
+		apns, err := NewAPNsProvider(APNsConfig{
+			KeyFile:  keyFile,
+			KeyID:    os.Getenv("PUSH_APNS_KEY_ID"),
+			TeamID:   os.Getenv("PUSH_APNS_TEAM_ID"),
+			Topic:    os.Getenv("PUSH_APNS_TOPIC"),
+			Endpoint: os.Getenv("PUSH_APNS_ENDPOINT"),
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		providers[PlatformAPNs] = apns
+	}
+
+	for platform, provider := range providers {
+		log.Printf("Push provider for %s: %s", platform, provider.Name())
+	}
+	return providers, nil
+}
\ No newline at end of file
@@ -0,0 +1,295 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmDefaultEndpoint = "https://fcm.googleapis.com"
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// FCMConfig configures the FCM provider.
+type FCMConfig struct {
+	// CredentialsFile is a Google service account JSON key file.
+	CredentialsFile string
+	// ProjectID defaults to the service account's project.
+	ProjectID string
+	// Endpoint is the FCM base URL; a local stub can stand in for it. The
+	// service account's token_uri is used to get access tokens, so a stub
+	// can serve those too.
+	Endpoint string
+}
+
+// FCMProvider sends messages with the FCM HTTP v1 API, one request per
+// device, authenticating with an OAuth 2 access token of a service account.
+type FCMProvider struct {
+	client    *http.Client
+	endpoint  string
+	projectID string
+	tokens    *googleTokenSource
+}
+
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func NewFCMProvider(cfg FCMConfig, client *http.Client) (*FCMProvider, error) {
+	data, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+	key, err := parsePrivateKey([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FCM private key: %w", err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("FCM credentials need client_email and token_uri")
+	}
+
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" {
+		return nil, errors.New("FCM project ID is not set")
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fcmDefaultEndpoint
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &FCMProvider{
+		client:    client,
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		projectID: projectID,
+		tokens: &googleTokenSource{
+			client:   client,
+			email:    account.ClientEmail,
+			key:      key,
+			tokenURI: account.TokenURI,
+		},
+	}, nil
+}
+
+func (p *FCMProvider) Name() string {
+	return "fcm"
+}
+
+func (p *FCMProvider) Send(ctx context.Context, messages []Message) []Result {
+	return sendEach(ctx, messages, p.send)
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      *fcmAndroid       `json:"android,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmAndroid struct {
+	// CollapseKey replaces an earlier undelivered notification of the same
+	// thread.
+	CollapseKey  string                  `json:"collapse_key,omitempty"`
+	Notification *fcmAndroidNotification `json:"notification,omitempty"`
+}
+
+type fcmAndroidNotification struct {
+	Tag               string `json:"tag,omitempty"`
+	NotificationCount int    `json:"notification_count,omitempty"`
+}
+
+type fcmError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (p *FCMProvider) send(ctx context.Context, message Message) Result {
+	result := Result{Token: message.Token}
+
+	accessToken, err := p.tokens.Token(ctx)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get FCM access token: %w", err)
+		return result
+	}
+
+	request := fcmRequest{Message: fcmMessage{
+		Token: message.Token,
+		Notification: fcmNotification{
+			Title: message.Notification.Title,
+			Body:  message.Notification.Body,
+		},
+		Data: message.Notification.Data,
+	}}
+	if message.Notification.ThreadID != "" || message.Notification.Badge > 0 {
+		request.Message.Android = &fcmAndroid{
+			CollapseKey: message.Notification.ThreadID,
+			Notification: &fcmAndroidNotification{
+				Tag:               message.Notification.ThreadID,
+				NotificationCount: message.Notification.Badge,
+			},
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	sendURL := p.endpoint + "/v1/projects/" + url.PathEscape(p.projectID) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendURL, bytes.NewReader(body))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode == http.StatusOK {
+		return result
+	}
+
+	var failure fcmError
+	json.Unmarshal(responseBody, &failure)
+	for _, detail := range failure.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			result.Unregistered = true
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		result.Unregistered = true
+	}
+	if result.Unregistered {
+		result.Err = ErrUnregistered
+		return result
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		p.tokens.Reset()
+	}
+	result.Err = fmt.Errorf("FCM responded with status %d: %s", resp.StatusCode, failure.Error.Message)
+	return result
+}
+
+// googleTokenSource gets OAuth 2 access tokens for a service account with a
+// signed JWT assertion, and keeps them until shortly before they expire.
+type googleTokenSource struct {
+	client   *http.Client
+	email    string
+	key      crypto.Signer
+	tokenURI string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (s *googleTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expiresAt) > time.Minute {
+		return s.token, nil
+	}
+
+	now := time.Now()
+	assertion, err := signJWT(
+		map[string]any{"alg": "RS256", "typ": "JWT"},
+		map[string]any{
+			"iss":   s.email,
+			"scope": fcmScope,
+			"aud":   s.tokenURI,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		},
+		s.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("token endpoint responded with status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("token endpoint returned no access token")
+	}
+
+	s.token = token.AccessToken
+	s.expiresAt = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// Reset drops the cached token, after FCM rejected it.
+func (s *googleTokenSource) Reset() {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+}
\ No newline at end of file
@@ -0,0 +1,284 @@
+package push
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fcmStub is a local FCM server that also serves the service account's
+// token endpoint. It answers each device token with the status and body in
+// responses, or 200 when the token is not listed.
+type fcmStub struct {
+	mu            sync.Mutex
+	tokenRequests []*http.Request
+	requests      []fcmStubRequest
+	responses     map[string]fcmResponse
+	accessTokens  int
+}
+
+type fcmStubRequest struct {
+	path   string
+	header http.Header
+	body   fcmRequest
+}
+
+type fcmResponse struct {
+	status int
+	body   string
+}
+
+func (s *fcmStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		r.ParseForm()
+		s.tokenRequests = append(s.tokenRequests, r)
+		s.accessTokens++
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-" + strconv.Itoa(s.accessTokens),
+			"expires_in":   3600,
+			"token_type":   "Bearer",
+		})
+		return
+	}
+
+	var body fcmRequest
+	json.NewDecoder(r.Body).Decode(&body)
+	s.requests = append(s.requests, fcmStubRequest{path: r.URL.Path, header: r.Header.Clone(), body: body})
+
+	response, ok := s.responses[body.Message.Token]
+	if !ok {
+		w.Write([]byte(`{"name":"projects/chat-test/messages/1"}`))
+		return
+	}
+	w.WriteHeader(response.status)
+	w.Write([]byte(response.body))
+}
+
+func newTestFCMProvider(t *testing.T, stub *fcmStub) (*FCMProvider, *rsa.PublicKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	credentials, err := json.Marshal(serviceAccount{
+		ProjectID:   "chat-test",
+		ClientEmail: "push@chat-test.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialsFile := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(credentialsFile, credentials, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := NewFCMProvider(FCMConfig{
+		CredentialsFile: credentialsFile,
+		Endpoint:        server.URL,
+	}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider, &key.PublicKey, server.URL + "/token"
+}
+
+// verifyRS256 checks a JWT's RS256 signature and returns its decoded header
+// and claims.
+func verifyRS256(t *testing.T, token string, key *rsa.PublicKey) (header, claims map[string]any) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("assertion %q is not a JWT", token)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("assertion signature does not verify: %v", err)
+	}
+
+	for i, target := range []*map[string]any{&header, &claims} {
+		data, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return header, claims
+}
+
+func TestFCMProviderSend(t *testing.T) {
+	stub := &fcmStub{}
+	provider, publicKey, tokenURI := newTestFCMProvider(t, stub)
+
+	results := provider.Send(context.Background(), []Message{{
+		Token: "device-a",
+		Notification: Notification{
+			Title:    "general",
+			Body:     "alice: hello",
+			ThreadID: "room-1",
+			Badge:    3,
+			Data:     map[string]string{"room_id": "room-1", "message_id": "msg-1"},
+		},
+	}})
+
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if results[0].Err != nil || results[0].Unregistered || results[0].Token != "device-a" {
+		t.Fatalf("result = %+v, want a delivered device-a", results[0])
+	}
+
+	if len(stub.tokenRequests) != 1 {
+		t.Fatalf("token endpoint received %d requests, want 1", len(stub.tokenRequests))
+	}
+	tokenRequest := stub.tokenRequests[0]
+	if got := tokenRequest.Header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+		t.Errorf("token request Content-Type = %q", got)
+	}
+	if got := tokenRequest.PostForm.Get("grant_type"); got != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		t.Errorf("grant_type = %q, want the JWT bearer grant", got)
+	}
+	header, claims := verifyRS256(t, tokenRequest.PostForm.Get("assertion"), publicKey)
+	if header["alg"] != "RS256" {
+		t.Errorf("assertion header = %v, want RS256", header)
+	}
+	if claims["iss"] != "push@chat-test.iam.gserviceaccount.com" || claims["aud"] != tokenURI || claims["scope"] != fcmScope {
+		t.Errorf("assertion claims = %v, want the service account, token URI and FCM scope", claims)
+	}
+
+	if len(stub.requests) != 1 {
+		t.Fatalf("stub received %d messages, want 1", len(stub.requests))
+	}
+	req := stub.requests[0]
+	if req.path != "/v1/projects/chat-test/messages:send" {
+		t.Errorf("path = %q, want the project's messages:send", req.path)
+	}
+	if got := req.header.Get("Authorization"); got != "Bearer access-1" {
+		t.Errorf("Authorization = %q, want the access token", got)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	message := req.body.Message
+	if message.Token != "device-a" || message.Notification.Title != "general" || message.Notification.Body != "alice: hello" {
+		t.Errorf("message = %+v, want the device token and notification", message)
+	}
+	if message.Data["room_id"] != "room-1" || message.Data["message_id"] != "msg-1" {
+		t.Errorf("data = %v, want the notification's data", message.Data)
+	}
+	if message.Android == nil || message.Android.CollapseKey != "room-1" || message.Android.Notification == nil ||
+		message.Android.Notification.Tag != "room-1" || message.Android.Notification.NotificationCount != 3 {
+		t.Errorf("android = %+v, want collapse key, tag room-1 and count 3", message.Android)
+	}
+}
+
+func TestFCMProviderReusesAccessToken(t *testing.T) {
+	stub := &fcmStub{}
+	provider, _, _ := newTestFCMProvider(t, stub)
+
+	provider.Send(context.Background(), []Message{{Token: "device-a"}, {Token: "device-b"}})
+	provider.Send(context.Background(), []Message{{Token: "device-c"}})
+
+	if len(stub.tokenRequests) != 1 {
+		t.Errorf("token endpoint received %d requests, want 1", len(stub.tokenRequests))
+	}
+	for _, req := range stub.requests {
+		if got := req.header.Get("Authorization"); got != "Bearer access-1" {
+			t.Errorf("Authorization = %q, want the cached access token", got)
+		}
+	}
+}
+
+func TestFCMProviderUnregisteredTokens(t *testing.T) {
+	stub := &fcmStub{responses: map[string]fcmResponse{
+		"not-found":    {http.StatusNotFound, `{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`},
+		"unregistered": {http.StatusBadRequest, `{"error":{"code":400,"message":"Token is unregistered","status":"INVALID_ARGUMENT","details":[{"errorCode":"UNREGISTERED"}]}}`},
+		"quota":        {http.StatusTooManyRequests, `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED","details":[{"errorCode":"QUOTA_EXCEEDED"}]}}`},
+		"unavailable":  {http.StatusServiceUnavailable, `{"error":{"code":503,"message":"Unavailable","status":"UNAVAILABLE","details":[{"errorCode":"UNAVAILABLE"}]}}`},
+	}}
+	provider, _, _ := newTestFCMProvider(t, stub)
+
+	tokens := []string{"ok", "not-found", "unregistered", "quota", "unavailable"}
+	messages := make([]Message, len(tokens))
+	for i, token := range tokens {
+		messages[i] = Message{Token: token, Notification: Notification{Title: "t", Body: "b"}}
+	}
+	results := provider.Send(context.Background(), messages)
+
+	unregistered := map[string]bool{"not-found": true, "unregistered": true}
+	for i, result := range results {
+		if result.Token != tokens[i] {
+			t.Fatalf("result %d is for %q, want %q", i, result.Token, tokens[i])
+		}
+		switch {
+		case tokens[i] == "ok":
+			if result.Err != nil || result.Unregistered {
+				t.Errorf("%s: result = %+v, want delivered", tokens[i], result)
+			}
+		case unregistered[tokens[i]]:
+			if !result.Unregistered || !errors.Is(result.Err, ErrUnregistered) {
+				t.Errorf("%s: result = %+v, want unregistered", tokens[i], result)
+			}
+		default:
+			if result.Err == nil || result.Unregistered {
+				t.Errorf("%s: result = %+v, want a failure that keeps the token", tokens[i], result)
+			}
+		}
+	}
+}
+
+func TestFCMProviderRenewsRejectedAccessToken(t *testing.T) {
+	stub := &fcmStub{responses: map[string]fcmResponse{
+		"device-a": {http.StatusUnauthorized, `{"error":{"code":401,"message":"Request had invalid authentication credentials.","status":"UNAUTHENTICATED"}}`},
+	}}
+	provider, _, _ := newTestFCMProvider(t, stub)
+
+	result := provider.Send(context.Background(), []Message{{Token: "device-a"}})[0]
+	if result.Err == nil || result.Unregistered {
+		t.Fatalf("result = %+v, want a retryable failure", result)
+	}
+	provider.Send(context.Background(), []Message{{Token: "device-b"}})
+
+	if len(stub.tokenRequests) != 2 {
+		t.Fatalf("token endpoint received %d requests, want 2", len(stub.tokenRequests))
+	}
+	if got := stub.requests[1].header.Get("Authorization"); got != "Bearer access-2" {
+		t.Errorf("Authorization = %q, want the renewed access token", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package push
+
+import (
+	"context"
+	"log"
+)
+
+// LogProvider logs messages instead of sending them, for development and for
+// platforms without a configured push service.
+type LogProvider struct {
+	platform string
+}
+
+func NewLogProvider(platform string) *LogProvider {
+	return &LogProvider{platform: platform}
+}
+
+func (p *LogProvider) Name() string {
+	return "log (" + p.platform + ")"
+}
+
+func (p *LogProvider) Send(ctx context.Context, messages []Message) []Result {
+	results := make([]Result, len(messages))
+	for i, message := range messages {
+		log.Printf("Push to %s device %s: %q %q", p.platform, shortToken(message.Token), message.Notification.Title, message.Notification.Body)
+		results[i] = Result{Token: message.Token}
+	}
+	return results
+}
+
+// shortToken keeps device tokens out of logs.
+func shortToken(token string) string {
+	if len(token) <= 8 {
+		return token
+	}
+	return token[:8] + "…"
+}
\ No newline at end of file
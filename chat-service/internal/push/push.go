@@ -0,0 +1,81 @@
+// Package push sends push notifications to devices through FCM and APNs.
+// Each push service is a PushProvider; the log provider stands in for
+// services that are not configured.
+package push
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Platforms devices register for.
+const (
+	PlatformFCM  = "fcm"
+	PlatformAPNs = "apns"
+)
+
+// IsPlatform reports whether platform is a supported push platform.
+func IsPlatform(platform string) bool {
+	return platform == PlatformFCM || platform == PlatformAPNs
+}
+
+// Notification is what a device shows.
+type Notification struct {
+	Title string
+	Body  string
+	// ThreadID groups notifications on the device, such as by room.
+	ThreadID string
+	// Badge is the number of unread notifications the app icon shows.
+	Badge int
+	// Data is passed to the app with the notification.
+	Data map[string]string
+}
+
+// Message is a notification for one device.
+type Message struct {
+	Token        string
+	Notification Notification
+}
+
+// Result is the outcome of sending one message.
+type Result struct {
+	Token string
+	Err   error
+	// Unregistered means the push service no longer knows the token, and it
+	// should be forgotten.
+	Unregistered bool
+}
+
+// PushProvider sends messages through one push service. Send returns one
+// result per message, in the same order.
+type PushProvider interface {
+	Name() string
+	Send(ctx context.Context, messages []Message) []Result
+}
+
+// ErrUnregistered is the error of results whose token is unregistered.
+var ErrUnregistered = errors.New("device token is no longer registered")
+
+// maxConcurrentSends is how many requests a provider has in flight at once.
+const maxConcurrentSends = 16
+
+// sendEach sends every message with send, a few at a time, for push services
+// that take one device per request.
+func sendEach(ctx context.Context, messages []Message, send func(context.Context, Message) Result) []Result {
+	results := make([]Result, len(messages))
+	slots := make(chan struct{}, maxConcurrentSends)
+
+	var wg sync.WaitGroup
+	for i, message := range messages {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = send(ctx, message)
+		}()
+	}
+	wg.Wait()
+	return results
+}
\ No newline at end of file
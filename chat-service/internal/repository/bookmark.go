@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookmarkCursor is the position of the last bookmark of a page.
+type BookmarkCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// SaveBookmark bookmarks a message, or updates the folder and note of the
+// user's existing bookmark of it.
+func (r *chatRepository) SaveBookmark(bookmark *models.Bookmark) (*models.Bookmark, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "message_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"folder", "note", "updated_at"}),
+	}).Create(bookmark)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var saved models.Bookmark
+	result = r.db.First(&saved, "user_id = ? AND message_id = ?", bookmark.UserID, bookmark.MessageID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &saved, nil
+}
+
+// DeleteBookmark removes the user's bookmark of the message and returns it.
+func (r *chatRepository) DeleteBookmark(userID, messageID string) (*models.Bookmark, error) {
+	var bookmarks []*models.Bookmark
+	result := r.db.Clauses(clause.Returning{}).
+		Where("user_id = ? AND message_id = ?", userID, messageID).
+		Delete(&bookmarks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(bookmarks) == 0 {
+		return nil, ErrBookmarkNotFound
+	}
+	return bookmarks[0], nil
+}
+
+// GetBookmarks returns up to limit of the user's bookmarks, newest first,
+// starting after the cursor when one is given. An empty folder returns the
+// bookmarks of every folder. Each bookmark comes with its message as it is
+// now, if it still exists, and the name of its room.
+func (r *chatRepository) GetBookmarks(userID, folder string, after *BookmarkCursor, limit int) ([]*models.Bookmark, error) {
+	var bookmarks []*models.Bookmark
+	query := r.db.Where("user_id = ?", userID)
+	if folder != "" {
+		query = query.Where("folder = ?", folder)
+	}
+	if after != nil {
+		query = query.Where("(created_at, id::text) < (?, ?)", after.CreatedAt, after.ID)
+	}
+	result := query.Order("created_at DESC, id DESC").Limit(limit).Find(&bookmarks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if err := attachBookmarked(r.db, bookmarks); err != nil {
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+// GetBookmarkFolders returns the user's folders by name, with the number of
+// bookmarks in each.
+func (r *chatRepository) GetBookmarkFolders(userID string) ([]*models.BookmarkFolder, error) {
+	var folders []*models.BookmarkFolder
+	result := r.db.Model(&models.Bookmark{}).
+		Select("folder AS name, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("folder").
+		Order("folder").
+		Scan(&folders)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return folders, nil
+}
+
+// attachBookmarked loads the messages and room names of bookmarks. Messages
+// that no longer exist are left nil.
+func attachBookmarked(db *gorm.DB, bookmarks []*models.Bookmark) error {
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	messageIDs := make([]string, len(bookmarks))
+	roomIDs := make([]string, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		messageIDs[i] = bookmark.MessageID
+		roomIDs[i] = bookmark.RoomID
+	}
+
+	var messages []*models.Message
+	if err := db.Scopes(unexpired).Where("id IN ?", messageIDs).Find(&messages).Error; err != nil {
+		return err
+	}
+	if err := attachPolls(db, messages); err != nil {
+		return err
+	}
+	if err := attachReactions(db, messages); err != nil {
+		return err
+	}
+	if err := attachReferences(db, messages); err != nil {
+		return err
+	}
+
+	var rooms []*models.Room
+	if err := db.Select("id, name").Where("id IN ?", roomIDs).Find(&rooms).Error; err != nil {
+		return err
+	}
+
+	byID := make(map[string]*models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+	names := make(map[string]string, len(rooms))
+	for _, room := range rooms {
+		names[room.ID] = room.Name
+	}
+	for _, bookmark := range bookmarks {
+		bookmark.Message = byID[bookmark.MessageID]
+		bookmark.RoomName = names[bookmark.RoomID]
+	}
+	return nil
+}
\ No newline at end of file
@@ -2,34 +2,164 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"chat-service/internal/models"
+	"chat-service/internal/moderation"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	ErrRoomNotFound    = errors.New("room not found")
 	ErrMessageNotFound = errors.New("message not found")
 	ErrAlreadyMember   = errors.New("user already a member of this room")
+	ErrMemberNotFound  = errors.New("room member not found")
+	ErrAlreadyPinned   = errors.New("message already pinned")
+	ErrPinNotFound     = errors.New("pinned message not found")
+	ErrPinLimitReached = errors.New("pinned message limit reached")
+
+	ErrModerationItemNotFound = errors.New("moderation item not found")
+	ErrAlreadyReported        = errors.New("message already reported")
+	ErrItemClaimed            = errors.New("moderation item claimed by another moderator")
+	ErrItemResolved           = errors.New("moderation item already resolved")
+
+	ErrScheduledMessageNotFound   = errors.New("scheduled message not found")
+	ErrScheduledMessageNotPending = errors.New("scheduled message is no longer pending")
+
+	ErrPollNotFound = errors.New("poll not found")
+	ErrPollClosed   = errors.New("poll is closed")
+
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrIncomingWebhookNotFound = errors.New("incoming webhook not found")
+	ErrExportJobNotFound       = errors.New("export job not found")
+	ErrBookmarkNotFound        = errors.New("bookmark not found")
+	ErrPushDeviceNotFound      = errors.New("push device not found")
 )
 
 type ChatRepository interface {
+	RetentionRepository
+	WebhookDeliveryRepository
+	PushJobRepository
+
+	ForOrg(orgID string) ChatRepository
+
 	CreateRoom(room *models.Room) error
 	GetRoomByID(roomID string) (*models.Room, error)
-	GetAllRooms() ([]*models.Room, error)
+	GetAllRooms(includeArchived bool) ([]*models.Room, error)
+	UpdateRoom(roomID string, updates map[string]any) (*models.Room, error)
+	ArchiveRoom(roomID string) (*models.Room, error)
+	DeleteRoom(roomID string) error
+	UpdateRoomRetention(roomID string, policy models.RetentionPolicy) error
+	UpdateRoomModeration(roomID string, filters []moderation.FilterConfig) error
 	CreateMessage(message *models.Message) error
+	CreateMessageIdempotent(message *models.Message, window time.Duration) (*models.Message, bool, error)
+	FindIdempotentMessage(roomID, userID, clientMessageID string, window time.Duration) (*models.Message, error)
+	ClaimIdempotencyKey(roomID, userID, clientMessageID string, window time.Duration) (bool, error)
+	ReleaseIdempotencyKey(roomID, userID, clientMessageID string) error
+	DeleteExpiredIdempotencyKeys(before time.Time) (int64, error)
+	GetMessageByID(messageID string) (*models.Message, error)
+	UpdateMessageContent(message *models.Message, revision *models.MessageRevision) error
+	DeleteMessage(revision *models.MessageRevision) error
 	GetMessagesByRoomID(roomID string, limit int) ([]*models.Message, error)
-	AddRoomMember(roomID, userID string) error
+	GetMessagesSince(roomID string, afterSeq int64, limit int) ([]*models.Message, error)
+	AddRoomMember(roomID, userID, role string) error
 	IsRoomMember(roomID, userID string) (bool, error)
+	GetRoomMember(roomID, userID string) (*models.RoomMember, error)
 	GetRoomMembers(roomID string) ([]*models.RoomMember, error)
+	ListRoomMembers(roomID string, after *MemberCursor, limit int) ([]*models.RoomMember, error)
+	UpdateRoomMemberRole(roomID, userID, role string) error
+	PinMessage(pin *models.PinnedMessage, maxPins int) error
+	UnpinMessage(roomID, messageID string) error
+	GetPinnedMessages(roomID string) ([]*models.PinnedMessage, error)
+	CountPinnedMessages(roomIDs []string) (map[string]int64, error)
+	CreateModerationItems(items []*models.ModerationItem) error
+	CreateReport(item *models.ModerationItem) error
+	GetModerationItem(itemID string) (*models.ModerationItem, error)
+	GetModerationItems(roomIDs []string, status string, limit int) ([]*models.ModerationItem, error)
+	GetModeratedRoomIDs(userID string) ([]string, error)
+	ClaimModerationItem(itemID, moderatorID string) (*models.ModerationItem, error)
+	ResolveModerationItem(resolution ModerationResolution) (*models.ModerationItem, error)
+	GetModerationActions(roomIDs []string, limit int) ([]*models.ModerationAction, error)
+	IsRoomBanned(roomID, userID string) (bool, error)
+	KickRoomMember(action *models.ModerationAction) error
+	MuteRoomMember(action *models.ModerationAction, until *time.Time) error
+	CreateScheduledMessage(scheduled *models.ScheduledMessage) error
+	GetScheduledMessage(id string) (*models.ScheduledMessage, error)
+	GetScheduledMessages(userID, roomID, status string) ([]*models.ScheduledMessage, error)
+	UpdatePendingScheduledMessage(id string, updates map[string]any) (*models.ScheduledMessage, error)
+	ClaimDueScheduledMessages(now time.Time, lease time.Duration, limit int) ([]*models.ScheduledMessage, error)
+	FinishScheduledMessage(id string, updates map[string]any) error
+	CreatePollMessage(message *models.Message, poll *models.Poll) error
+	GetPoll(pollID string) (*models.Poll, error)
+	SetPollVotes(pollID, userID string, optionIDs []string) (*models.Poll, error)
+	ClosePoll(pollID string) (*models.Poll, error)
+	UpdateRoomSlowMode(roomID string, seconds int) error
+	AcquireSendSlot(roomID, userID string, limits SendLimits, now time.Time) (*SendSlot, error)
+	ReleaseSendSlot(roomID, userID string, limits SendLimits, slot *SendSlot) error
+	CreateWebhook(webhook *models.Webhook) error
+	GetWebhook(webhookID string) (*models.Webhook, error)
+	GetWebhooks(roomID string) ([]*models.Webhook, error)
+	UpdateWebhook(webhookID string, updates map[string]any) (*models.Webhook, error)
+	DeleteWebhook(webhookID string) error
+	EnqueueWebhookDeliveries(roomID, event, payload string) (int64, error)
+	GetWebhookDeliveries(webhookID, status string, limit int) ([]*models.WebhookDelivery, error)
+	CreateIncomingWebhook(hook *models.IncomingWebhook) error
+	GetIncomingWebhook(id string) (*models.IncomingWebhook, error)
+	GetIncomingWebhookByTokenHash(tokenHash string) (*models.IncomingWebhook, error)
+	GetIncomingWebhooks(roomID string) ([]*models.IncomingWebhook, error)
+	RevokeIncomingWebhook(id string) (*models.IncomingWebhook, error)
+	TouchIncomingWebhook(id string, usedAt time.Time) error
+	CreateExportJob(job *models.ExportJob) error
+	GetExportJob(id string) (*models.ExportJob, error)
+	GetExportJobs(roomID string, limit int) ([]*models.ExportJob, error)
+	ClaimExportJob(now time.Time, lease time.Duration) (*models.ExportJob, error)
+	UpdateRunningExportJob(id string, updates map[string]any) error
+	ExpireExportJobs(now time.Time) ([]*models.ExportJob, error)
+	CountDeletedMessages(roomID string) (int64, error)
+	GetDeletedMessages(roomID string, afterSeq int64, limit int) ([]*models.MessageRevision, error)
+	SummarizePurges(roomID string) (*PurgeSummary, error)
+	GetMessageEdits(messageIDs []string) ([]*models.MessageRevision, error)
+	GetRoomByExternalID(externalID string) (*models.Room, error)
+	GetImportedMessageIDs(roomID string, externalIDs []string) (map[string]string, error)
+	ImportMessages(roomID string, messages []*ImportMessage) (*ImportResult, error)
+	GetReferencingRoomIDs(messageID string) ([]string, error)
+	SaveBookmark(bookmark *models.Bookmark) (*models.Bookmark, error)
+	DeleteBookmark(userID, messageID string) (*models.Bookmark, error)
+	GetBookmarks(userID, folder string, after *BookmarkCursor, limit int) ([]*models.Bookmark, error)
+	GetBookmarkFolders(userID string) ([]*models.BookmarkFolder, error)
+	UpdateRoomNotifications(roomID, userID, level string, mutedUntil *time.Time) (*models.RoomMember, error)
+	GetNotificationSettings(userID string) (*models.NotificationSettings, error)
+	SaveNotificationSettings(settings *models.NotificationSettings) error
+	GetNotificationRecipients(roomID, authorID string, now time.Time) ([]*NotificationRecipient, error)
+	RegisterPushDevice(device *models.PushDevice) (*models.PushDevice, error)
+	GetPushDevices(userID string) ([]*models.PushDevice, error)
+	DeletePushDevice(userID, deviceID string) error
+	EnqueuePushJobs(job *models.PushJob, userIDs []string) (int64, error)
+	UpdateRoomMessageTTL(roomID string, seconds int) error
+	DeleteExpiredMessages(limit int) ([]*models.Message, error)
+	EnqueueDigestEntries(entry *models.DigestEntry, userIDs []string) error
+	ClearDigestEntries(userID, roomID string) error
+	ClaimDueDigests(now time.Time, offlineAfter, period, lease time.Duration, limit int) ([]string, error)
+	GetDigestItems(userID string) ([]*DigestItem, error)
+	FinishDigest(userID string, entryIDs []string, sentAt time.Time) error
+	MarkDigestUserSeen(userID string, seenAt time.Time) error
+	SetDigestOptOut(userID string, optOut bool) error
 }
 
 type chatRepository struct {
 	db *gorm.DB
 }
 
-func NewChatRepository(db *gorm.DB) ChatRepository {
-	return &chatRepository{db: db}
+// NewChatRepository returns an unscoped repository, and installs the tenant
+// scope that repositories returned by ForOrg rely on.
+func NewChatRepository(db *gorm.DB) (ChatRepository, error) {
+	if _, ok := db.Config.Plugins[tenantScope{}.Name()]; !ok {
+		if err := db.Use(tenantScope{}); err != nil {
+			return nil, err
+		}
+	}
+	return &chatRepository{db: db}, nil
 }
 
 func (r *chatRepository) CreateRoom(room *models.Room) error {
@@ -52,27 +182,366 @@ func (r *chatRepository) GetRoomByID(roomID string) (*models.Room, error) {
 	return &room, nil
 }
 
-func (r *chatRepository) GetAllRooms() ([]*models.Room, error) {
+func (r *chatRepository) GetAllRooms(includeArchived bool) ([]*models.Room, error) {
 	var rooms []*models.Room
-	result := r.db.Find(&rooms)
+	query := r.db
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	result := query.Find(&rooms)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return rooms, nil
 }
 
+func (r *chatRepository) UpdateRoom(roomID string, updates map[string]any) (*models.Room, error) {
+	room, err := r.GetRoomByID(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := r.db.Model(room).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return room, nil
+}
+
+func (r *chatRepository) ArchiveRoom(roomID string) (*models.Room, error) {
+	return r.UpdateRoom(roomID, map[string]any{"archived_at": time.Now()})
+}
+
+// DeleteRoom removes the room together with its messages, members and pins.
+func (r *chatRepository) DeleteRoom(roomID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		dependents := []any{
+			&models.PinnedMessage{},
+			&models.Message{},
+			&models.RoomMember{},
+			&models.ArchivedMessage{},
+			&models.PurgeRecord{},
+			&models.SlowModeEntry{},
+			&models.ModerationItem{},
+			&models.ModerationAction{},
+			&models.RoomBan{},
+			&models.ScheduledMessage{},
+			&models.Webhook{},
+			&models.IncomingWebhook{},
+			&models.MessageRevision{},
+			&models.ExportJob{},
+			&models.Bookmark{},
+			&models.PushJob{},
+			&models.DigestEntry{},
+		}
+		for _, model := range dependents {
+			if err := tx.Where("room_id = ?", roomID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Delete(&models.Room{}, "id = ?", roomID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRoomNotFound
+		}
+		return nil
+	})
+}
+
+func (r *chatRepository) UpdateRoomSlowMode(roomID string, seconds int) error {
+	result := r.db.Model(&models.Room{ID: roomID}).Update("slow_mode_seconds", seconds)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoomNotFound
+	}
+	return nil
+}
+
+func (r *chatRepository) UpdateRoomRetention(roomID string, policy models.RetentionPolicy) error {
+	result := r.db.Model(&models.Room{ID: roomID}).Updates(map[string]any{
+		"retention_type":  policy.Type,
+		"retention_value": policy.Value,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoomNotFound
+	}
+	return nil
+}
+
+// UpdateRoomModeration stores the room's filters; nil clears the override.
+func (r *chatRepository) UpdateRoomModeration(roomID string, filters []moderation.FilterConfig) error {
+	room := &models.Room{ID: roomID, ModerationFilters: filters}
+	result := r.db.Model(room).Select("moderation_filters", "updated_at").Updates(room)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoomNotFound
+	}
+	return nil
+}
+
 func (r *chatRepository) CreateMessage(message *models.Message) error {
-	result := r.db.Create(message)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createMessage(tx, message)
+	})
+}
+
+// createMessage assigns the next room sequence number and inserts the message.
+// The room row stays locked until tx commits, so numbers are gap-free and
+// concurrent senders are serialised per room.
+func createMessage(tx *gorm.DB, message *models.Message) error {
+	var seq int64
+	result := tx.Raw("UPDATE rooms SET last_seq = last_seq + 1 WHERE id = ? RETURNING last_seq", message.RoomID).
+		Scan(&seq)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoomNotFound
+	}
+
+	message.Seq = seq
+	return tx.Create(message).Error
+}
+
+// CreateMessageIdempotent creates the message unless the same user already sent
+// one with the same client message ID to the room within window. It returns the
+// stored message and whether it was newly created. A key claimed by a slash
+// command that has not posted yet is taken by the message the command posts.
+func (r *chatRepository) CreateMessageIdempotent(message *models.Message, window time.Duration) (*models.Message, bool, error) {
+	if message.ClientMessageID == "" {
+		if err := r.CreateMessage(message); err != nil {
+			return nil, false, err
+		}
+		return message, true, nil
+	}
+
+	var stored *models.Message
+	created := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		keyQuery := tx.Where("room_id = ? AND user_id = ? AND client_message_id = ?",
+			message.RoomID, message.UserID, message.ClientMessageID).
+			Session(&gorm.Session{})
+
+		// An expired key no longer deduplicates, so make room for a new one
+		result := keyQuery.Where("created_at < ?", time.Now().Add(-window)).
+			Delete(&models.MessageIdempotencyKey{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		key := &models.MessageIdempotencyKey{
+			RoomID:          message.RoomID,
+			UserID:          message.UserID,
+			ClientMessageID: message.ClientMessageID,
+			CreatedAt:       time.Now(),
+		}
+
+		// A concurrent retry blocks here on the primary key until the first
+		// transaction commits, and then sees the conflict
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			var existing models.MessageIdempotencyKey
+			if err := keyQuery.First(&existing).Error; err != nil {
+				return err
+			}
+			if existing.MessageID == nil {
+				if err := createIdempotentMessage(tx, keyQuery, message); err != nil {
+					return err
+				}
+				stored = message
+				created = true
+				return nil
+			}
+
+			var original models.Message
+			if err := tx.First(&original, "id = ?", *existing.MessageID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrMessageNotFound
+				}
+				return err
+			}
+			stored = &original
+			return nil
+		}
+
+		if err := createIdempotentMessage(tx, keyQuery, message); err != nil {
+			return err
+		}
+		stored = message
+		created = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return stored, created, nil
+}
+
+// createIdempotentMessage creates the message and points its key at it.
+func createIdempotentMessage(tx *gorm.DB, keyQuery *gorm.DB, message *models.Message) error {
+	if err := createMessage(tx, message); err != nil {
+		return err
+	}
+	return keyQuery.Model(&models.MessageIdempotencyKey{}).
+		Update("message_id", message.ID).Error
+}
+
+// ClaimIdempotencyKey records a client message ID for a slash command before
+// it runs. It reports false if the ID was already used within window, in
+// which case the command must not run again.
+func (r *chatRepository) ClaimIdempotencyKey(roomID, userID, clientMessageID string, window time.Duration) (bool, error) {
+	claimed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		keyQuery := tx.Where("room_id = ? AND user_id = ? AND client_message_id = ?",
+			roomID, userID, clientMessageID).
+			Session(&gorm.Session{})
+
+		result := keyQuery.Where("created_at < ?", time.Now().Add(-window)).
+			Delete(&models.MessageIdempotencyKey{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.MessageIdempotencyKey{
+			RoomID:          roomID,
+			UserID:          userID,
+			ClientMessageID: clientMessageID,
+			CreatedAt:       time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		claimed = result.RowsAffected > 0
+		return nil
+	})
+	return claimed, err
+}
+
+// ReleaseIdempotencyKey frees a key claimed by a slash command that failed,
+// so a retry runs it again. Keys that produced a message are kept.
+func (r *chatRepository) ReleaseIdempotencyKey(roomID, userID, clientMessageID string) error {
+	return r.db.Where("room_id = ? AND user_id = ? AND client_message_id = ? AND message_id IS NULL",
+		roomID, userID, clientMessageID).
+		Delete(&models.MessageIdempotencyKey{}).Error
+}
+
+// FindIdempotentMessage returns the message an unexpired client message ID
+// produced, or nil if there is none.
+func (r *chatRepository) FindIdempotentMessage(roomID, userID, clientMessageID string, window time.Duration) (*models.Message, error) {
+	var key models.MessageIdempotencyKey
+	result := r.db.Where("room_id = ? AND user_id = ? AND client_message_id = ? AND created_at >= ?",
+		roomID, userID, clientMessageID, time.Now().Add(-window)).
+		Limit(1).
+		Find(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 || key.MessageID == nil {
+		return nil, nil
+	}
+
+	var message models.Message
+	result = r.db.Limit(1).Find(&message, "id = ?", *key.MessageID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &message, nil
+}
+
+func (r *chatRepository) DeleteExpiredIdempotencyKeys(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.MessageIdempotencyKey{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *chatRepository) GetMessageByID(messageID string) (*models.Message, error) {
+	var message models.Message
+	result := r.db.Scopes(unexpired).First(&message, "id = ?", messageID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, result.Error
+	}
+	if err := attachReferences(r.db, []*models.Message{&message}); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// UpdateMessageContent saves an edited message's content and rendering, and
+// the revision holding its previous content.
+func (r *chatRepository) UpdateMessageContent(message *models.Message, revision *models.MessageRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(message).
+			Select("content", "plain_text", "entities", "edited_at").
+			Updates(message)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMessageNotFound
+		}
+		return tx.Create(revision).Error
+	})
+}
+
+// DeleteMessage deletes the revision's message and keeps the revision as its
+// record.
+func (r *chatRepository) DeleteMessage(revision *models.MessageRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteMessage(tx, revision)
+	})
+}
+
+// deleteMessage deletes the revision's message within tx, records the
+// revision and drops the notifications still pending for the message.
+// Bookmarks stay and are listed without the message.
+func deleteMessage(tx *gorm.DB, revision *models.MessageRevision) error {
+	result := tx.Delete(&models.Message{}, "id = ?", revision.MessageID)
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return ErrMessageNotFound
+	}
+	if err := tx.Create(revision).Error; err != nil {
+		return err
+	}
+	for _, model := range []any{&models.DigestEntry{}, &models.PushJob{}} {
+		if err := tx.Where("message_id = ?", revision.MessageID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (r *chatRepository) GetMessagesByRoomID(roomID string, limit int) ([]*models.Message, error) {
 	var messages []*models.Message
-	result := r.db.Where("room_id = ?", roomID).
-		Order("created_at DESC").
+	result := r.db.Scopes(unexpired).
+		Where("room_id = ?", roomID).
+		Order("seq DESC").
 		Limit(limit).
 		Find(&messages)
 	if result.Error != nil {
@@ -84,10 +553,42 @@ func (r *chatRepository) GetMessagesByRoomID(roomID string, limit int) ([]*model
 		messages[i], messages[j] = messages[j], messages[i]
 	}
 
+	if err := attachPolls(r.db, messages); err != nil {
+		return nil, err
+	}
+	if err := attachReactions(r.db, messages); err != nil {
+		return nil, err
+	}
+	if err := attachReferences(r.db, messages); err != nil {
+		return nil, err
+	}
+
 	return messages, nil
 }
 
-func (r *chatRepository) AddRoomMember(roomID, userID string) error {
+func (r *chatRepository) GetMessagesSince(roomID string, afterSeq int64, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+	result := r.db.Scopes(unexpired).
+		Where("room_id = ? AND seq > ?", roomID, afterSeq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := attachPolls(r.db, messages); err != nil {
+		return nil, err
+	}
+	if err := attachReactions(r.db, messages); err != nil {
+		return nil, err
+	}
+	if err := attachReferences(r.db, messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *chatRepository) AddRoomMember(roomID, userID, role string) error {
 	// Check if already a member
 	var existingMember models.RoomMember
 	result := r.db.Where("room_id = ? AND user_id = ?", roomID, userID).
@@ -103,6 +604,7 @@ func (r *chatRepository) AddRoomMember(roomID, userID string) error {
 	member := &models.RoomMember{
 		RoomID: roomID,
 		UserID: userID,
+		Role:   role,
 	}
 
 	result = r.db.Create(member)
@@ -116,7 +618,7 @@ func (r *chatRepository) IsRoomMember(roomID, userID string) (bool, error) {
 	var member models.RoomMember
 	result := r.db.Where("room_id = ? AND user_id = ?", roomID, userID).
 		First(&member)
-		
+
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return false, nil
@@ -126,6 +628,19 @@ func (r *chatRepository) IsRoomMember(roomID, userID string) (bool, error) {
 	return true, nil
 }
 
+func (r *chatRepository) GetRoomMember(roomID, userID string) (*models.RoomMember, error) {
+	var member models.RoomMember
+	result := r.db.Where("room_id = ? AND user_id = ?", roomID, userID).
+		First(&member)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, result.Error
+	}
+	return &member, nil
+}
+
 func (r *chatRepository) GetRoomMembers(roomID string) ([]*models.RoomMember, error) {
 	var members []*models.RoomMember
 	result := r.db.Where("room_id = ?", roomID).Find(&members)
@@ -133,4 +648,131 @@ func (r *chatRepository) GetRoomMembers(roomID string) ([]*models.RoomMember, er
 		return nil, result.Error
 	}
 	return members, nil
+}
+
+// MemberCursor is the position of the last member of a page.
+type MemberCursor struct {
+	JoinedAt time.Time
+	ID       string
+}
+
+// ListRoomMembers returns up to limit members in the order they joined,
+// starting after the cursor when one is given.
+func (r *chatRepository) ListRoomMembers(roomID string, after *MemberCursor, limit int) ([]*models.RoomMember, error) {
+	var members []*models.RoomMember
+	query := r.db.Where("room_id = ?", roomID)
+	if after != nil {
+		// Comparing the ID as text keeps a tampered cursor from failing the
+		// query; for canonical UUIDs it orders the same way
+		query = query.Where("(joined_at, id::text) > (?, ?)", after.JoinedAt, after.ID)
+	}
+	result := query.Order("joined_at ASC, id ASC").Limit(limit).Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+func (r *chatRepository) UpdateRoomMemberRole(roomID, userID, role string) error {
+	result := r.db.Model(&models.RoomMember{}).
+		Where("room_id = ? AND user_id = ?", roomID, userID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// PinMessage pins a message while holding a lock on the room row, so that
+// concurrent pins cannot push the room over maxPins.
+func (r *chatRepository) PinMessage(pin *models.PinnedMessage, maxPins int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var room models.Room
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&room, "id = ?", pin.RoomID)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrRoomNotFound
+			}
+			return result.Error
+		}
+
+		var existing int64
+		result = tx.Model(&models.PinnedMessage{}).
+			Where("room_id = ? AND message_id = ?", pin.RoomID, pin.MessageID).
+			Count(&existing)
+		if result.Error != nil {
+			return result.Error
+		}
+		if existing > 0 {
+			return ErrAlreadyPinned
+		}
+
+		var count int64
+		result = tx.Model(&models.PinnedMessage{}).
+			Where("room_id = ?", pin.RoomID).
+			Count(&count)
+		if result.Error != nil {
+			return result.Error
+		}
+		if maxPins > 0 && count >= int64(maxPins) {
+			return ErrPinLimitReached
+		}
+
+		return tx.Omit("Message").Create(pin).Error
+	})
+}
+
+func (r *chatRepository) UnpinMessage(roomID, messageID string) error {
+	result := r.db.Where("room_id = ? AND message_id = ?", roomID, messageID).
+		Delete(&models.PinnedMessage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPinNotFound
+	}
+	return nil
+}
+
+func (r *chatRepository) GetPinnedMessages(roomID string) ([]*models.PinnedMessage, error) {
+	var pins []*models.PinnedMessage
+	result := r.db.Preload("Message").
+		Joins("JOIN messages ON messages.id = pinned_messages.message_id").
+		Scopes(unexpired).
+		Where("pinned_messages.room_id = ?", roomID).
+		Order("pinned_at DESC").
+		Find(&pins)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return pins, nil
+}
+
+func (r *chatRepository) CountPinnedMessages(roomIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(roomIDs))
+	if len(roomIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		RoomID string
+		Count  int64
+	}
+	result := r.db.Model(&models.PinnedMessage{}).
+		Select("room_id, COUNT(*) AS count").
+		Where("room_id IN ?", roomIDs).
+		Group("room_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, row := range rows {
+		counts[row.RoomID] = row.Count
+	}
+	return counts, nil
 }
\ No newline at end of file
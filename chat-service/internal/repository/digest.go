@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DigestItem is a queued digest entry with its message and the name of its
+// room. Message is nil if the message was deleted since.
+type DigestItem struct {
+	Entry    *models.DigestEntry
+	Message  *models.Message
+	RoomName string
+}
+
+// EnqueueDigestEntries keeps a copy of the entry for each of the users'
+// next digests.
+func (r *chatRepository) EnqueueDigestEntries(entry *models.DigestEntry, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	entries := make([]*models.DigestEntry, len(userIDs))
+	for i, userID := range userIDs {
+		entries[i] = &models.DigestEntry{
+			UserID:    userID,
+			RoomID:    entry.RoomID,
+			MessageID: entry.MessageID,
+			Mention:   entry.Mention,
+		}
+	}
+	return r.db.Create(&entries).Error
+}
+
+// ClearDigestEntries drops the user's entries of a room they have caught up
+// with.
+func (r *chatRepository) ClearDigestEntries(userID, roomID string) error {
+	return r.db.Where("user_id = ? AND room_id = ?", userID, roomID).Delete(&models.DigestEntry{}).Error
+}
+
+// ClaimDueDigests leases the digests of up to limit users whose oldest entry
+// is at least offlineAfter old, who were not seen online in that time, and
+// who were not sent a digest within period. Users who opted out are
+// skipped. It returns the IDs of the claimed users.
+func (r *chatRepository) ClaimDueDigests(now time.Time, offlineAfter, period, lease time.Duration, limit int) ([]string, error) {
+	var userIDs []string
+	result := r.db.Raw(`
+		INSERT INTO digest_states (user_id, lease_until)
+		SELECT e.user_id, ?
+		FROM digest_entries e
+		LEFT JOIN digest_states s ON s.user_id = e.user_id
+		LEFT JOIN notification_settings n ON n.user_id = e.user_id
+		WHERE (n.digest_opt_out IS NULL OR NOT n.digest_opt_out)
+			AND (s.last_seen_at IS NULL OR s.last_seen_at <= ?)
+			AND (s.last_sent_at IS NULL OR s.last_sent_at <= ?)
+			AND (s.lease_until IS NULL OR s.lease_until < ?)
+		GROUP BY e.user_id
+		HAVING MIN(e.created_at) <= ?
+		ORDER BY MIN(e.created_at)
+		LIMIT ?
+		ON CONFLICT (user_id) DO UPDATE SET lease_until = EXCLUDED.lease_until
+		WHERE digest_states.lease_until IS NULL OR digest_states.lease_until < ?
+		RETURNING user_id`,
+		now.Add(lease), now.Add(-offlineAfter), now.Add(-period), now, now.Add(-offlineAfter), limit, now).
+		Scan(&userIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return userIDs, nil
+}
+
+// GetDigestItems returns the user's queued entries in the order they were
+// queued, with their messages and room names.
+func (r *chatRepository) GetDigestItems(userID string) ([]*DigestItem, error) {
+	var entries []*models.DigestEntry
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	messageIDs := make([]string, len(entries))
+	roomIDs := make([]string, 0, len(entries))
+	seenRooms := make(map[string]bool)
+	for i, entry := range entries {
+		messageIDs[i] = entry.MessageID
+		if !seenRooms[entry.RoomID] {
+			seenRooms[entry.RoomID] = true
+			roomIDs = append(roomIDs, entry.RoomID)
+		}
+	}
+
+	var messages []*models.Message
+	if err := r.db.Scopes(unexpired).Where("id IN ?", messageIDs).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+
+	var rooms []*models.Room
+	if err := r.db.Select("id, name").Where("id IN ?", roomIDs).Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+	roomNames := make(map[string]string, len(rooms))
+	for _, room := range rooms {
+		roomNames[room.ID] = room.Name
+	}
+
+	items := make([]*DigestItem, len(entries))
+	for i, entry := range entries {
+		items[i] = &DigestItem{
+			Entry:    entry,
+			Message:  byID[entry.MessageID],
+			RoomName: roomNames[entry.RoomID],
+		}
+	}
+	return items, nil
+}
+
+// FinishDigest drops the entries a digest covered, records when it was sent
+// and releases the user's lease.
+func (r *chatRepository) FinishDigest(userID string, entryIDs []string, sentAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(entryIDs) > 0 {
+			if err := tx.Where("id IN ?", entryIDs).Delete(&models.DigestEntry{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.DigestState{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]any{
+				"last_sent_at": sentAt,
+				"lease_until":  nil,
+			}).Error
+	})
+}
+
+// MarkDigestUserSeen records that the user was online, which puts off their
+// digest, and releases their lease.
+func (r *chatRepository) MarkDigestUserSeen(userID string, seenAt time.Time) error {
+	return r.db.Model(&models.DigestState{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"last_seen_at": seenAt,
+			"lease_until":  nil,
+		}).Error
+}
+
+// SetDigestOptOut stops or resumes the user's email digests. Opting out
+// drops their queued entries.
+func (r *chatRepository) SetDigestOptOut(userID string, optOut bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		settings := &models.NotificationSettings{
+			UserID:       userID,
+			DigestOptOut: optOut,
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"digest_opt_out", "updated_at"}),
+		}).Create(settings).Error
+		if err != nil || !optOut {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.DigestEntry{}).Error
+	})
+}
\ No newline at end of file
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// unexpired leaves out messages whose time-to-live has run out but which
+// the reaper has not deleted yet. It goes by the database clock, like the
+// reaper, so that replicas with skewed clocks agree on what has expired.
+func unexpired(db *gorm.DB) *gorm.DB {
+	return db.Where("messages.expires_at IS NULL OR messages.expires_at > NOW()")
+}
+
+func (r *chatRepository) UpdateRoomMessageTTL(roomID string, seconds int) error {
+	result := r.db.Model(&models.Room{ID: roomID}).Update("message_ttl_seconds", seconds)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoomNotFound
+	}
+	return nil
+}
+
+// DeleteExpiredMessages deletes up to limit messages that expired by the
+// database clock, together with everything that would keep their content
+// around: their edit history, bookmarks and queued notifications. Reactions,
+// pins and polls go with the messages. Each message leaves an expire revision
+// without content, so exports can account for it.
+func (r *chatRepository) DeleteExpiredMessages(limit int) ([]*models.Message, error) {
+	var expired []*models.Message
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Raw(`
+			DELETE FROM messages WHERE id IN (
+				SELECT id FROM messages
+				WHERE expires_at <= NOW()
+				ORDER BY expires_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *`,
+			limit).
+			Scan(&expired)
+		if result.Error != nil || len(expired) == 0 {
+			return result.Error
+		}
+
+		messageIDs := make([]string, len(expired))
+		for i, message := range expired {
+			messageIDs[i] = message.ID
+		}
+		dependents := []any{
+			&models.MessageRevision{},
+			&models.Bookmark{},
+			&models.DigestEntry{},
+			&models.PushJob{},
+		}
+		for _, model := range dependents {
+			if err := tx.Where("message_id IN ?", messageIDs).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		revisions := make([]*models.MessageRevision, len(expired))
+		for i, message := range expired {
+			revisions[i] = models.NewMessageRevision(message, models.MessageRevisionExpire, "")
+			revisions[i].Content = ""
+		}
+		return tx.Create(&revisions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
\ No newline at end of file
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+)
+
+func (r *chatRepository) CreateExportJob(job *models.ExportJob) error {
+	result := r.db.Create(job)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *chatRepository) GetExportJob(id string) (*models.ExportJob, error) {
+	var job models.ExportJob
+	result := r.db.First(&job, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrExportJobNotFound
+		}
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+// GetExportJobs returns a room's most recent export jobs first.
+func (r *chatRepository) GetExportJobs(roomID string, limit int) ([]*models.ExportJob, error) {
+	var jobs []*models.ExportJob
+	result := r.db.Where("room_id = ?", roomID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return jobs, nil
+}
+
+// ClaimExportJob leases the oldest pending export job to the caller, or one
+// whose lease ran out because the replica running it stopped. It returns nil
+// when there is nothing to do.
+func (r *chatRepository) ClaimExportJob(now time.Time, lease time.Duration) (*models.ExportJob, error) {
+	var claimed []*models.ExportJob
+	result := r.db.Raw(`
+		UPDATE export_jobs
+		SET status = ?, lease_until = ?, attempts = attempts + 1,
+			started_at = COALESCE(started_at, ?), updated_at = ?
+		WHERE id IN (
+			SELECT id FROM export_jobs
+			WHERE status = ? OR (status = ? AND lease_until < ?)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.ExportStatusRunning, now.Add(lease), now, now,
+		models.ExportStatusPending, models.ExportStatusRunning, now).
+		Scan(&claimed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+	return claimed[0], nil
+}
+
+// UpdateRunningExportJob updates a job that is still running. It returns
+// ErrExportJobNotFound if the job was removed or finished in the meantime.
+func (r *chatRepository) UpdateRunningExportJob(id string, updates map[string]any) error {
+	result := r.db.Model(&models.ExportJob{ID: id}).
+		Where("status = ?", models.ExportStatusRunning).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrExportJobNotFound
+	}
+	return nil
+}
+
+// ExpireExportJobs marks completed jobs past their expiry as expired and
+// returns them, so their files can be removed.
+func (r *chatRepository) ExpireExportJobs(now time.Time) ([]*models.ExportJob, error) {
+	var expired []*models.ExportJob
+	result := r.db.Raw(`
+		UPDATE export_jobs
+		SET status = ?, updated_at = ?
+		WHERE status = ? AND expires_at < ?
+		RETURNING *`,
+		models.ExportStatusExpired, now,
+		models.ExportStatusCompleted, now).
+		Scan(&expired)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return expired, nil
+}
+
+// removalActions are the revisions that record a message's removal.
+var removalActions = []string{models.MessageRevisionDelete, models.MessageRevisionExpire}
+
+func (r *chatRepository) CountDeletedMessages(roomID string) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.MessageRevision{}).
+		Where("room_id = ? AND action IN ?", roomID, removalActions).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// GetDeletedMessages returns the deletion and expiry records of a room's
+// messages in sequence order, starting after afterSeq.
+func (r *chatRepository) GetDeletedMessages(roomID string, afterSeq int64, limit int) ([]*models.MessageRevision, error) {
+	var deleted []*models.MessageRevision
+	result := r.db.Where("room_id = ? AND action IN ? AND seq > ?", roomID, removalActions, afterSeq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&deleted)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deleted, nil
+}
+
+// GetMessageEdits returns the edits of the given messages, oldest first.
+func (r *chatRepository) GetMessageEdits(messageIDs []string) ([]*models.MessageRevision, error) {
+	var edits []*models.MessageRevision
+	if len(messageIDs) == 0 {
+		return edits, nil
+	}
+	result := r.db.Where("message_id IN ? AND action = ?", messageIDs, models.MessageRevisionEdit).
+		Order("created_at ASC").
+		Find(&edits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return edits, nil
+}
+
+// PurgeSummary sums up what the retention job removed from a room.
+type PurgeSummary struct {
+	MessagesRemoved int64
+	NewestRemovedAt *time.Time
+}
+
+// SummarizePurges totals the room's purge records. It returns nil if the
+// retention job never removed anything from the room.
+func (r *chatRepository) SummarizePurges(roomID string) (*PurgeSummary, error) {
+	var summary PurgeSummary
+	result := r.db.Model(&models.PurgeRecord{}).
+		Select("COALESCE(SUM(messages_removed), 0) AS messages_removed, MAX(newest_removed_at) AS newest_removed_at").
+		Where("room_id = ?", roomID).
+		Scan(&summary)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if summary.MessagesRemoved == 0 {
+		return nil, nil
+	}
+	return &summary, nil
+}
\ No newline at end of file
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"errors"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportMessage is a message imported from another chat system, with the
+// external ID of the thread it replies to and its reactions.
+type ImportMessage struct {
+	Message          *models.Message
+	ParentExternalID string
+	Reactions        []*models.Reaction
+}
+
+// ImportResult counts what ImportMessages did.
+type ImportResult struct {
+	Created int
+	// Skipped messages had been imported before.
+	Skipped   int
+	Reactions int64
+	// Orphans are replies whose thread is not in the room; they are kept as
+	// top-level messages.
+	Orphans int
+}
+
+func (r *chatRepository) GetRoomByExternalID(externalID string) (*models.Room, error) {
+	var room models.Room
+	result := r.db.First(&room, "external_id = ?", externalID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, result.Error
+	}
+	return &room, nil
+}
+
+// GetImportedMessageIDs maps the external IDs of messages already imported
+// into the room to their IDs.
+func (r *chatRepository) GetImportedMessageIDs(roomID string, externalIDs []string) (map[string]string, error) {
+	return importedMessageIDs(r.db, roomID, externalIDs)
+}
+
+// ImportMessages adds messages to the room in the given order, which should
+// be the order they were sent in. Messages imported before are skipped, but
+// their reactions are still added, so re-running an import with a newer
+// archive picks up new reactions.
+func (r *chatRepository) ImportMessages(roomID string, messages []*ImportMessage) (*ImportResult, error) {
+	importResult := &ImportResult{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		externalIDs := make([]string, 0, 2*len(messages))
+		for _, message := range messages {
+			externalIDs = append(externalIDs, message.Message.ExternalID)
+			if message.ParentExternalID != "" {
+				externalIDs = append(externalIDs, message.ParentExternalID)
+			}
+		}
+		ids, err := importedMessageIDs(tx, roomID, externalIDs)
+		if err != nil {
+			return err
+		}
+
+		var reactions []*models.Reaction
+		for _, message := range messages {
+			if id, ok := ids[message.Message.ExternalID]; ok {
+				message.Message.ID = id
+				importResult.Skipped++
+			} else {
+				if message.ParentExternalID != "" {
+					if parentID, ok := ids[message.ParentExternalID]; ok {
+						message.Message.ParentID = &parentID
+					} else {
+						importResult.Orphans++
+					}
+				}
+
+				message.Message.RoomID = roomID
+				if err := createMessage(tx, message.Message); err != nil {
+					return err
+				}
+				ids[message.Message.ExternalID] = message.Message.ID
+				importResult.Created++
+			}
+
+			for _, reaction := range message.Reactions {
+				reaction.MessageID = message.Message.ID
+				reactions = append(reactions, reaction)
+			}
+		}
+
+		if len(reactions) == 0 {
+			return nil
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reactions)
+		if result.Error != nil {
+			return result.Error
+		}
+		importResult.Reactions = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return importResult, nil
+}
+
+func importedMessageIDs(db *gorm.DB, roomID string, externalIDs []string) (map[string]string, error) {
+	ids := make(map[string]string, len(externalIDs))
+	if len(externalIDs) == 0 {
+		return ids, nil
+	}
+
+	var rows []struct {
+		ID         string
+		ExternalID string
+	}
+	result := db.Model(&models.Message{}).
+		Select("id, external_id").
+		Where("room_id = ? AND external_id IN ?", roomID, externalIDs).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range rows {
+		ids[row.ExternalID] = row.ID
+	}
+	return ids, nil
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// CreateIncomingWebhook stores the webhook and adds its bot user to the room.
+func (r *chatRepository) CreateIncomingWebhook(hook *models.IncomingWebhook) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(hook).Error; err != nil {
+			return err
+		}
+
+		member := &models.RoomMember{
+			RoomID: hook.RoomID,
+			UserID: hook.BotUserID(),
+			Role:   models.RoleMember,
+		}
+		return tx.Create(member).Error
+	})
+}
+
+func (r *chatRepository) GetIncomingWebhook(id string) (*models.IncomingWebhook, error) {
+	return findIncomingWebhook(r.db, "id = ?", id)
+}
+
+func (r *chatRepository) GetIncomingWebhookByTokenHash(tokenHash string) (*models.IncomingWebhook, error) {
+	return findIncomingWebhook(r.db, "token_hash = ?", tokenHash)
+}
+
+func (r *chatRepository) GetIncomingWebhooks(roomID string) ([]*models.IncomingWebhook, error) {
+	var hooks []*models.IncomingWebhook
+	result := r.db.Where("room_id = ?", roomID).Order("created_at ASC").Find(&hooks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return hooks, nil
+}
+
+// RevokeIncomingWebhook disables the token for good and removes the bot user
+// from the room. Messages it already posted are kept.
+func (r *chatRepository) RevokeIncomingWebhook(id string) (*models.IncomingWebhook, error) {
+	var hook *models.IncomingWebhook
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		found, err := findIncomingWebhook(tx, "id = ?", id)
+		if err != nil {
+			return err
+		}
+		hook = found
+		if hook.IsRevoked() {
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Model(hook).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		hook.RevokedAt = &now
+
+		return tx.Where("room_id = ? AND user_id = ?", hook.RoomID, hook.BotUserID()).
+			Delete(&models.RoomMember{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (r *chatRepository) TouchIncomingWebhook(id string, usedAt time.Time) error {
+	return r.db.Model(&models.IncomingWebhook{ID: id}).Update("last_used_at", usedAt).Error
+}
+
+func findIncomingWebhook(db *gorm.DB, query string, args ...any) (*models.IncomingWebhook, error) {
+	var hook models.IncomingWebhook
+	result := db.Where(query, args...).First(&hook)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrIncomingWebhookNotFound
+		}
+		return nil, result.Error
+	}
+	return &hook, nil
+}
\ No newline at end of file
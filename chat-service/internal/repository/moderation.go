@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ModerationResolution describes how a moderator resolves a queue item.
+type ModerationResolution struct {
+	ItemID      string
+	ModeratorID string
+	Action      string
+	Note        string
+	// MuteFor is the length of a mute resolution.
+	MuteFor time.Duration
+	// Override lets the moderator resolve an item someone else has claimed.
+	Override bool
+}
+
+func (r *chatRepository) CreateModerationItems(items []*models.ModerationItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	result := r.db.Omit("Message").Create(&items)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// CreateReport queues a user report. Each user can report a message once.
+func (r *chatRepository) CreateReport(item *models.ModerationItem) error {
+	result := r.db.Omit("Message").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(item)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyReported
+	}
+	return nil
+}
+
+func (r *chatRepository) GetModerationItem(itemID string) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+	result := r.db.Preload("Message").First(&item, "id = ?", itemID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrModerationItemNotFound
+		}
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+// GetModerationItems lists queue items, newest first. A nil roomIDs lists
+// every room and an empty status lists every status.
+func (r *chatRepository) GetModerationItems(roomIDs []string, status string, limit int) ([]*models.ModerationItem, error) {
+	var items []*models.ModerationItem
+	query := r.db.Preload("Message")
+	if roomIDs != nil {
+		query = query.Where("room_id IN ?", roomIDs)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("created_at DESC").Limit(limit).Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+// GetModeratedRoomIDs returns the rooms the user owns or moderates.
+func (r *chatRepository) GetModeratedRoomIDs(userID string) ([]string, error) {
+	var roomIDs []string
+	query := `
+		SELECT id FROM rooms WHERE created_by = ?
+		UNION
+		SELECT room_id FROM room_members WHERE user_id = ? AND role IN ?`
+	args := []any{userID, userID, []string{models.RoleOwner, models.RoleModerator}}
+	if orgID := r.orgID(); orgID != "" {
+		query = `SELECT id FROM (` + query + `) AS moderated
+		WHERE id::text IN (SELECT id::text FROM rooms WHERE org_id = ?)`
+		args = append(args, orgID)
+	}
+	result := r.db.Raw(query, args...).Scan(&roomIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roomIDs, nil
+}
+
+// ClaimModerationItem assigns an open item to the moderator and records the
+// claim in the audit log.
+func (r *chatRepository) ClaimModerationItem(itemID, moderatorID string) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockModerationItem(tx, &item, itemID); err != nil {
+			return err
+		}
+		if !item.IsOpen() {
+			return ErrItemResolved
+		}
+		if item.ClaimedBy == moderatorID {
+			return nil
+		}
+		if item.ClaimedBy != "" {
+			return ErrItemClaimed
+		}
+
+		now := time.Now()
+		item.Status = models.ModerationStatusClaimed
+		item.ClaimedBy = moderatorID
+		item.ClaimedAt = &now
+		if err := tx.Omit("Message").Save(&item).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.ModerationAction{
+			RoomID:       item.RoomID,
+			ItemID:       &item.ID,
+			ModeratorID:  moderatorID,
+			Action:       models.ModerationActionClaim,
+			TargetUserID: item.AuthorID,
+			MessageID:    derefString(item.MessageID),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ResolveModerationItem applies the resolution, closes the item and records
+// the action in the audit log, all in one transaction. Deleting the message
+// also closes every other open item about it.
+func (r *chatRepository) ResolveModerationItem(resolution ModerationResolution) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockModerationItem(tx, &item, resolution.ItemID); err != nil {
+			return err
+		}
+		if !item.IsOpen() {
+			return ErrItemResolved
+		}
+		if item.ClaimedBy != "" && item.ClaimedBy != resolution.ModeratorID && !resolution.Override {
+			return ErrItemClaimed
+		}
+		messageID := derefString(item.MessageID)
+
+		now := time.Now()
+		closed := map[string]any{
+			"status":          models.ModerationStatusResolved,
+			"resolved_by":     resolution.ModeratorID,
+			"resolved_at":     now,
+			"resolution":      resolution.Action,
+			"resolution_note": resolution.Note,
+		}
+
+		switch resolution.Action {
+		case models.ResolutionDeleteMessage:
+			if item.MessageID != nil {
+				result := tx.Model(&models.ModerationItem{}).
+					Where("message_id = ? AND id <> ? AND status <> ?", *item.MessageID, item.ID, models.ModerationStatusResolved).
+					Updates(closed)
+				if result.Error != nil {
+					return result.Error
+				}
+				var message models.Message
+				if err := tx.First(&message, "id = ?", *item.MessageID).Error; err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return ErrMessageNotFound
+					}
+					return err
+				}
+				revision := models.NewMessageRevision(&message, models.MessageRevisionDelete, resolution.ModeratorID)
+				if err := deleteMessage(tx, revision); err != nil {
+					return err
+				}
+			}
+		case models.ResolutionMute:
+			mutedUntil := now.Add(resolution.MuteFor)
+			result := tx.Model(&models.RoomMember{}).
+				Where("room_id = ? AND user_id = ?", item.RoomID, item.AuthorID).
+				Update("muted_until", mutedUntil)
+			if result.Error != nil {
+				return result.Error
+			}
+		case models.ResolutionBan:
+			result := tx.Where("room_id = ? AND user_id = ?", item.RoomID, item.AuthorID).
+				Delete(&models.RoomMember{})
+			if result.Error != nil {
+				return result.Error
+			}
+			ban := &models.RoomBan{
+				RoomID:    item.RoomID,
+				UserID:    item.AuthorID,
+				BannedBy:  resolution.ModeratorID,
+				Reason:    resolution.Note,
+				CreatedAt: now,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(ban).Error; err != nil {
+				return err
+			}
+		}
+
+		item.Status = models.ModerationStatusResolved
+		item.ResolvedBy = resolution.ModeratorID
+		item.ResolvedAt = &now
+		item.Resolution = resolution.Action
+		item.ResolutionNote = resolution.Note
+		if resolution.Action == models.ResolutionDeleteMessage {
+			// The foreign key has already cleared it in the database
+			item.MessageID = nil
+		}
+		if err := tx.Omit("Message").Save(&item).Error; err != nil {
+			return err
+		}
+
+		action := &models.ModerationAction{
+			RoomID:       item.RoomID,
+			ItemID:       &item.ID,
+			ModeratorID:  resolution.ModeratorID,
+			Action:       resolution.Action,
+			TargetUserID: item.AuthorID,
+			MessageID:    messageID,
+			Note:         resolution.Note,
+		}
+		if resolution.Action == models.ResolutionMute {
+			action.DurationSeconds = int64(resolution.MuteFor / time.Second)
+		}
+		return tx.Create(action).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetModerationActions lists audit records, newest first. A nil roomIDs lists
+// every room.
+func (r *chatRepository) GetModerationActions(roomIDs []string, limit int) ([]*models.ModerationAction, error) {
+	var actions []*models.ModerationAction
+	query := r.db.Model(&models.ModerationAction{})
+	if roomIDs != nil {
+		query = query.Where("room_id IN ?", roomIDs)
+	}
+	result := query.Order("created_at DESC").Limit(limit).Find(&actions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return actions, nil
+}
+
+func (r *chatRepository) IsRoomBanned(roomID, userID string) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.RoomBan{}).
+		Where("room_id = ? AND user_id = ?", roomID, userID).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func lockModerationItem(tx *gorm.DB, item *models.ModerationItem, itemID string) error {
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(item, "id = ?", itemID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return ErrModerationItemNotFound
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// KickRoomMember removes the target from the room and records the action.
+func (r *chatRepository) KickRoomMember(action *models.ModerationAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("room_id = ? AND user_id = ?", action.RoomID, action.TargetUserID).
+			Delete(&models.RoomMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMemberNotFound
+		}
+		return tx.Create(action).Error
+	})
+}
+
+// MuteRoomMember mutes the target until the given time, or unmutes them when
+// until is nil, and records the action.
+func (r *chatRepository) MuteRoomMember(action *models.ModerationAction, until *time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RoomMember{}).
+			Where("room_id = ? AND user_id = ?", action.RoomID, action.TargetUserID).
+			Update("muted_until", until)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMemberNotFound
+		}
+		return tx.Create(action).Error
+	})
+}
\ No newline at end of file
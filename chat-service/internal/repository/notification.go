@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRecipient is a room member who may be notified of a new
+// message, with their notification settings.
+type NotificationRecipient struct {
+	UserID      string
+	NotifyLevel string
+	Settings    *models.NotificationSettings
+}
+
+// UpdateRoomNotifications sets the member's notification level and how long
+// the room's notifications are silenced for them.
+func (r *chatRepository) UpdateRoomNotifications(roomID, userID, level string, mutedUntil *time.Time) (*models.RoomMember, error) {
+	result := r.db.Model(&models.RoomMember{}).
+		Where("room_id = ? AND user_id = ?", roomID, userID).
+		Updates(map[string]any{
+			"notify_level":              level,
+			"notifications_muted_until": mutedUntil,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrMemberNotFound
+	}
+	return r.GetRoomMember(roomID, userID)
+}
+
+// GetNotificationSettings returns the user's settings, or the defaults if
+// they never changed them.
+func (r *chatRepository) GetNotificationSettings(userID string) (*models.NotificationSettings, error) {
+	var settings []*models.NotificationSettings
+	result := r.db.Where("user_id = ?", userID).Limit(1).Find(&settings)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(settings) == 0 {
+		return &models.NotificationSettings{UserID: userID}, nil
+	}
+	return settings[0], nil
+}
+
+func (r *chatRepository) SaveNotificationSettings(settings *models.NotificationSettings) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dnd_enabled", "dnd_start", "dnd_end", "time_zone", "updated_at"}),
+	}).Create(settings).Error
+}
+
+// GetNotificationRecipients returns the room's members other than the author
+// whose level and mute let the room notify them at the given time. Their
+// do-not-disturb windows are left for the caller to check.
+func (r *chatRepository) GetNotificationRecipients(roomID, authorID string, now time.Time) ([]*NotificationRecipient, error) {
+	var members []*models.RoomMember
+	result := r.db.Select("user_id, notify_level").
+		Where("room_id = ? AND user_id <> ?", roomID, authorID).
+		Where("notify_level <> ?", models.NotifyNone).
+		Where("notifications_muted_until IS NULL OR notifications_muted_until <= ?", now).
+		Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	var settings []*models.NotificationSettings
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	byUser := make(map[string]*models.NotificationSettings, len(settings))
+	for _, userSettings := range settings {
+		byUser[userSettings.UserID] = userSettings
+	}
+
+	recipients := make([]*NotificationRecipient, len(members))
+	for i, member := range members {
+		userSettings := byUser[member.UserID]
+		if userSettings == nil {
+			userSettings = &models.NotificationSettings{UserID: member.UserID}
+		}
+		recipients[i] = &NotificationRecipient{
+			UserID:      member.UserID,
+			NotifyLevel: member.NotifyLevel,
+			Settings:    userSettings,
+		}
+	}
+	return recipients, nil
+}
\ No newline at end of file
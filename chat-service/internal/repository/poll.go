@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreatePollMessage stores a poll together with the message that carries it.
+func (r *chatRepository) CreatePollMessage(message *models.Message, poll *models.Poll) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createMessage(tx, message); err != nil {
+			return err
+		}
+
+		poll.MessageID = message.ID
+		poll.RoomID = message.RoomID
+		if err := tx.Create(poll).Error; err != nil {
+			return err
+		}
+
+		message.Poll = poll
+		return nil
+	})
+}
+
+func (r *chatRepository) GetPoll(pollID string) (*models.Poll, error) {
+	return loadPoll(r.db, pollID)
+}
+
+// SetPollVotes replaces the user's votes with optionIDs and keeps the
+// tallies in step. An empty optionIDs retracts the user's vote. The poll row
+// is locked so concurrent votes cannot lose tally updates.
+func (r *chatRepository) SetPollVotes(pollID, userID string, optionIDs []string) (*models.Poll, error) {
+	var poll *models.Poll
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Poll
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", pollID)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrPollNotFound
+			}
+			return result.Error
+		}
+		if locked.IsClosed(time.Now()) {
+			return ErrPollClosed
+		}
+
+		var previous []string
+		result = tx.Model(&models.PollVote{}).
+			Where("poll_id = ? AND user_id = ?", pollID, userID).
+			Pluck("option_id", &previous)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if len(previous) > 0 {
+			result = tx.Where("poll_id = ? AND user_id = ?", pollID, userID).Delete(&models.PollVote{})
+			if result.Error != nil {
+				return result.Error
+			}
+			result = tx.Model(&models.PollOption{}).
+				Where("id IN ?", previous).
+				Update("vote_count", gorm.Expr("vote_count - 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		if len(optionIDs) > 0 {
+			votes := make([]*models.PollVote, len(optionIDs))
+			for i, optionID := range optionIDs {
+				votes[i] = &models.PollVote{PollID: pollID, UserID: userID, OptionID: optionID}
+			}
+			if err := tx.Create(&votes).Error; err != nil {
+				return err
+			}
+			result = tx.Model(&models.PollOption{}).
+				Where("id IN ?", optionIDs).
+				Update("vote_count", gorm.Expr("vote_count + 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		voterDelta := 0
+		if len(previous) == 0 && len(optionIDs) > 0 {
+			voterDelta = 1
+		} else if len(previous) > 0 && len(optionIDs) == 0 {
+			voterDelta = -1
+		}
+		if voterDelta != 0 {
+			result = tx.Model(&locked).Update("total_voters", gorm.Expr("total_voters + ?", voterDelta))
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		var err error
+		poll, err = loadPoll(tx, pollID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// ClosePoll stops a poll from accepting votes. Closing a closed poll is a
+// no-op.
+func (r *chatRepository) ClosePoll(pollID string) (*models.Poll, error) {
+	result := r.db.Model(&models.Poll{}).
+		Where("id = ? AND closed_at IS NULL", pollID).
+		Update("closed_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return loadPoll(r.db, pollID)
+}
+
+// attachPolls loads the polls of poll messages in one query.
+func attachPolls(db *gorm.DB, messages []*models.Message) error {
+	byMessage := make(map[string]*models.Message)
+	for _, message := range messages {
+		if message.Type == models.MessageTypePoll {
+			byMessage[message.ID] = message
+		}
+	}
+	if len(byMessage) == 0 {
+		return nil
+	}
+
+	messageIDs := make([]string, 0, len(byMessage))
+	for id := range byMessage {
+		messageIDs = append(messageIDs, id)
+	}
+
+	var polls []*models.Poll
+	result := db.Preload("Options", orderOptions).
+		Where("message_id IN ?", messageIDs).
+		Find(&polls)
+	if result.Error != nil {
+		return result.Error
+	}
+	if err := attachVoters(db, polls); err != nil {
+		return err
+	}
+
+	for _, poll := range polls {
+		byMessage[poll.MessageID].Poll = poll
+	}
+	return nil
+}
+
+func loadPoll(db *gorm.DB, pollID string) (*models.Poll, error) {
+	var poll models.Poll
+	result := db.Preload("Options", orderOptions).First(&poll, "id = ?", pollID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrPollNotFound
+		}
+		return nil, result.Error
+	}
+	if err := attachVoters(db, []*models.Poll{&poll}); err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// attachVoters fills in who voted for each option of polls that are not
+// anonymous.
+func attachVoters(db *gorm.DB, polls []*models.Poll) error {
+	options := make(map[string]*models.PollOption)
+	var pollIDs []string
+	for _, poll := range polls {
+		if poll.Anonymous {
+			continue
+		}
+		pollIDs = append(pollIDs, poll.ID)
+		for i := range poll.Options {
+			options[poll.Options[i].ID] = &poll.Options[i]
+		}
+	}
+	if len(pollIDs) == 0 {
+		return nil
+	}
+
+	var votes []models.PollVote
+	result := db.Where("poll_id IN ?", pollIDs).Order("created_at").Find(&votes)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, vote := range votes {
+		if option, ok := options[vote.OptionID]; ok {
+			option.Voters = append(option.Voters, vote.UserID)
+		}
+	}
+	return nil
+}
+
+func orderOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PushBatch is the claimed jobs of one device.
+type PushBatch struct {
+	Device *models.PushDevice
+	Jobs   []*models.PushJob
+}
+
+// PushJobRepository is the part of ChatRepository used by the push worker.
+type PushJobRepository interface {
+	ClaimDuePushJobs(now time.Time, window, lease time.Duration, limit int) ([]*PushBatch, error)
+	FinishPushJobs(jobIDs []string, deviceID string, pushedAt time.Time) error
+	DropPushJobs(jobIDs []string) error
+	DeletePushDevicesByToken(tokens []string) error
+}
+
+// RegisterPushDevice registers the device's token, moving it to the user if
+// another user registered it before.
+func (r *chatRepository) RegisterPushDevice(device *models.PushDevice) (*models.PushDevice, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+	}).Create(device)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var registered models.PushDevice
+	if err := r.db.First(&registered, "token = ?", device.Token).Error; err != nil {
+		return nil, err
+	}
+	return &registered, nil
+}
+
+func (r *chatRepository) GetPushDevices(userID string) ([]*models.PushDevice, error) {
+	var devices []*models.PushDevice
+	result := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&devices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return devices, nil
+}
+
+// DeletePushDevice removes the user's device together with its queued jobs.
+func (r *chatRepository) DeletePushDevice(userID, deviceID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", deviceID, userID).Delete(&models.PushDevice{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPushDeviceNotFound
+		}
+		return tx.Where("device_id = ?", deviceID).Delete(&models.PushJob{}).Error
+	})
+}
+
+// DeletePushDevicesByToken removes the devices of tokens the push services
+// no longer know, together with their queued jobs.
+func (r *chatRepository) DeletePushDevicesByToken(tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var deviceIDs []string
+		result := tx.Model(&models.PushDevice{}).Where("token IN ?", tokens).Pluck("id", &deviceIDs)
+		if result.Error != nil || len(deviceIDs) == 0 {
+			return result.Error
+		}
+		if err := tx.Where("device_id IN ?", deviceIDs).Delete(&models.PushJob{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", deviceIDs).Delete(&models.PushDevice{}).Error
+	})
+}
+
+// EnqueuePushJobs queues a copy of the job for every device of the users.
+func (r *chatRepository) EnqueuePushJobs(job *models.PushJob, userIDs []string) (int64, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.Exec(`
+		INSERT INTO push_jobs
+			(device_id, room_id, message_id, title, body, mention, attempts, created_at)
+		SELECT id, ?, ?, ?, ?, ?, 0, ?
+		FROM push_devices
+		WHERE user_id IN ?`,
+		job.RoomID, job.MessageID, job.Title, job.Body, job.Mention, time.Now(),
+		userIDs)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// ClaimDuePushJobs leases all queued jobs of up to limit devices whose
+// oldest job was queued at least window ago, grouped by device in the order
+// they were queued. Jobs whose lease ran out are claimed again. The lease is
+// re-checked on every row under its lock, so concurrent workers never claim
+// the same job.
+func (r *chatRepository) ClaimDuePushJobs(now time.Time, window, lease time.Duration, limit int) ([]*PushBatch, error) {
+	var claimed []*models.PushJob
+	result := r.db.Raw(`
+		UPDATE push_jobs
+		SET lease_until = ?, attempts = attempts + 1
+		WHERE device_id IN (
+			SELECT device_id FROM push_jobs
+			WHERE lease_until IS NULL OR lease_until < ?
+			GROUP BY device_id
+			HAVING MIN(created_at) <= ?
+			ORDER BY MIN(created_at)
+			LIMIT ?
+		) AND (lease_until IS NULL OR lease_until < ?)
+		RETURNING *`,
+		now.Add(lease), now, now.Add(-window), limit, now).
+		Scan(&claimed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	byDevice := make(map[string]*PushBatch)
+	var deviceIDs []string
+	for _, job := range claimed {
+		batch, ok := byDevice[job.DeviceID]
+		if !ok {
+			batch = &PushBatch{}
+			byDevice[job.DeviceID] = batch
+			deviceIDs = append(deviceIDs, job.DeviceID)
+		}
+		batch.Jobs = append(batch.Jobs, job)
+	}
+	for _, batch := range byDevice {
+		sort.Slice(batch.Jobs, func(i, j int) bool {
+			return batch.Jobs[i].CreatedAt.Before(batch.Jobs[j].CreatedAt)
+		})
+	}
+
+	var devices []*models.PushDevice
+	if err := r.db.Where("id IN ?", deviceIDs).Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		byDevice[device.ID].Device = device
+	}
+
+	batches := make([]*PushBatch, 0, len(deviceIDs))
+	var orphans []string
+	for _, deviceID := range deviceIDs {
+		batch := byDevice[deviceID]
+		if batch.Device == nil {
+			// The device was removed after its jobs were queued
+			for _, job := range batch.Jobs {
+				orphans = append(orphans, job.ID)
+			}
+			continue
+		}
+		batches = append(batches, batch)
+	}
+	if err := r.DropPushJobs(orphans); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// FinishPushJobs removes jobs that were pushed to their device.
+func (r *chatRepository) FinishPushJobs(jobIDs []string, deviceID string, pushedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id IN ?", jobIDs).Delete(&models.PushJob{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.PushDevice{}).
+			Where("id = ?", deviceID).
+			Update("last_push_at", pushedAt).Error
+	})
+}
+
+// DropPushJobs removes jobs that will not be pushed.
+func (r *chatRepository) DropPushJobs(jobIDs []string) error {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", jobIDs).Delete(&models.PushJob{}).Error
+}
\ No newline at end of file
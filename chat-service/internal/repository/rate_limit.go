@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SendLimits configures AcquireSendSlot. A zero SlowMode or Burst disables
+// the respective limit.
+type SendLimits struct {
+	// SlowMode is the minimum gap between two messages of a user in the room.
+	SlowMode time.Duration
+	// Burst is the capacity of the user's token bucket.
+	Burst int
+	// Refill is the time it takes to earn back one token.
+	Refill time.Duration
+}
+
+// SendSlot is the outcome of AcquireSendSlot.
+type SendSlot struct {
+	Allowed    bool
+	RetryAfter time.Duration
+	// SlowMode is set when the room's slow mode, rather than the user's
+	// token bucket, caused the denial.
+	SlowMode bool
+	// SentAt is when the send was recorded, and PreviousSentAt the user's
+	// send before it in the slow-mode room, for ReleaseSendSlot.
+	SentAt         time.Time
+	PreviousSentAt time.Time
+}
+
+// AcquireSendSlot checks the room's slow mode and the user's token bucket and,
+// only if both allow it, records the send. Rows are locked so that replicas
+// sharing the database see a consistent state.
+func (r *chatRepository) AcquireSendSlot(roomID, userID string, limits SendLimits, now time.Time) (*SendSlot, error) {
+	slot := &SendSlot{Allowed: true}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var entry models.SlowModeEntry
+		if limits.SlowMode > 0 {
+			entry = models.SlowModeEntry{RoomID: roomID, UserID: userID}
+			if err := lockRow(tx, &entry, "room_id = ? AND user_id = ?", roomID, userID); err != nil {
+				return err
+			}
+
+			if wait := limits.SlowMode - now.Sub(entry.LastSentAt); wait > 0 {
+				slot.Allowed = false
+				slot.RetryAfter = wait
+				slot.SlowMode = true
+			}
+		}
+
+		var bucket models.SendRateBucket
+		if limits.Burst > 0 && limits.Refill > 0 {
+			bucket = models.SendRateBucket{UserID: userID, Tokens: float64(limits.Burst), UpdatedAt: now}
+			if err := lockRow(tx, &bucket, "user_id = ?", userID); err != nil {
+				return err
+			}
+
+			elapsed := now.Sub(bucket.UpdatedAt)
+			if elapsed > 0 {
+				bucket.Tokens = math.Min(float64(limits.Burst), bucket.Tokens+elapsed.Seconds()/limits.Refill.Seconds())
+				bucket.UpdatedAt = now
+			}
+
+			if bucket.Tokens < 1 {
+				wait := time.Duration((1 - bucket.Tokens) * float64(limits.Refill))
+				if wait > slot.RetryAfter {
+					slot.RetryAfter = wait
+					slot.SlowMode = false
+				}
+				slot.Allowed = false
+			}
+		}
+
+		if !slot.Allowed {
+			return nil
+		}
+
+		slot.SentAt = now
+		if limits.SlowMode > 0 {
+			slot.PreviousSentAt = entry.LastSentAt
+			entry.LastSentAt = now
+			if err := tx.Save(&entry).Error; err != nil {
+				return err
+			}
+		}
+		if limits.Burst > 0 && limits.Refill > 0 {
+			bucket.Tokens--
+			if err := tx.Save(&bucket).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+// ReleaseSendSlot gives back a slot AcquireSendSlot granted for a send that
+// did not go through: the token returns to the user's bucket, and the room's
+// slow mode counts from the user's previous send again unless they have sent
+// since.
+func (r *chatRepository) ReleaseSendSlot(roomID, userID string, limits SendLimits, slot *SendSlot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if limits.SlowMode > 0 {
+			err := tx.Model(&models.SlowModeEntry{}).
+				Where("room_id = ? AND user_id = ? AND last_sent_at = ?", roomID, userID, slot.SentAt).
+				Update("last_sent_at", slot.PreviousSentAt).Error
+			if err != nil {
+				return err
+			}
+		}
+		if limits.Burst > 0 && limits.Refill > 0 {
+			err := tx.Model(&models.SendRateBucket{}).
+				Where("user_id = ?", userID).
+				Update("tokens", gorm.Expr("LEAST(tokens + 1, ?)", limits.Burst)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// lockRow inserts row unless it already exists and then reloads it with a
+// row lock held until the end of the transaction.
+func lockRow(tx *gorm.DB, row any, query string, args ...any) error {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(query, args...).
+		Take(row).Error
+}
\ No newline at end of file
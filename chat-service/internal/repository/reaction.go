@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"strings"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// attachReactions sums up the reactions to each message, most used emoji
+// first.
+func attachReactions(db *gorm.DB, messages []*models.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	byMessage := make(map[string]*models.Message, len(messages))
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		byMessage[message.ID] = message
+		messageIDs = append(messageIDs, message.ID)
+	}
+
+	var rows []struct {
+		MessageID string
+		Emoji     string
+		UserIDs   string
+		Count     int
+	}
+	result := db.Model(&models.Reaction{}).
+		Select("message_id, emoji, STRING_AGG(user_id, ',' ORDER BY created_at) AS user_ids, COUNT(*) AS count").
+		Where("message_id IN ?", messageIDs).
+		Group("message_id, emoji").
+		Order("count DESC, MIN(created_at)").
+		Scan(&rows)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, row := range rows {
+		message := byMessage[row.MessageID]
+		message.Reactions = append(message.Reactions, models.ReactionCount{
+			Emoji:   row.Emoji,
+			Count:   row.Count,
+			UserIDs: strings.Split(row.UserIDs, ","),
+		})
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+)
+
+// attachReferences looks up the originals of forwarded messages and quoted
+// replies. References to deleted originals are left unavailable.
+func attachReferences(db *gorm.DB, messages []*models.Message) error {
+	var originalIDs []string
+	for _, message := range messages {
+		if message.Reference.IsSet() && message.Reference.MessageID != nil {
+			originalIDs = append(originalIDs, *message.Reference.MessageID)
+		}
+	}
+	if len(originalIDs) == 0 {
+		return nil
+	}
+
+	var originals []*models.Message
+	result := db.Select("id, content, plain_text").
+		Scopes(unexpired).
+		Where("id IN ?", originalIDs).
+		Find(&originals)
+	if result.Error != nil {
+		return result.Error
+	}
+	byID := make(map[string]*models.Message, len(originals))
+	for _, original := range originals {
+		byID[original.ID] = original
+	}
+
+	for _, message := range messages {
+		if message.Reference.IsSet() && message.Reference.MessageID != nil {
+			message.Reference.SetOriginal(byID[*message.Reference.MessageID])
+		}
+	}
+	return nil
+}
+
+// GetReferencingRoomIDs returns the rooms with messages that forward or quote
+// the message.
+func (r *chatRepository) GetReferencingRoomIDs(messageID string) ([]string, error) {
+	var roomIDs []string
+	result := r.db.Model(&models.Message{}).
+		Distinct("room_id").
+		Where("reference_message_id = ?", messageID).
+		Pluck("room_id", &roomIDs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roomIDs, nil
+}
\ No newline at end of file
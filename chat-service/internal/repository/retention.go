@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"time"
+
+	"chat-service/internal/models"
+)
+
+// PurgeResult summarises a single purge batch.
+type PurgeResult struct {
+	Removed int64
+	Oldest  *time.Time
+	Newest  *time.Time
+}
+
+// RetentionCutoff is where a purge stops. Day policies remove messages
+// created before Before. Count policies remove messages with a sequence
+// number below BeforeSeq, since imported messages keep their original
+// creation time and seq is the room's order.
+type RetentionCutoff struct {
+	Before    time.Time
+	BeforeSeq int64
+}
+
+// condition returns the SQL condition selecting the messages before the
+// cutoff, given the name of the column holding their creation time.
+func (c RetentionCutoff) condition(createdAtColumn string) (string, any) {
+	if c.BeforeSeq > 0 {
+		return "seq < ?", c.BeforeSeq
+	}
+	return createdAtColumn + " < ?", c.Before
+}
+
+// RetentionRepository is the part of ChatRepository used by the retention job.
+type RetentionRepository interface {
+	GetCountCutoff(roomID string, keep int) (int64, error)
+	PurgeMessagesBefore(roomID string, cutoff RetentionCutoff, batchSize int, archive bool) (*PurgeResult, error)
+	PurgeMessageRevisionsBefore(roomID string, cutoff RetentionCutoff) (int64, error)
+	CountRoomMessages(roomID string) (int64, error)
+	CreatePurgeRecord(record *models.PurgeRecord) error
+	GetPurgeRecords(roomID string, limit int) ([]*models.PurgeRecord, error)
+}
+
+// GetCountCutoff returns the sequence number of the oldest message that still
+// fits into the newest keep messages, or 0 if the room has fewer messages.
+func (r *chatRepository) GetCountCutoff(roomID string, keep int) (int64, error) {
+	var cutoffs []int64
+	result := r.db.Model(&models.Message{}).
+		Where("room_id = ?", roomID).
+		Order("seq DESC").
+		Offset(keep-1).
+		Limit(1).
+		Pluck("seq", &cutoffs)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if len(cutoffs) == 0 {
+		return 0, nil
+	}
+	return cutoffs[0], nil
+}
+
+// PurgeMessagesBefore removes at most batchSize messages before the cutoff.
+// Rows locked by another replica are skipped, and pinned messages are kept.
+// When archive is set the removed rows are copied to archived_messages in the
+// same statement.
+func (r *chatRepository) PurgeMessagesBefore(roomID string, cutoff RetentionCutoff, batchSize int, archive bool) (*PurgeResult, error) {
+	condition, before := cutoff.condition("created_at")
+	selectBatch := `SELECT id FROM messages
+		WHERE room_id = ? AND ` + condition + `
+		AND id NOT IN (SELECT message_id FROM pinned_messages WHERE room_id = ?)
+		ORDER BY seq
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`
+
+	query := `DELETE FROM messages WHERE id IN (` + selectBatch + `) RETURNING created_at`
+	if archive {
+		query = `WITH removed AS (
+			DELETE FROM messages WHERE id IN (` + selectBatch + `) RETURNING *
+		)
+		INSERT INTO archived_messages (id, room_id, data, created_at, archived_at)
+		SELECT id, room_id, to_jsonb(removed), created_at, NOW() FROM removed
+		RETURNING created_at`
+	}
+
+	var removed []time.Time
+	result := r.db.Raw(query, roomID, before, roomID, batchSize).Scan(&removed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	purge := &PurgeResult{Removed: int64(len(removed))}
+	for i := range removed {
+		if purge.Oldest == nil || removed[i].Before(*purge.Oldest) {
+			purge.Oldest = &removed[i]
+		}
+		if purge.Newest == nil || removed[i].After(*purge.Newest) {
+			purge.Newest = &removed[i]
+		}
+	}
+	return purge, nil
+}
+
+// PurgeMessageRevisionsBefore removes the edit and deletion history of
+// messages before the cutoff, except for pinned messages, which the retention
+// job keeps.
+func (r *chatRepository) PurgeMessageRevisionsBefore(roomID string, cutoff RetentionCutoff) (int64, error) {
+	condition, before := cutoff.condition("message_created_at")
+	result := r.db.Where("room_id = ?", roomID).
+		Where(condition, before).
+		Where("message_id NOT IN (SELECT message_id FROM pinned_messages WHERE room_id = ?)", roomID).
+		Delete(&models.MessageRevision{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *chatRepository) CountRoomMessages(roomID string) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Message{}).Where("room_id = ?", roomID).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *chatRepository) CreatePurgeRecord(record *models.PurgeRecord) error {
+	result := r.db.Create(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *chatRepository) GetPurgeRecords(roomID string, limit int) ([]*models.PurgeRecord, error) {
+	var records []*models.PurgeRecord
+	result := r.db.Where("room_id = ?", roomID).
+		Order("finished_at DESC").
+		Limit(limit).
+		Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return records, nil
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+)
+
+func (r *chatRepository) CreateScheduledMessage(scheduled *models.ScheduledMessage) error {
+	result := r.db.Create(scheduled)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *chatRepository) GetScheduledMessage(id string) (*models.ScheduledMessage, error) {
+	var scheduled models.ScheduledMessage
+	result := r.db.First(&scheduled, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledMessageNotFound
+		}
+		return nil, result.Error
+	}
+	return &scheduled, nil
+}
+
+// GetScheduledMessages lists the user's scheduled messages in delivery order.
+// An empty roomID lists every room and an empty status lists every status.
+func (r *chatRepository) GetScheduledMessages(userID, roomID, status string) ([]*models.ScheduledMessage, error) {
+	var scheduled []*models.ScheduledMessage
+	query := r.db.Where("user_id = ?", userID)
+	if roomID != "" {
+		query = query.Where("room_id = ?", roomID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("scheduled_at ASC").Find(&scheduled)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return scheduled, nil
+}
+
+// UpdatePendingScheduledMessage applies updates only while the message is
+// still pending, so it cannot race with its delivery.
+func (r *chatRepository) UpdatePendingScheduledMessage(id string, updates map[string]any) (*models.ScheduledMessage, error) {
+	scheduled := &models.ScheduledMessage{ID: id}
+	result := r.db.Model(scheduled).
+		Where("status = ?", models.ScheduledStatusPending).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrScheduledMessageNotPending
+	}
+	return r.GetScheduledMessage(id)
+}
+
+// ClaimDueScheduledMessages leases up to limit due messages to the caller.
+// Messages whose lease ran out, because the replica delivering them stopped,
+// are claimed again. SKIP LOCKED keeps concurrent schedulers from claiming
+// the same rows.
+func (r *chatRepository) ClaimDueScheduledMessages(now time.Time, lease time.Duration, limit int) ([]*models.ScheduledMessage, error) {
+	var claimed []*models.ScheduledMessage
+	result := r.db.Raw(`
+		UPDATE scheduled_messages
+		SET status = ?, lease_until = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM scheduled_messages
+			WHERE (status = ? AND scheduled_at <= ?)
+				OR (status = ? AND lease_until < ?)
+			ORDER BY scheduled_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.ScheduledStatusSending, now.Add(lease), now,
+		models.ScheduledStatusPending, now,
+		models.ScheduledStatusSending, now,
+		limit).
+		Scan(&claimed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return claimed, nil
+}
+
+// FinishScheduledMessage moves a claimed message out of the sending state.
+func (r *chatRepository) FinishScheduledMessage(id string, updates map[string]any) error {
+	result := r.db.Model(&models.ScheduledMessage{ID: id}).
+		Where("status = ?", models.ScheduledStatusSending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
\ No newline at end of file
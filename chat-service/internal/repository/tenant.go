@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type orgContextKey struct{}
+
+// WithOrg returns a copy of ctx that scopes repository queries to the
+// organization.
+func WithOrg(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+// OrgFromContext returns the organization ctx scopes queries to, if any.
+func OrgFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	orgID, _ := ctx.Value(orgContextKey{}).(string)
+	return orgID
+}
+
+// ForOrg returns a repository whose queries only see the organization's
+// data. An empty orgID returns an unscoped repository, as background workers
+// use.
+func (r *chatRepository) ForOrg(orgID string) ChatRepository {
+	if orgID == "" {
+		return &chatRepository{db: r.db.WithContext(context.Background())}
+	}
+	return &chatRepository{db: r.db.WithContext(WithOrg(context.Background(), orgID))}
+}
+
+// orgID is the organization the repository is scoped to, if any.
+func (r *chatRepository) orgID() string {
+	return OrgFromContext(r.db.Statement.Context)
+}
+
+// tenantScope is a gorm plugin that enforces tenant isolation on every query
+// of an organization-scoped repository:
+//
+//   - tables with an org_id column, such as rooms, are filtered on it, and
+//     rows created in them are assigned to the organization;
+//   - tables with a room_id column only see rows of the organization's rooms;
+//   - tables with only a message_id column only see rows of messages in the
+//     organization's rooms.
+//
+// Per-user tables with none of these, such as notification settings and
+// push devices, span the user's organizations. Raw SQL is not rewritten;
+// methods running it from a scoped repository filter by hand.
+type tenantScope struct{}
+
+func (tenantScope) Name() string {
+	return "chat:tenant_scope"
+}
+
+func (tenantScope) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("chat:tenant_assign", assignOrg); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("chat:tenant_query", scopeToOrg); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("chat:tenant_update", scopeToOrg); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("chat:tenant_delete", scopeToOrg); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("chat:tenant_row", scopeToOrg)
+}
+
+func scopeToOrg(db *gorm.DB) {
+	stmt := db.Statement
+	orgID := OrgFromContext(stmt.Context)
+	if orgID == "" || stmt.Schema == nil || stmt.SQL.Len() > 0 {
+		return
+	}
+
+	if condition := tenantCondition(stmt.Schema, orgID); condition != nil {
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{condition}})
+	}
+}
+
+func tenantCondition(s *schema.Schema, orgID string) clause.Expression {
+	table := clause.Table{Name: clause.CurrentTable}
+	switch {
+	case s.LookUpField("OrgID") != nil:
+		return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "org_id"}, Value: orgID}
+	case s.LookUpField("RoomID") != nil:
+		return clause.Expr{
+			SQL:  "?.room_id IN (SELECT id::text FROM rooms WHERE org_id = ?)",
+			Vars: []any{table, orgID},
+		}
+	case s.LookUpField("MessageID") != nil:
+		return clause.Expr{
+			SQL:  "?.message_id IN (SELECT id FROM messages WHERE room_id IN (SELECT id::text FROM rooms WHERE org_id = ?))",
+			Vars: []any{table, orgID},
+		}
+	}
+	return nil
+}
+
+// assignOrg puts rows created in tables with an org_id column into the
+// organization, unless they name one already.
+func assignOrg(db *gorm.DB) {
+	stmt := db.Statement
+	orgID := OrgFromContext(stmt.Context)
+	if orgID == "" || stmt.Schema == nil {
+		return
+	}
+
+	field := stmt.Schema.LookUpField("OrgID")
+	if field == nil {
+		return
+	}
+
+	assign := func(value reflect.Value) {
+		if _, zero := field.ValueOf(stmt.Context, value); zero {
+			if err := field.Set(stmt.Context, value, orgID); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			assign(reflect.Indirect(stmt.ReflectValue.Index(i)))
+		}
+	case reflect.Struct:
+		assign(stmt.ReflectValue)
+	}
+}
\ No newline at end of file
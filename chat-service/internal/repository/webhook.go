@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"chat-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookAttempt is the outcome of one delivery attempt.
+type WebhookAttempt struct {
+	DeliveryID     string
+	WebhookID      string
+	Succeeded      bool
+	ResponseStatus int
+	Error          string
+	// RetryAt schedules another attempt after a failure. Zero gives up on
+	// the delivery.
+	RetryAt time.Time
+	// DisableAfter is the number of consecutive failed attempts that
+	// disables the webhook.
+	DisableAfter int
+}
+
+// WebhookDeliveryRepository is the part of ChatRepository used by the webhook
+// worker.
+type WebhookDeliveryRepository interface {
+	ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error)
+	FinishWebhookDelivery(attempt WebhookAttempt) (bool, error)
+}
+
+func (r *chatRepository) CreateWebhook(webhook *models.Webhook) error {
+	result := r.db.Create(webhook)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+func (r *chatRepository) GetWebhook(webhookID string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	result := r.db.First(&webhook, "id = ?", webhookID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, result.Error
+	}
+	return &webhook, nil
+}
+
+// GetWebhooks lists a room's webhooks, or every webhook when roomID is empty.
+func (r *chatRepository) GetWebhooks(roomID string) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	query := r.db
+	if roomID != "" {
+		query = query.Where("room_id = ?", roomID)
+	}
+	result := query.Order("created_at ASC").Find(&webhooks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return webhooks, nil
+}
+
+func (r *chatRepository) UpdateWebhook(webhookID string, updates map[string]any) (*models.Webhook, error) {
+	webhook, err := r.GetWebhook(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := r.db.Model(webhook).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return r.GetWebhook(webhookID)
+}
+
+func (r *chatRepository) DeleteWebhook(webhookID string) error {
+	result := r.db.Delete(&models.Webhook{}, "id = ?", webhookID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// EnqueueWebhookDeliveries queues the payload for every active webhook of the
+// room, or of all rooms of its organization, that subscribes to the event.
+func (r *chatRepository) EnqueueWebhookDeliveries(roomID, event, payload string) (int64, error) {
+	now := time.Now()
+	result := r.db.Exec(`
+		INSERT INTO webhook_deliveries
+			(webhook_id, room_id, event, payload, status, attempts, next_attempt_at, created_at, updated_at)
+		SELECT id, ?, ?, ?, ?, 0, ?, ?, ?
+		FROM webhooks
+		WHERE active
+			AND (room_id = ? OR (room_id IS NULL AND org_id = (SELECT org_id FROM rooms WHERE id::text = ?)))
+			AND (events IS NULL OR events = '[]'::jsonb OR events @> jsonb_build_array(?::text))`,
+		roomID, event, payload, models.WebhookDeliveryPending, now, now, now,
+		roomID, roomID, event)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// ClaimDueWebhookDeliveries leases up to limit due deliveries of active
+// webhooks to the caller, with their webhooks loaded. Like scheduled
+// messages, deliveries whose lease ran out are claimed again.
+func (r *chatRepository) ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	var claimed []*models.WebhookDelivery
+	result := r.db.Raw(`
+		UPDATE webhook_deliveries
+		SET status = ?, lease_until = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE w.active AND (
+				(d.status = ? AND d.next_attempt_at <= ?)
+				OR (d.status = ? AND d.lease_until < ?))
+			ORDER BY d.next_attempt_at
+			LIMIT ?
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING *`,
+		models.WebhookDeliverySending, now.Add(lease), now,
+		models.WebhookDeliveryPending, now,
+		models.WebhookDeliverySending, now,
+		limit).
+		Scan(&claimed)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(claimed) == 0 {
+		return claimed, nil
+	}
+
+	webhookIDs := make([]string, 0, len(claimed))
+	for _, delivery := range claimed {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+	}
+	var webhooks []*models.Webhook
+	if err := r.db.Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		byID[webhook.ID] = webhook
+	}
+	for _, delivery := range claimed {
+		delivery.Webhook = byID[delivery.WebhookID]
+	}
+	return claimed, nil
+}
+
+// FinishWebhookDelivery records an attempt on the delivery and its webhook.
+// It reports whether the attempt disabled the webhook, in which case the
+// webhook's other queued deliveries are failed as well.
+func (r *chatRepository) FinishWebhookDelivery(attempt WebhookAttempt) (bool, error) {
+	disabled := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]any{
+			"lease_until":     nil,
+			"response_status": attempt.ResponseStatus,
+			"error":           attempt.Error,
+		}
+		switch {
+		case attempt.Succeeded:
+			updates["status"] = models.WebhookDeliverySucceeded
+			updates["delivered_at"] = now
+		case !attempt.RetryAt.IsZero():
+			updates["status"] = models.WebhookDeliveryPending
+			updates["next_attempt_at"] = attempt.RetryAt
+		default:
+			updates["status"] = models.WebhookDeliveryFailed
+		}
+
+		result := tx.Model(&models.WebhookDelivery{ID: attempt.DeliveryID}).
+			Where("status = ?", models.WebhookDeliverySending).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if attempt.Succeeded {
+			return tx.Model(&models.Webhook{ID: attempt.WebhookID}).
+				Where("failures <> 0").
+				Update("failures", 0).Error
+		}
+
+		var webhook models.Webhook
+		result = tx.Model(&webhook).
+			Clauses(clause.Returning{}).
+			Where("id = ?", attempt.WebhookID).
+			Update("failures", gorm.Expr("failures + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if !webhook.Active || attempt.DisableAfter <= 0 || webhook.Failures < attempt.DisableAfter {
+			return nil
+		}
+
+		result = tx.Model(&models.Webhook{ID: attempt.WebhookID}).Updates(map[string]any{
+			"active":          false,
+			"disabled_at":     now,
+			"disabled_reason": "too many consecutive failed deliveries",
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		result = tx.Model(&models.WebhookDelivery{}).
+			Where("webhook_id = ? AND status = ?", attempt.WebhookID, models.WebhookDeliveryPending).
+			Updates(map[string]any{
+				"status": models.WebhookDeliveryFailed,
+				"error":  "webhook disabled",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		disabled = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return disabled, nil
+}
+
+// GetWebhookDeliveries returns a webhook's most recent deliveries first. An
+// empty status lists every status.
+func (r *chatRepository) GetWebhookDeliveries(webhookID, status string, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	query := r.db.Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Order("created_at DESC").Limit(limit).Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deliveries, nil
+}
\ No newline at end of file
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+const (
+	maxBookmarkFolderLength = 64
+	maxBookmarkNoteLength   = 1000
+)
+
+// BookmarksPage is one page of ListBookmarks. NextCursor is empty on the last
+// page, and Folders lists all of the user's folders.
+type BookmarksPage struct {
+	Bookmarks  []*models.Bookmark
+	NextCursor string
+	Folders    []*models.BookmarkFolder
+}
+
+// BookmarkMessage bookmarks a message the user can read. Bookmarking it
+// again moves it to the given folder and replaces its note.
+func (s *chatService) BookmarkMessage(messageID, userID, folder, note string) (*models.Bookmark, error) {
+	folder = strings.TrimSpace(folder)
+	if len(folder) > maxBookmarkFolderLength || len(note) > maxBookmarkNoteLength {
+		return nil, ErrInvalidBookmark
+	}
+
+	message, err := s.getMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	readable, err := s.canReadRoom(message.RoomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !readable {
+		return nil, ErrNotRoomMember
+	}
+
+	bookmark, err := s.repo.SaveBookmark(&models.Bookmark{
+		UserID:    userID,
+		MessageID: message.ID,
+		RoomID:    message.RoomID,
+		Folder:    folder,
+		Note:      note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save bookmark: %w", err)
+	}
+	bookmark.Message = message
+	if room, err := s.repo.GetRoomByID(message.RoomID); err == nil {
+		bookmark.RoomName = room.Name
+	}
+
+	s.publishUserEvent(userID, map[string]any{
+		"type":       "bookmark_saved",
+		"message_id": bookmark.MessageID,
+		"bookmark":   bookmark,
+		"timestamp":  bookmark.UpdatedAt.Unix(),
+	})
+
+	return bookmark, nil
+}
+
+// RemoveBookmark removes the user's bookmark of a message. It works whether
+// or not the message still exists.
+func (s *chatService) RemoveBookmark(messageID, userID string) error {
+	bookmark, err := s.repo.DeleteBookmark(userID, messageID)
+	if err != nil {
+		if err == repository.ErrBookmarkNotFound {
+			return ErrBookmarkNotFound
+		}
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+
+	s.publishUserEvent(userID, map[string]any{
+		"type":        "bookmark_removed",
+		"message_id":  bookmark.MessageID,
+		"bookmark_id": bookmark.ID,
+		"timestamp":   time.Now().Unix(),
+	})
+
+	return nil
+}
+
+// ListBookmarks lists the user's bookmarks, newest first, optionally only
+// those of one folder. Messages are shown as they are now: edits show up,
+// and bookmarks of messages that were deleted, or are in rooms the user can
+// no longer read, come without their message.
+func (s *chatService) ListBookmarks(userID, folder, cursor string, limit int) (*BookmarksPage, error) {
+	var after *repository.BookmarkCursor
+	if cursor != "" {
+		var err error
+		after, err = decodeBookmarkCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	bookmarks, err := s.repo.GetBookmarks(userID, strings.TrimSpace(folder), after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	readable := make(map[string]bool)
+	for _, bookmark := range bookmarks {
+		if bookmark.Message == nil {
+			continue
+		}
+		canRead, checked := readable[bookmark.RoomID]
+		if !checked {
+			if canRead, err = s.canReadRoom(bookmark.RoomID, userID); err != nil {
+				return nil, err
+			}
+			readable[bookmark.RoomID] = canRead
+		}
+		if !canRead {
+			bookmark.Message = nil
+		}
+	}
+
+	folders, err := s.repo.GetBookmarkFolders(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmark folders: %w", err)
+	}
+
+	page := &BookmarksPage{Bookmarks: bookmarks, Folders: folders}
+	if len(bookmarks) == limit {
+		page.NextCursor = encodeBookmarkCursor(bookmarks[len(bookmarks)-1])
+	}
+	return page, nil
+}
+
+// publishUserEvent sends an event to the user's personal channel, which all
+// of their sessions subscribe to.
+func (s *chatService) publishUserEvent(userID string, data map[string]any) {
+	go func() {
+		if err := s.centrifugo.Publish(userChannel(userID), data); err != nil {
+			fmt.Printf("Failed to publish user event to centrifugo: %v\n", err)
+		}
+	}()
+}
+
+// encodeBookmarkCursor makes an opaque cursor pointing after the bookmark.
+func encodeBookmarkCursor(bookmark *models.Bookmark) string {
+	raw := strconv.FormatInt(bookmark.CreatedAt.UnixNano(), 10) + ":" + bookmark.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeBookmarkCursor(cursor string) (*repository.BookmarkCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.BookmarkCursor{CreatedAt: time.Unix(0, createdAt), ID: id}, nil
+}
\ No newline at end of file
@@ -1,13 +1,21 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
+	"chat-service/internal/events"
 	"chat-service/internal/models"
+	"chat-service/internal/moderation"
 	"chat-service/internal/repository"
 	"chat-service/pkg/centrifugo"
+	"chat-service/pkg/config"
 )
 
 var (
@@ -15,31 +23,203 @@ var (
 	ErrNotRoomMember    = errors.New("user is not a member of this room")
 	ErrInvalidMessage   = errors.New("invalid message content")
 	ErrCentrifugoFailed = errors.New("failed to publish to centrifugo")
+	ErrMessageNotFound  = errors.New("message not found")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidRole      = errors.New("invalid member role")
+	ErrAlreadyPinned    = errors.New("message already pinned")
+	ErrPinNotFound      = errors.New("pinned message not found")
+	ErrPinLimitReached  = errors.New("pinned message limit reached")
+	ErrInvalidRetention = errors.New("invalid retention policy")
+	ErrInvalidRoomName  = errors.New("room name is required")
+	ErrRoomArchived     = errors.New("room is archived")
+
+	ErrInvalidClientMessageID  = errors.New("client message id must be at most 64 characters")
+	ErrInvalidModerationConfig = errors.New("invalid moderation config")
+	ErrInvalidSlowMode         = errors.New("slow mode must be between 0 and 21600 seconds")
+	ErrInvalidReport           = errors.New("invalid report")
+	ErrAlreadyReported         = errors.New("message already reported")
+	ErrModerationItemNotFound  = errors.New("moderation item not found")
+	ErrItemClaimed             = errors.New("moderation item claimed by another moderator")
+	ErrItemResolved            = errors.New("moderation item already resolved")
+	ErrInvalidResolution       = errors.New("invalid moderation resolution")
+	ErrUserMuted               = errors.New("user is muted in this room")
+	ErrUserBanned              = errors.New("user is banned from this room")
+	ErrUserNotFound            = errors.New("user not found")
+
+	ErrInvalidSchedule            = errors.New("scheduled time must be in the future and within the scheduling window")
+	ErrScheduledMessageNotFound   = errors.New("scheduled message not found")
+	ErrScheduledMessageNotPending = errors.New("scheduled message is no longer pending")
+	ErrCommandNotScheduled        = errors.New("slash commands cannot be scheduled")
+	ErrCommandOptions             = errors.New("slash commands cannot quote a message or have a time-to-live")
+
+	ErrInvalidPoll  = errors.New("invalid poll")
+	ErrInvalidVote  = errors.New("invalid poll vote")
+	ErrPollNotFound = errors.New("poll not found")
+	ErrPollClosed   = errors.New("poll is closed")
+
+	ErrInvalidWebhook          = errors.New("invalid webhook")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrIncomingWebhookNotFound = errors.New("incoming webhook not found")
+	ErrInvalidCursor           = errors.New("invalid cursor")
+
+	ErrInvalidExportFormat = errors.New("export format must be jsonl, csv or html")
+	ErrExportNotFound      = errors.New("export not found")
+	ErrExportNotReady      = errors.New("export is not ready for download")
+
+	ErrSourceNotReadable = errors.New("the original message is in a room you cannot read")
+	ErrQuoteNotScheduled = errors.New("quoted replies cannot be scheduled")
+
+	ErrInvalidBookmark  = errors.New("bookmark folder must be at most 64 characters and note at most 1000")
+	ErrBookmarkNotFound = errors.New("bookmark not found")
+
+	ErrInvalidNotifyLevel = errors.New("notification level must be all, mentions or none")
+	ErrInvalidMutedUntil  = errors.New("muted until must be an RFC 3339 time")
+	ErrInvalidDNDSchedule = errors.New("do-not-disturb start and end must be different times as HH:MM, and the time zone a valid IANA name")
+
+	ErrInvalidPushDevice  = errors.New("push device needs a platform of fcm or apns and a token")
+	ErrPushDeviceNotFound = errors.New("push device not found")
+
+	ErrInvalidMessageTTL = errors.New("message time-to-live must be between 0 and 2592000 seconds")
+	ErrTTLNotScheduled   = errors.New("scheduled messages cannot have their own time-to-live")
 )
 
+// SendMessageOptions holds optional parameters of SendMessage.
+type SendMessageOptions struct {
+	// ClientMessageID deduplicates retried sends of the same message.
+	ClientMessageID string
+	// QuoteMessageID makes the message a reply quoting that message.
+	QuoteMessageID string
+	// TTL makes the message disappear that long after it is sent. It cannot
+	// outlast the room's own time-to-live.
+	TTL time.Duration
+	// Scheduled marks the send of a scheduled message. Scheduled sends cannot
+	// run commands.
+	Scheduled bool
+}
+
 type ChatService interface {
 	CreateRoom(name, description, userID string) (*models.Room, error)
-	GetRooms() ([]*models.Room, error)
+	GetRooms(includePinnedCount bool) ([]*models.Room, error)
+	UpdateRoom(roomID, userID string, name, description *string, archive bool) (*models.Room, error)
+	ArchiveRoom(roomID, userID string) (*models.Room, error)
+	DeleteRoom(roomID, userID string) error
 	JoinRoom(roomID, userID string) error
-	SendMessage(roomID, userID, username, content string) (*models.Message, error)
-	GetRoomMessages(roomID string, limit int) ([]*models.Message, error)
+	GetRoomMembers(roomID, userID, cursor string, limit int) (*RoomMembersPage, error)
+	SetMemberRole(roomID, userID, targetUserID, role string) error
+	SendMessage(roomID, userID, username, content string, opts SendMessageOptions) (*models.Message, error)
+	ForwardMessage(messageID, targetRoomID, userID, username string, opts SendMessageOptions) (*models.Message, error)
+	EditMessage(messageID, userID, content string) (*models.Message, error)
+	DeleteMessage(messageID, userID string) error
+	GetRoomMessages(roomID, userID string, limit int) ([]*models.Message, error)
+	GetMessagesSince(roomID, userID string, afterSeq int64, limit int) ([]*models.Message, int64, error)
+	PinMessage(roomID, messageID, userID string) (*models.PinnedMessage, error)
+	UnpinMessage(roomID, messageID, userID string) error
+	ListPinnedMessages(roomID, userID string) ([]*models.PinnedMessage, error)
+	SetRoomRetention(roomID, userID string, policy models.RetentionPolicy) (*models.Room, error)
+	GetPurgeRecords(roomID, userID string, limit int) ([]*models.PurgeRecord, error)
+	SetRoomModeration(roomID, userID string, filters []moderation.FilterConfig) (*models.Room, error)
+	GetRoomModeration(roomID, userID string) ([]moderation.FilterConfig, bool, error)
+	ListModerationQueue(roomID, userID, status string, limit int) ([]*models.ModerationItem, error)
+	SetSlowMode(roomID, userID string, seconds int) (*models.Room, error)
+	SetMessageTTL(roomID, userID string, seconds int) (*models.Room, error)
+	ExpireMessages(limit int) (int, error)
+	ReportMessage(messageID, userID, reason, detail string) (*models.ModerationItem, error)
+	ClaimModerationItem(itemID, userID string) (*models.ModerationItem, error)
+	ResolveModerationItem(itemID, userID, action, note string, muteFor time.Duration) (*models.ModerationItem, error)
+	ListModerationActions(roomID, userID string, limit int) ([]*models.ModerationAction, error)
+	ScheduleMessage(roomID, userID, username, content string, at time.Time) (*models.ScheduledMessage, error)
+	ListScheduledMessages(userID, roomID, status string) ([]*models.ScheduledMessage, error)
+	UpdateScheduledMessage(id, userID string, content *string, at *time.Time) (*models.ScheduledMessage, error)
+	CancelScheduledMessage(id, userID string) (*models.ScheduledMessage, error)
+	CreatePoll(roomID, userID, username string, params PollParams) (*models.Message, error)
+	VotePoll(pollID, userID string, optionIDs []string) (*models.Poll, error)
+	ClosePoll(pollID, userID string) (*models.Poll, error)
+	CreateWebhook(userID string, params WebhookParams) (*models.Webhook, error)
+	ListWebhooks(roomID, userID string) ([]*models.Webhook, error)
+	UpdateWebhook(webhookID, userID string, update WebhookUpdate) (*models.Webhook, error)
+	DeleteWebhook(webhookID, userID string) error
+	ListWebhookDeliveries(webhookID, userID, status string, limit int) ([]*models.WebhookDelivery, error)
+	CreateIncomingWebhook(roomID, userID, name string) (*models.IncomingWebhook, string, error)
+	ListIncomingWebhooks(roomID, userID string) ([]*models.IncomingWebhook, error)
+	RevokeIncomingWebhook(id, userID string) (*models.IncomingWebhook, error)
+	PostIncomingWebhook(token, content string, opts SendMessageOptions) (*models.Message, error)
+	CreateExport(roomID, userID, format string) (*models.ExportJob, error)
+	GetExport(exportID, userID string) (*models.ExportJob, error)
+	ListExports(roomID, userID string, limit int) ([]*models.ExportJob, error)
+	OpenExport(exportID, userID string) (*models.ExportJob, io.ReadCloser, error)
+	BookmarkMessage(messageID, userID, folder, note string) (*models.Bookmark, error)
+	RemoveBookmark(messageID, userID string) error
+	ListBookmarks(userID, folder, cursor string, limit int) (*BookmarksPage, error)
+	GetRoomNotifications(roomID, userID string) (*models.RoomMember, error)
+	SetRoomNotifications(roomID, userID, level string, mutedUntil *time.Time) (*models.RoomMember, error)
+	GetNotificationSettings(userID string) (*models.NotificationSettings, error)
+	SetDNDSchedule(userID string, schedule DNDSchedule) (*models.NotificationSettings, error)
+	SetEmailDigests(userID string, enabled bool) (*models.NotificationSettings, error)
+	RegisterPushDevice(userID, platform, token string) (*models.PushDevice, error)
+	ListPushDevices(userID string) ([]*models.PushDevice, error)
+	UnregisterPushDevice(userID, deviceID string) error
+	StreamRoomEvents(ctx context.Context, userID string, roomIDs []string, sinceSeq int64, roomSinceSeq map[string]int64, send func(events.Event) error) error
+	ForOrg(orgID, orgRole, callerID string) ChatService
 }
 
 type chatService struct {
-	repo          repository.ChatRepository
-	centrifugo    *centrifugo.CentrifugoClient
+	repo              repository.ChatRepository
+	centrifugo        *centrifugo.CentrifugoClient
+	events            *events.Bus
+	users             UserDirectory
+	presence          *presenceCache
+	roomOrgs          *sync.Map
+	maxPinnedMessages int
+	idempotencyWindow time.Duration
+	moderation        *moderationPipelines
+	sendRateBurst     int
+	sendRateRefill    time.Duration
+	botRateBurst      int
+	botRateRefill     time.Duration
+	admins            map[string]struct{}
+	maxScheduleAhead  time.Duration
+	externalCommands  map[string]string
+	commandSecret     string
+	commandTimeout    time.Duration
+
+	// orgID is the organization ForOrg scoped the service to, and orgAdminID
+	// the caller if they administer it.
+	orgID      string
+	orgAdminID string
 }
 
-func NewChatService(repo repository.ChatRepository, centrifugo *centrifugo.CentrifugoClient) ChatService {
+func NewChatService(repo repository.ChatRepository, centrifugo *centrifugo.CentrifugoClient, eventBus *events.Bus, users UserDirectory) ChatService {
+	// Admins moderate every room
+	admins := make(map[string]struct{})
+	for _, userID := range config.List("CHAT_ADMINS") {
+		admins[userID] = struct{}{}
+	}
+
 	return &chatService{
-		repo:       repo,
-		centrifugo: centrifugo,
+		repo:              repo,
+		centrifugo:        centrifugo,
+		events:            eventBus,
+		users:             users,
+		presence:          newPresenceCache(config.Duration("PRESENCE_CACHE_TTL", 5*time.Second), centrifugo.Presence),
+		roomOrgs:          &sync.Map{},
+		maxPinnedMessages: config.Int("MAX_PINNED_MESSAGES", 50),
+		idempotencyWindow: config.Duration("IDEMPOTENCY_WINDOW", 24*time.Hour),
+		moderation:        newModerationPipelines(os.Getenv("MODERATION_DEFAULT_CONFIG")),
+		sendRateBurst:     config.Int("SEND_RATE_BURST", 20),
+		sendRateRefill:    config.Duration("SEND_RATE_REFILL", 3*time.Second),
+		botRateBurst:      config.Int("INCOMING_WEBHOOK_RATE_BURST", 10),
+		botRateRefill:     config.Duration("INCOMING_WEBHOOK_RATE_REFILL", 6*time.Second),
+		admins:            admins,
+		maxScheduleAhead:  config.Duration("SCHEDULED_MESSAGE_MAX_AHEAD", 30*24*time.Hour),
+		externalCommands:  parseExternalCommands(config.List("SLASH_COMMANDS")),
+		commandSecret:     os.Getenv("SLASH_COMMAND_SECRET"),
+		commandTimeout:    config.Duration("SLASH_COMMAND_TIMEOUT", 5*time.Second),
 	}
 }
 
 func (s *chatService) CreateRoom(name, description, userID string) (*models.Room, error) {
 	if name == "" {
-		return nil, errors.New("room name is required")
+		return nil, ErrInvalidRoomName
 	}
 
 	room := &models.Room{
@@ -54,7 +234,7 @@ func (s *chatService) CreateRoom(name, description, userID string) (*models.Room
 	}
 
 	// Auto-join the room creator
-	err = s.repo.AddRoomMember(room.ID, userID)
+	err = s.repo.AddRoomMember(room.ID, userID, models.RoleOwner)
 	if err != nil && err != repository.ErrAlreadyMember {
 		return nil, fmt.Errorf("failed to add room creator as member: %w", err)
 	}
@@ -62,14 +242,147 @@ func (s *chatService) CreateRoom(name, description, userID string) (*models.Room
 	return room, nil
 }
 
-func (s *chatService) GetRooms() ([]*models.Room, error) {
-	rooms, err := s.repo.GetAllRooms()
+func (s *chatService) GetRooms(includePinnedCount bool) ([]*models.Room, error) {
+	rooms, err := s.repo.GetAllRooms(false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rooms: %w", err)
 	}
+
+	if includePinnedCount {
+		roomIDs := make([]string, len(rooms))
+		for i, room := range rooms {
+			roomIDs[i] = room.ID
+		}
+
+		counts, err := s.repo.CountPinnedMessages(roomIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count pinned messages: %w", err)
+		}
+		for _, room := range rooms {
+			room.PinnedCount = counts[room.ID]
+		}
+	}
+
 	return rooms, nil
 }
 
+// UpdateRoom changes the room's name and description and, with archive,
+// archives it, all in one update so that a failure leaves the room as it was.
+func (s *chatService) UpdateRoom(roomID, userID string, name, description *string, archive bool) (*models.Room, error) {
+	room, err := s.requireOwner(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]any{}
+	if name != nil {
+		if *name == "" {
+			return nil, ErrInvalidRoomName
+		}
+		updates["name"] = *name
+	}
+	if description != nil {
+		updates["description"] = *description
+	}
+	archiving := archive && !room.IsArchived()
+	if archiving {
+		updates["archived_at"] = time.Now()
+	}
+	if len(updates) == 0 {
+		return room, nil
+	}
+
+	room, err = s.repo.UpdateRoom(roomID, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	if name != nil || description != nil {
+		s.publishRoomEvent(roomID, map[string]any{
+			"type":        "room_updated",
+			"room_id":     roomID,
+			"name":        room.Name,
+			"description": room.Description,
+			"user_id":     userID,
+			"timestamp":   room.UpdatedAt.Unix(),
+		})
+	}
+	if archiving {
+		s.publishRoomEvent(roomID, map[string]any{
+			"type":      "room_archived",
+			"room_id":   roomID,
+			"user_id":   userID,
+			"timestamp": room.ArchivedAt.Unix(),
+		})
+	}
+
+	return room, nil
+}
+
+func (s *chatService) ArchiveRoom(roomID, userID string) (*models.Room, error) {
+	room, err := s.requireOwner(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return room, nil
+	}
+
+	room, err = s.repo.ArchiveRoom(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive room: %w", err)
+	}
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":      "room_archived",
+		"room_id":   roomID,
+		"user_id":   userID,
+		"timestamp": room.ArchivedAt.Unix(),
+	})
+
+	return room, nil
+}
+
+func (s *chatService) DeleteRoom(roomID, userID string) error {
+	if _, err := s.requireOwner(roomID, userID); err != nil {
+		return err
+	}
+
+	err := s.repo.DeleteRoom(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return ErrRoomNotFound
+		}
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	event := map[string]any{
+		"type":      "room_deleted",
+		"room_id":   roomID,
+		"user_id":   userID,
+		"timestamp": time.Now().Unix(),
+	}
+
+	s.events.Publish(events.Event{
+		Type:   "room_deleted",
+		RoomID: roomID,
+		OrgID:  s.roomOrgID(roomID),
+		Data:   event,
+	})
+
+	// Clear the history first so late subscribers do not replay deleted messages
+	go func() {
+		if err := s.centrifugo.HistoryRemove("room:" + roomID); err != nil {
+			fmt.Printf("Failed to remove room history from centrifugo: %v\n", err)
+		}
+		if err := s.centrifugo.Publish("room:"+roomID, event); err != nil {
+			fmt.Printf("Failed to publish event to centrifugo: %v\n", err)
+		}
+	}()
+
+	return nil
+}
+
 func (s *chatService) JoinRoom(roomID, userID string) error {
 	// Check if room exists
 	room, err := s.repo.GetRoomByID(roomID)
@@ -79,9 +392,20 @@ func (s *chatService) JoinRoom(roomID, userID string) error {
 		}
 		return fmt.Errorf("failed to get room: %w", err)
 	}
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
+
+	banned, err := s.repo.IsRoomBanned(room.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check room ban: %w", err)
+	}
+	if banned {
+		return ErrUserBanned
+	}
 
 	// Add user as room member
-	err = s.repo.AddRoomMember(room.ID, userID)
+	err = s.repo.AddRoomMember(room.ID, userID, models.RoleMember)
 	if err != nil {
 		if err == repository.ErrAlreadyMember {
 			return nil // Already a member, no error
@@ -90,78 +414,273 @@ func (s *chatService) JoinRoom(roomID, userID string) error {
 	}
 
 	// Notify room about new member via Centrifugo
-	notification := map[string]any{
+	s.publishRoomEvent(roomID, map[string]any{
 		"type":      "user_joined",
 		"room_id":   roomID,
 		"user_id":   userID,
 		"timestamp": time.Now().Unix(),
+	})
+
+	return nil
+}
+
+func (s *chatService) SetMemberRole(roomID, userID, targetUserID, role string) error {
+	if role != models.RoleModerator && role != models.RoleMember {
+		return ErrInvalidRole
 	}
 
-	go func() {
-		if err := s.centrifugo.Publish("room:"+roomID, notification); err != nil {
-			fmt.Printf("Failed to publish join notification: %v\n", err)
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return ErrRoomNotFound
 		}
-	}()
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+
+	// Only the owner may promote or demote, and the owner's role is fixed
+	if room.CreatedBy != userID || targetUserID == room.CreatedBy {
+		return ErrPermissionDenied
+	}
+
+	err = s.repo.UpdateRoomMemberRole(roomID, targetUserID, role)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return ErrNotRoomMember
+		}
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":      "member_role_changed",
+		"room_id":   roomID,
+		"user_id":   targetUserID,
+		"role":      role,
+		"timestamp": time.Now().Unix(),
+	})
 
 	return nil
 }
 
-func (s *chatService) SendMessage(roomID, userID, username, content string) (*models.Message, error) {
+// SendMessage posts a message to the room. Content starting with a slash
+// runs a command instead; a leading "//" escapes the slash.
+func (s *chatService) SendMessage(roomID, userID, username, content string, opts SendMessageOptions) (*models.Message, error) {
 	if content == "" {
 		return nil, ErrInvalidMessage
 	}
+	if len(opts.ClientMessageID) > 64 {
+		return nil, ErrInvalidClientMessageID
+	}
+	if opts.TTL < 0 || opts.TTL > maxMessageTTL {
+		return nil, ErrInvalidMessageTTL
+	}
 
-	// Check if room exists
-	room, err := s.repo.GetRoomByID(roomID)
+	room, member, err := s.senderRoom(roomID, userID)
 	if err != nil {
-		if err == repository.ErrRoomNotFound {
-			return nil, ErrRoomNotFound
+		return nil, err
+	}
+
+	if name, args, ok := parseCommand(content); ok {
+		if opts.Scheduled {
+			return nil, ErrCommandNotScheduled
 		}
-		return nil, fmt.Errorf("failed to get room: %w", err)
+		if opts.QuoteMessageID != "" || opts.TTL > 0 {
+			return nil, ErrCommandOptions
+		}
+		return s.runCommand(&commandContext{
+			room:     room,
+			member:   member,
+			username: username,
+			name:     name,
+			args:     args,
+			opts:     opts,
+		})
+	}
+	if strings.HasPrefix(content, "//") {
+		content = content[1:]
 	}
 
-	// Check if user is room member
-	isMember, err := s.repo.IsRoomMember(room.ID, userID)
+	var reference models.MessageReference
+	ttl := opts.TTL
+	if opts.QuoteMessageID != "" {
+		// A reply quoting a disappearing message expires with it
+		var original *models.Message
+		original, reference, err = s.messageReference(models.ReferenceQuote, opts.QuoteMessageID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if ttl, err = referenceTTL(original, ttl); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.sendMessage(room, member, outgoingMessage{
+		userID:          userID,
+		username:        username,
+		content:         content,
+		messageType:     models.MessageTypeText,
+		clientMessageID: opts.ClientMessageID,
+		reference:       reference,
+		ttl:             ttl,
+	})
+}
+
+// outgoingMessage is a message for sendMessage to post.
+type outgoingMessage struct {
+	userID          string
+	username        string
+	content         string
+	messageType     string
+	clientMessageID string
+	reference       models.MessageReference
+	ttl             time.Duration
+}
+
+// sendMessage rate limits the member, moderates the content and posts it.
+// The author is usually the member, but command responses are posted under
+// the command's own identity. Retries of a message that went through and
+// messages moderation rejects do not count against the rate limit.
+func (s *chatService) sendMessage(room *models.Room, member *models.RoomMember, out outgoingMessage) (*models.Message, error) {
+	if out.clientMessageID != "" {
+		original, err := s.repo.FindIdempotentMessage(room.ID, out.userID, out.clientMessageID, s.idempotencyWindow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find message: %w", err)
+		}
+		if original != nil {
+			original.Duplicate = true
+			return original, nil
+		}
+	}
+
+	release, err := s.acquireSendSlot(room, member)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check room membership: %w", err)
+		return nil, err
 	}
-	if !isMember {
-		return nil, ErrNotRoomMember
+
+	moderated, err := s.moderate(room, out.content)
+	if err != nil {
+		release()
+		return nil, err
 	}
 
 	// Create message
 	message := &models.Message{
-		RoomID:   roomID,
-		UserID:   userID,
-		Username: username,
-		Content:  content,
+		RoomID:          room.ID,
+		UserID:          out.userID,
+		Username:        out.username,
+		Content:         moderated.Content,
+		Type:            out.messageType,
+		ClientMessageID: out.clientMessageID,
+		Reference:       out.reference,
+		ExpiresAt:       messageExpiry(room, out.ttl),
 	}
 
-	err = s.repo.CreateMessage(message)
+	message, created, err := s.repo.CreateMessageIdempotent(message, s.idempotencyWindow)
 	if err != nil {
+		release()
 		return nil, fmt.Errorf("failed to create message: %w", err)
 	}
 
+	// A concurrent retry got there first, and its message was already published
+	if !created {
+		release()
+		message.Duplicate = true
+		return message, nil
+	}
+
+	// Flagged messages are delivered but queued for review
+	if len(moderated.Flags) > 0 {
+		s.queueFlags(message, moderated.Flags)
+	}
+
 	// Publish message to Centrifugo
-	messageData := map[string]any{
+	s.publishMessage(message, messageEventData(message))
+	s.markRoomRead(room.ID, out.userID)
+
+	return message, nil
+}
+
+// senderRoom returns the room and the sender's membership if the user may
+// post to the room right now.
+func (s *chatService) senderRoom(roomID, userID string) (*models.Room, *models.RoomMember, error) {
+	// Check if room exists
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, nil, ErrRoomNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	if room.IsArchived() {
+		return nil, nil, ErrRoomArchived
+	}
+
+	// Check if user is room member
+	member, err := s.repo.GetRoomMember(room.ID, userID)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return nil, nil, ErrNotRoomMember
+		}
+		return nil, nil, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	if member.IsMuted(time.Now()) {
+		return nil, nil, ErrUserMuted
+	}
+
+	return room, member, nil
+}
+
+// moderate runs the room's moderation filters over content.
+func (s *chatService) moderate(room *models.Room, content string) (moderation.Result, error) {
+	moderated, err := s.moderation.run(room, content)
+	if err != nil {
+		return moderation.Result{}, fmt.Errorf("failed to moderate message: %w", err)
+	}
+	if moderated.Rejected != nil {
+		return moderation.Result{}, &moderation.RejectionError{
+			Filter: moderated.Rejected.Filter,
+			Reason: moderated.Rejected.Reason,
+			Detail: moderated.Rejected.Detail,
+		}
+	}
+	if strings.TrimSpace(moderated.Content) == "" {
+		return moderation.Result{}, ErrInvalidMessage
+	}
+	return moderated, nil
+}
+
+// messageEventData is the payload published for a new message.
+func messageEventData(message *models.Message) map[string]any {
+	data := map[string]any{
 		"id":         message.ID,
 		"room_id":    message.RoomID,
+		"seq":        message.Seq,
+		"type":       message.Type,
 		"user_id":    message.UserID,
 		"username":   message.Username,
 		"content":    message.Content,
+		"plain_text": message.PlainText,
+		"entities":   message.Entities,
 		"created_at": message.CreatedAt.Format(time.RFC3339),
 	}
-
-	go func() {
-		if err := s.centrifugo.Publish("room:"+roomID, messageData); err != nil {
-			fmt.Printf("Failed to publish message to centrifugo: %v\n", err)
-		}
-	}()
-
-	return message, nil
+	if message.ClientMessageID != "" {
+		data["client_message_id"] = message.ClientMessageID
+	}
+	if message.Poll != nil {
+		data["poll"] = message.Poll
+	}
+	if message.Reference.IsSet() {
+		data["reference"] = message.Reference
+	}
+	if message.ExpiresAt != nil {
+		data["expires_at"] = message.ExpiresAt.Format(time.RFC3339)
+	}
+	return data
 }
 
-func (s *chatService) GetRoomMessages(roomID string, limit int) ([]*models.Message, error) {
+// GetRoomMessages returns the room's latest messages. userID is the reader,
+// if known.
+func (s *chatService) GetRoomMessages(roomID, userID string, limit int) ([]*models.Message, error) {
 	// Check if room exists
 	_, err := s.repo.GetRoomByID(roomID)
 	if err != nil {
@@ -182,6 +701,396 @@ func (s *chatService) GetRoomMessages(roomID string, limit int) ([]*models.Messa
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
+	s.markRoomRead(roomID, userID)
 
 	return messages, nil
+}
+
+// GetMessagesSince returns messages with a sequence number above afterSeq in
+// ascending order, together with the room's latest sequence number so that
+// clients can tell whether more messages remain. Only members and admins of
+// the room may catch up on it.
+func (s *chatService) GetMessagesSince(roomID, userID string, afterSeq int64, limit int) ([]*models.Message, int64, error) {
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, 0, ErrRoomNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	readable, err := s.canReadRoom(roomID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !readable {
+		return nil, 0, ErrNotRoomMember
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	messages, err := s.repo.GetMessagesSince(roomID, afterSeq, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get messages: %w", err)
+	}
+	s.markRoomRead(roomID, userID)
+
+	return messages, room.LastSeq, nil
+}
+
+// EditMessage replaces the content of the user's own text message. The new
+// content goes through the room's moderation filters like a new message.
+func (s *chatService) EditMessage(messageID, userID, content string) (*models.Message, error) {
+	if content == "" {
+		return nil, ErrInvalidMessage
+	}
+
+	message, err := s.getMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.UserID != userID || (message.Type != models.MessageTypeText && message.Type != models.MessageTypeAction) {
+		return nil, ErrPermissionDenied
+	}
+
+	room, _, err := s.senderRoom(message.RoomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	moderated, err := s.moderate(room, content)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := models.NewMessageRevision(message, models.MessageRevisionEdit, userID)
+
+	now := time.Now()
+	message.Content = moderated.Content
+	message.EditedAt = &now
+	message.Render()
+	if err := s.repo.UpdateMessageContent(message, revision); err != nil {
+		if err == repository.ErrMessageNotFound {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	if len(moderated.Flags) > 0 {
+		s.queueFlags(message, moderated.Flags)
+	}
+
+	s.publishRoomEvent(message.RoomID, map[string]any{
+		"type":       "message_edited",
+		"room_id":    message.RoomID,
+		"message_id": message.ID,
+		"seq":        message.Seq,
+		"user_id":    userID,
+		"content":    message.Content,
+		"plain_text": message.PlainText,
+		"entities":   message.Entities,
+		"edited_at":  now.Format(time.RFC3339),
+		"timestamp":  now.Unix(),
+	})
+
+	return message, nil
+}
+
+// DeleteMessage deletes a message on behalf of its author or a room
+// moderator.
+func (s *chatService) DeleteMessage(messageID, userID string) error {
+	message, err := s.getMessage(messageID)
+	if err != nil {
+		return err
+	}
+	if message.UserID != userID {
+		if _, err := s.requireModerator(message.RoomID, userID); err != nil {
+			return err
+		}
+	}
+
+	revision := models.NewMessageRevision(message, models.MessageRevisionDelete, userID)
+	if err := s.repo.DeleteMessage(revision); err != nil {
+		if err == repository.ErrMessageNotFound {
+			return ErrMessageNotFound
+		}
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	s.publishRoomEvent(message.RoomID, map[string]any{
+		"type":       "message_deleted",
+		"room_id":    message.RoomID,
+		"message_id": message.ID,
+		"seq":        message.Seq,
+		"user_id":    userID,
+		"timestamp":  time.Now().Unix(),
+	})
+	s.publishReferenceUnavailable(message)
+
+	return nil
+}
+
+func (s *chatService) getMessage(messageID string) (*models.Message, error) {
+	message, err := s.repo.GetMessageByID(messageID)
+	if err != nil {
+		if err == repository.ErrMessageNotFound {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	return message, nil
+}
+
+func (s *chatService) PinMessage(roomID, messageID, userID string) (*models.PinnedMessage, error) {
+	room, err := s.requireModerator(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+
+	message, err := s.repo.GetMessageByID(messageID)
+	if err != nil {
+		if err == repository.ErrMessageNotFound {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if message.RoomID != roomID {
+		return nil, ErrMessageNotFound
+	}
+
+	pin := &models.PinnedMessage{
+		RoomID:    roomID,
+		MessageID: messageID,
+		PinnedBy:  userID,
+	}
+
+	err = s.repo.PinMessage(pin, s.maxPinnedMessages)
+	if err != nil {
+		switch err {
+		case repository.ErrRoomNotFound:
+			return nil, ErrRoomNotFound
+		case repository.ErrAlreadyPinned:
+			return nil, ErrAlreadyPinned
+		case repository.ErrPinLimitReached:
+			return nil, ErrPinLimitReached
+		}
+		return nil, fmt.Errorf("failed to pin message: %w", err)
+	}
+	pin.Message = *message
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":       "message_pinned",
+		"room_id":    roomID,
+		"message_id": messageID,
+		"user_id":    userID,
+		"timestamp":  pin.PinnedAt.Unix(),
+	})
+
+	return pin, nil
+}
+
+func (s *chatService) UnpinMessage(roomID, messageID, userID string) error {
+	room, err := s.requireModerator(roomID, userID)
+	if err != nil {
+		return err
+	}
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
+
+	err = s.repo.UnpinMessage(roomID, messageID)
+	if err != nil {
+		if err == repository.ErrPinNotFound {
+			return ErrPinNotFound
+		}
+		return fmt.Errorf("failed to unpin message: %w", err)
+	}
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":       "message_unpinned",
+		"room_id":    roomID,
+		"message_id": messageID,
+		"user_id":    userID,
+		"timestamp":  time.Now().Unix(),
+	})
+
+	return nil
+}
+
+func (s *chatService) ListPinnedMessages(roomID, userID string) ([]*models.PinnedMessage, error) {
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	isMember, err := s.repo.IsRoomMember(room.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotRoomMember
+	}
+
+	pins, err := s.repo.GetPinnedMessages(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned messages: %w", err)
+	}
+
+	return pins, nil
+}
+
+func (s *chatService) SetRoomRetention(roomID, userID string, policy models.RetentionPolicy) (*models.Room, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, ErrInvalidRetention
+	}
+
+	room, err := s.requireOwner(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.UpdateRoomRetention(roomID, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update room retention: %w", err)
+	}
+	room.RetentionType = policy.Type
+	room.RetentionValue = policy.Value
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":      "retention_updated",
+		"room_id":   roomID,
+		"policy":    policy,
+		"timestamp": time.Now().Unix(),
+	})
+
+	return room, nil
+}
+
+func (s *chatService) GetPurgeRecords(roomID, userID string, limit int) ([]*models.PurgeRecord, error) {
+	if _, err := s.requireOwner(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	records, err := s.repo.GetPurgeRecords(roomID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purge records: %w", err)
+	}
+	return records, nil
+}
+
+// requireOwner returns the room if the user created it.
+func (s *chatService) requireOwner(roomID, userID string) (*models.Room, error) {
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy != userID {
+		return nil, ErrPermissionDenied
+	}
+	return room, nil
+}
+
+// requireModerator returns the room if the user owns or moderates it.
+func (s *chatService) requireModerator(roomID, userID string) (*models.Room, error) {
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy == userID || s.isAdmin(userID) {
+		return room, nil
+	}
+
+	member, err := s.repo.GetRoomMember(roomID, userID)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return nil, ErrNotRoomMember
+		}
+		return nil, fmt.Errorf("failed to get room member: %w", err)
+	}
+	if !member.CanModerate() {
+		return nil, ErrPermissionDenied
+	}
+
+	return room, nil
+}
+
+// publishRoomEvent publishes an event to in-process subscribers and to the
+// room channel without blocking the caller.
+func (s *chatService) publishRoomEvent(roomID string, event map[string]any) {
+	eventType, _ := event["type"].(string)
+	s.events.Publish(events.Event{
+		Type:   eventType,
+		RoomID: roomID,
+		OrgID:  s.roomOrgID(roomID),
+		Data:   event,
+	})
+
+	go func() {
+		if err := s.centrifugo.Publish("room:"+roomID, event); err != nil {
+			fmt.Printf("Failed to publish event to centrifugo: %v\n", err)
+		}
+		s.dispatchWebhooks(roomID, eventType, event)
+	}()
+}
+
+// roomOrgID returns the organization of the room, so that events reach
+// subscribers of the whole organization. A scoped service only acts on rooms
+// of its own organization; otherwise lookups are cached, as rooms never move
+// between organizations.
+func (s *chatService) roomOrgID(roomID string) string {
+	if s.orgID != "" {
+		return s.orgID
+	}
+	if orgID, ok := s.roomOrgs.Load(roomID); ok {
+		return orgID.(string)
+	}
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		return ""
+	}
+	s.roomOrgs.Store(roomID, room.OrgID)
+	return room.OrgID
+}
+
+// publishMessage publishes a new message like publishRoomEvent, keeping the
+// message itself for in-process subscribers.
+func (s *chatService) publishMessage(message *models.Message, data map[string]any) {
+	s.events.Publish(events.Event{
+		Type:      events.TypeMessage,
+		RoomID:    message.RoomID,
+		OrgID:     s.roomOrgID(message.RoomID),
+		Seq:       message.Seq,
+		Message:   message,
+		Data:      data,
+		Timestamp: message.CreatedAt,
+	})
+
+	go func() {
+		if err := s.centrifugo.Publish("room:"+message.RoomID, data); err != nil {
+			fmt.Printf("Failed to publish message to centrifugo: %v\n", err)
+		}
+		s.dispatchWebhooks(message.RoomID, models.WebhookEventMessageCreated, data)
+		s.notifyMembers(message)
+	}()
 }
\ No newline at end of file
@@ -0,0 +1,641 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+	"chat-service/pkg/signature"
+)
+
+const (
+	defaultMuteDuration = 10 * time.Minute
+	maxTopicLength      = 250
+)
+
+// commandName matches a slash command at the start of a message. Anything
+// else starting with a slash, like a file path, is sent as text.
+var commandName = regexp.MustCompile(`^/([a-z][a-z0-9_-]*)(?:\s+|$)`)
+
+// commandContext is a command being run on behalf of a room member.
+type commandContext struct {
+	room     *models.Room
+	member   *models.RoomMember
+	username string
+	name     string
+	args     string
+	opts     SendMessageOptions
+}
+
+// command is a built-in slash command. A command returns the message it
+// posted, or an ephemeral reply for the sender.
+type command struct {
+	usage       string
+	description string
+	// moderator restricts the command to the room owner, moderators and admins.
+	moderator bool
+	run       func(s *chatService, cmd *commandContext) (*models.Message, error)
+}
+
+// builtinCommands is filled in init because /help lists it.
+var builtinCommands map[string]*command
+
+func init() {
+	builtinCommands = map[string]*command{
+		"help": {
+			description: "List the commands you can use",
+			run:         (*chatService).commandHelp,
+		},
+		"me": {
+			usage:       "<action>",
+			description: "Post an action, like \"/me waves\"",
+			run:         (*chatService).commandMe,
+		},
+		"topic": {
+			usage:       "[topic]",
+			description: "Show or set the room topic",
+			moderator:   true,
+			run:         (*chatService).commandTopic,
+		},
+		"invite": {
+			usage:       "@user",
+			description: "Add a user to the room",
+			moderator:   true,
+			run:         (*chatService).commandInvite,
+		},
+		"kick": {
+			usage:       "@user",
+			description: "Remove a member from the room",
+			moderator:   true,
+			run:         (*chatService).commandKick,
+		},
+		"mute": {
+			usage:       "@user [duration]",
+			description: "Stop a member from posting, for 10m unless a duration like 1h is given",
+			moderator:   true,
+			run:         (*chatService).commandMute,
+		},
+		"unmute": {
+			usage:       "@user",
+			description: "Let a muted member post again",
+			moderator:   true,
+			run:         (*chatService).commandUnmute,
+		},
+		"poll": {
+			usage:       "\"question\" \"option\" \"option\" ...",
+			description: "Start a poll",
+			run:         (*chatService).commandPoll,
+		},
+	}
+}
+
+// parseCommand splits a slash command into its name and arguments.
+func parseCommand(content string) (string, string, bool) {
+	match := commandName.FindStringSubmatch(content)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], strings.TrimSpace(content[len(match[0]):]), true
+}
+
+// parseExternalCommands reads "name=url" entries. External commands cannot
+// replace built-in ones.
+func parseExternalCommands(entries []string) map[string]string {
+	commands := make(map[string]string)
+	for _, entry := range entries {
+		name, endpoint, ok := strings.Cut(entry, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "/")
+		if !ok || !commandName.MatchString("/"+name) {
+			log.Printf("Ignoring invalid slash command %q", entry)
+			continue
+		}
+		if _, builtin := builtinCommands[name]; builtin {
+			log.Printf("Ignoring slash command /%s, it is built in", name)
+			continue
+		}
+		if parsed, err := url.Parse(endpoint); err != nil || parsed.Host == "" {
+			log.Printf("Ignoring slash command /%s, invalid url", name)
+			continue
+		}
+		commands[name] = endpoint
+	}
+	return commands
+}
+
+// runCommand runs the command once per client message ID: a retry returns
+// the message the command posted, or tells the sender it already ran.
+func (s *chatService) runCommand(cmd *commandContext) (*models.Message, error) {
+	clientMessageID := cmd.opts.ClientMessageID
+	if clientMessageID == "" {
+		return s.dispatchCommand(cmd)
+	}
+
+	claimed, err := s.repo.ClaimIdempotencyKey(cmd.room.ID, cmd.member.UserID, clientMessageID, s.idempotencyWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim client message id: %w", err)
+	}
+	if !claimed {
+		original, err := s.repo.FindIdempotentMessage(cmd.room.ID, cmd.member.UserID, clientMessageID, s.idempotencyWindow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find message: %w", err)
+		}
+		if original != nil {
+			original.Duplicate = true
+			return original, nil
+		}
+		return s.ephemeral(cmd, fmt.Sprintf("/%s already ran.", cmd.name)), nil
+	}
+
+	message, err := s.dispatchCommand(cmd)
+	if err != nil {
+		if releaseErr := s.repo.ReleaseIdempotencyKey(cmd.room.ID, cmd.member.UserID, clientMessageID); releaseErr != nil {
+			log.Printf("Failed to release client message id of /%s: %v", cmd.name, releaseErr)
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+func (s *chatService) dispatchCommand(cmd *commandContext) (*models.Message, error) {
+	builtin, ok := builtinCommands[cmd.name]
+	if !ok {
+		if endpoint, ok := s.externalCommands[cmd.name]; ok {
+			return s.runExternalCommand(cmd, endpoint)
+		}
+		return s.ephemeral(cmd, fmt.Sprintf("Unknown command /%s. Type /help to see the commands you can use.", cmd.name)), nil
+	}
+
+	if builtin.moderator && !s.canModerate(cmd.room, cmd.member) {
+		return s.ephemeral(cmd, fmt.Sprintf("You don't have permission to use /%s.", cmd.name)), nil
+	}
+
+	message, err := builtin.run(s, cmd)
+	if err != nil {
+		// Mistakes the sender can fix are answered privately instead of
+		// failing the send
+		if isCommandFeedback(err) {
+			return s.ephemeral(cmd, fmt.Sprintf("/%s: %v", cmd.name, err)), nil
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+func isCommandFeedback(err error) bool {
+	switch {
+	case errors.Is(err, ErrPermissionDenied),
+		errors.Is(err, ErrUserBanned),
+		errors.Is(err, ErrUserNotFound),
+		errors.Is(err, ErrNotOrgMember),
+		errors.Is(err, ErrInvalidPoll):
+		return true
+	}
+	return false
+}
+
+func (s *chatService) usage(cmd *commandContext) *models.Message {
+	return s.ephemeral(cmd, fmt.Sprintf("Usage: /%s %s", cmd.name, builtinCommands[cmd.name].usage))
+}
+
+// ephemeral builds a reply only the sender sees. It is never stored; besides
+// being returned to the caller, it is published on the sender's personal
+// channel so replies to scheduled or bot messages are not lost.
+func (s *chatService) ephemeral(cmd *commandContext, text string) *models.Message {
+	message := &models.Message{
+		RoomID:    cmd.room.ID,
+		UserID:    cmd.member.UserID,
+		Username:  cmd.username,
+		Content:   text,
+		Type:      models.MessageTypeEphemeral,
+		CreatedAt: time.Now(),
+	}
+	message.Render()
+
+	data := messageEventData(message)
+	go func() {
+		if err := s.centrifugo.Publish(userChannel(cmd.member.UserID), data); err != nil {
+			fmt.Printf("Failed to publish ephemeral message to centrifugo: %v\n", err)
+		}
+	}()
+
+	return message
+}
+
+// userChannel is the Centrifugo channel only the given user may subscribe to.
+func userChannel(userID string) string {
+	return "user:#" + userID
+}
+
+func (s *chatService) canModerate(room *models.Room, member *models.RoomMember) bool {
+	return room.CreatedBy == member.UserID || member.CanModerate() || s.isAdmin(member.UserID)
+}
+
+// commandTarget returns the user ID named by the first argument, with or
+// without a leading @.
+func commandTarget(args string) (string, string, bool) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return "", "", false
+	}
+	target := strings.TrimPrefix(fields[0], "@")
+	if target == "" {
+		return "", "", false
+	}
+	return target, strings.TrimSpace(strings.TrimPrefix(args, fields[0])), true
+}
+
+// checkModerationTarget keeps moderators from acting on themselves, on the
+// room owner, and on other moderators unless they own the room or are admins.
+func (s *chatService) checkModerationTarget(cmd *commandContext, targetID string) error {
+	actorID := cmd.member.UserID
+	if targetID == actorID || targetID == cmd.room.CreatedBy {
+		return ErrPermissionDenied
+	}
+	if cmd.room.CreatedBy == actorID || s.isAdmin(actorID) {
+		return nil
+	}
+
+	target, err := s.repo.GetRoomMember(cmd.room.ID, targetID)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return nil
+		}
+		return fmt.Errorf("failed to get room member: %w", err)
+	}
+	if target.CanModerate() {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func (s *chatService) commandHelp(cmd *commandContext) (*models.Message, error) {
+	names := make([]string, 0, len(builtinCommands)+len(s.externalCommands))
+	for name, builtin := range builtinCommands {
+		if !builtin.moderator || s.canModerate(cmd.room, cmd.member) {
+			names = append(names, name)
+		}
+	}
+	for name := range s.externalCommands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	lines := []string{"Commands:"}
+	for _, name := range names {
+		builtin, ok := builtinCommands[name]
+		if !ok {
+			lines = append(lines, fmt.Sprintf("`/%s` - provided by an external service", name))
+			continue
+		}
+		usage := "/" + name
+		if builtin.usage != "" {
+			usage += " " + builtin.usage
+		}
+		lines = append(lines, fmt.Sprintf("`%s` - %s", usage, builtin.description))
+	}
+	return s.ephemeral(cmd, strings.Join(lines, "\n")), nil
+}
+
+func (s *chatService) commandMe(cmd *commandContext) (*models.Message, error) {
+	if cmd.args == "" {
+		return s.usage(cmd), nil
+	}
+
+	return s.sendMessage(cmd.room, cmd.member, outgoingMessage{
+		userID:          cmd.member.UserID,
+		username:        cmd.username,
+		content:         cmd.args,
+		messageType:     models.MessageTypeAction,
+		clientMessageID: cmd.opts.ClientMessageID,
+	})
+}
+
+func (s *chatService) commandTopic(cmd *commandContext) (*models.Message, error) {
+	if cmd.args == "" {
+		if cmd.room.Description == "" {
+			return s.ephemeral(cmd, "This room has no topic."), nil
+		}
+		return s.ephemeral(cmd, "Topic: "+cmd.room.Description), nil
+	}
+	if utf8.RuneCountInString(cmd.args) > maxTopicLength {
+		return s.ephemeral(cmd, fmt.Sprintf("The topic can be at most %d characters.", maxTopicLength)), nil
+	}
+
+	room, err := s.repo.UpdateRoom(cmd.room.ID, map[string]any{"description": cmd.args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	s.publishRoomEvent(room.ID, map[string]any{
+		"type":        "room_updated",
+		"room_id":     room.ID,
+		"name":        room.Name,
+		"description": room.Description,
+		"user_id":     cmd.member.UserID,
+		"timestamp":   room.UpdatedAt.Unix(),
+	})
+
+	return s.ephemeral(cmd, "Topic updated."), nil
+}
+
+func (s *chatService) commandInvite(cmd *commandContext) (*models.Message, error) {
+	targetID, _, ok := commandTarget(cmd.args)
+	if !ok {
+		return s.usage(cmd), nil
+	}
+
+	if err := s.requireUser(targetID); err != nil {
+		return nil, err
+	}
+	if err := s.requireOrgMember(targetID); err != nil {
+		return nil, err
+	}
+
+	banned, err := s.repo.IsRoomBanned(cmd.room.ID, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check room ban: %w", err)
+	}
+	if banned {
+		return nil, ErrUserBanned
+	}
+
+	if err := s.repo.AddRoomMember(cmd.room.ID, targetID, models.RoleMember); err != nil {
+		if err == repository.ErrAlreadyMember {
+			return s.ephemeral(cmd, fmt.Sprintf("@%s is already a member.", targetID)), nil
+		}
+		return nil, fmt.Errorf("failed to add room member: %w", err)
+	}
+
+	s.publishRoomEvent(cmd.room.ID, map[string]any{
+		"type":       "user_joined",
+		"room_id":    cmd.room.ID,
+		"user_id":    targetID,
+		"invited_by": cmd.member.UserID,
+		"timestamp":  time.Now().Unix(),
+	})
+
+	return s.ephemeral(cmd, fmt.Sprintf("Invited @%s.", targetID)), nil
+}
+
+func (s *chatService) commandKick(cmd *commandContext) (*models.Message, error) {
+	targetID, _, ok := commandTarget(cmd.args)
+	if !ok {
+		return s.usage(cmd), nil
+	}
+	if err := s.checkModerationTarget(cmd, targetID); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.KickRoomMember(&models.ModerationAction{
+		RoomID:       cmd.room.ID,
+		ModeratorID:  cmd.member.UserID,
+		Action:       models.ModerationActionKick,
+		TargetUserID: targetID,
+	})
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return s.ephemeral(cmd, fmt.Sprintf("@%s is not a member.", targetID)), nil
+		}
+		return nil, fmt.Errorf("failed to kick member: %w", err)
+	}
+
+	s.publishRoomEvent(cmd.room.ID, map[string]any{
+		"type":      "member_kicked",
+		"room_id":   cmd.room.ID,
+		"user_id":   targetID,
+		"kicked_by": cmd.member.UserID,
+		"timestamp": time.Now().Unix(),
+	})
+	go func() {
+		if err := s.centrifugo.Unsubscribe(targetID, "room:"+cmd.room.ID); err != nil {
+			fmt.Printf("Failed to unsubscribe kicked user: %v\n", err)
+		}
+	}()
+
+	return s.ephemeral(cmd, fmt.Sprintf("Kicked @%s.", targetID)), nil
+}
+
+func (s *chatService) commandMute(cmd *commandContext) (*models.Message, error) {
+	targetID, rest, ok := commandTarget(cmd.args)
+	if !ok {
+		return s.usage(cmd), nil
+	}
+
+	duration := defaultMuteDuration
+	if rest != "" {
+		parsed, err := time.ParseDuration(rest)
+		if err != nil || parsed <= 0 || parsed > maxMuteDuration {
+			return s.ephemeral(cmd, "The duration must be like 30m or 2h, and at most 720h."), nil
+		}
+		duration = parsed
+	}
+	if err := s.checkModerationTarget(cmd, targetID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mutedUntil := now.Add(duration)
+	err := s.repo.MuteRoomMember(&models.ModerationAction{
+		RoomID:          cmd.room.ID,
+		ModeratorID:     cmd.member.UserID,
+		Action:          models.ResolutionMute,
+		TargetUserID:    targetID,
+		DurationSeconds: int64(duration / time.Second),
+	}, &mutedUntil)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return s.ephemeral(cmd, fmt.Sprintf("@%s is not a member.", targetID)), nil
+		}
+		return nil, fmt.Errorf("failed to mute member: %w", err)
+	}
+
+	s.publishRoomEvent(cmd.room.ID, map[string]any{
+		"type":        "member_muted",
+		"room_id":     cmd.room.ID,
+		"user_id":     targetID,
+		"muted_until": mutedUntil.Unix(),
+		"timestamp":   now.Unix(),
+	})
+
+	return s.ephemeral(cmd, fmt.Sprintf("Muted @%s for %s.", targetID, duration)), nil
+}
+
+func (s *chatService) commandUnmute(cmd *commandContext) (*models.Message, error) {
+	targetID, _, ok := commandTarget(cmd.args)
+	if !ok {
+		return s.usage(cmd), nil
+	}
+	if err := s.checkModerationTarget(cmd, targetID); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.MuteRoomMember(&models.ModerationAction{
+		RoomID:       cmd.room.ID,
+		ModeratorID:  cmd.member.UserID,
+		Action:       models.ModerationActionUnmute,
+		TargetUserID: targetID,
+	}, nil)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return s.ephemeral(cmd, fmt.Sprintf("@%s is not a member.", targetID)), nil
+		}
+		return nil, fmt.Errorf("failed to unmute member: %w", err)
+	}
+
+	s.publishRoomEvent(cmd.room.ID, map[string]any{
+		"type":      "member_unmuted",
+		"room_id":   cmd.room.ID,
+		"user_id":   targetID,
+		"timestamp": time.Now().Unix(),
+	})
+
+	return s.ephemeral(cmd, fmt.Sprintf("Unmuted @%s.", targetID)), nil
+}
+
+func (s *chatService) commandPoll(cmd *commandContext) (*models.Message, error) {
+	args, ok := splitQuoted(cmd.args)
+	if !ok || len(args) < 1+minPollOptions {
+		return s.usage(cmd), nil
+	}
+
+	return s.CreatePoll(cmd.room.ID, cmd.member.UserID, cmd.username, PollParams{
+		Question: args[0],
+		Options:  args[1:],
+	})
+}
+
+// splitQuoted splits on whitespace, keeping double-quoted text together.
+func splitQuoted(text string) ([]string, bool) {
+	var parts []string
+	var current strings.Builder
+	quoted, inPart := false, false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inPart = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if inPart {
+				parts = append(parts, current.String())
+				current.Reset()
+				inPart = false
+			}
+		default:
+			current.WriteRune(r)
+			inPart = true
+		}
+	}
+	if quoted {
+		return nil, false
+	}
+	if inPart {
+		parts = append(parts, current.String())
+	}
+	return parts, true
+}
+
+// externalCommandRequest is posted to an external command's endpoint.
+type externalCommandRequest struct {
+	Command  string `json:"command"`
+	Text     string `json:"text"`
+	RoomID   string `json:"room_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// externalCommandResponse follows Slack: the reply is ephemeral unless
+// response_type is "in_channel".
+type externalCommandResponse struct {
+	Text         string `json:"text"`
+	ResponseType string `json:"response_type"`
+}
+
+// runExternalCommand forwards the command to its endpoint and posts the
+// reply. Requests are signed like outgoing webhooks when
+// SLASH_COMMAND_SECRET is set. In-channel replies are posted under the
+// command's own name and count against the sender's send rate.
+func (s *chatService) runExternalCommand(cmd *commandContext, endpoint string) (*models.Message, error) {
+	body, err := json.Marshal(externalCommandRequest{
+		Command:  "/" + cmd.name,
+		Text:     cmd.args,
+		RoomID:   cmd.room.ID,
+		UserID:   cmd.member.UserID,
+		Username: cmd.username,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode command: %w", err)
+	}
+
+	reply, err := s.callExternalCommand(endpoint, body)
+	if err != nil {
+		log.Printf("Slash command /%s failed: %v", cmd.name, err)
+		return s.ephemeral(cmd, fmt.Sprintf("/%s is not responding. Try again later.", cmd.name)), nil
+	}
+
+	if reply.ResponseType != "in_channel" || strings.TrimSpace(reply.Text) == "" {
+		if reply.Text == "" {
+			reply.Text = fmt.Sprintf("/%s done.", cmd.name)
+		}
+		return s.ephemeral(cmd, reply.Text), nil
+	}
+
+	return s.sendMessage(cmd.room, cmd.member, outgoingMessage{
+		userID:      "command:" + cmd.name,
+		username:    "/" + cmd.name,
+		content:     reply.Text,
+		messageType: models.MessageTypeText,
+	})
+}
+
+func (s *chatService) callExternalCommand(endpoint string, body []byte) (*externalCommandResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.commandTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.commandSecret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set("X-Command-Timestamp", strconv.FormatInt(timestamp, 10))
+		req.Header.Set("X-Command-Signature", "sha256="+signature.Sign(s.commandSecret, timestamp, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	var reply externalCommandResponse
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &reply); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+	}
+	return &reply, nil
+}
\ No newline at end of file
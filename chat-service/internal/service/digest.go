@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"chat-service/internal/models"
+)
+
+// SetEmailDigests turns the user's email digests of unread messages on or
+// off. Turning them off drops the messages queued for the next digest.
+func (s *chatService) SetEmailDigests(userID string, enabled bool) (*models.NotificationSettings, error) {
+	if err := s.repo.SetDigestOptOut(userID, !enabled); err != nil {
+		return nil, fmt.Errorf("failed to save notification settings: %w", err)
+	}
+
+	settings, err := s.repo.GetNotificationSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	s.publishUserEvent(userID, map[string]any{
+		"type":      "notification_settings_updated",
+		"settings":  settings,
+		"timestamp": time.Now().Unix(),
+	})
+
+	return settings, nil
+}
+
+// queueDigestEntries keeps the message for the email digests of offline
+// members, mapped to whether the message mentions them.
+func (s *chatService) queueDigestEntries(message *models.Message, offline map[string]bool) {
+	var mentioned, others []string
+	for userID, mention := range offline {
+		if mention {
+			mentioned = append(mentioned, userID)
+		} else {
+			others = append(others, userID)
+		}
+	}
+
+	entry := &models.DigestEntry{
+		RoomID:    message.RoomID,
+		MessageID: message.ID,
+	}
+	if err := s.repo.EnqueueDigestEntries(entry, others); err != nil {
+		fmt.Printf("Failed to queue digest entries: %v\n", err)
+	}
+	entry.Mention = true
+	if err := s.repo.EnqueueDigestEntries(entry, mentioned); err != nil {
+		fmt.Printf("Failed to queue digest entries: %v\n", err)
+	}
+}
+
+// markRoomRead drops the room's messages from the user's next digest once
+// they read or post in it.
+func (s *chatService) markRoomRead(roomID, userID string) {
+	if userID == "" {
+		return
+	}
+	if err := s.repo.ClearDigestEntries(userID, roomID); err != nil {
+		fmt.Printf("Failed to clear digest entries: %v\n", err)
+	}
+}
\ No newline at end of file
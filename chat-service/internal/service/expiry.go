@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// maxMessageTTL caps how long a disappearing message may live.
+const maxMessageTTL = 30 * 24 * time.Hour
+
+// SetMessageTTL sets how long new messages in the room live; 0 keeps them.
+// Messages already sent keep their expiry.
+func (s *chatService) SetMessageTTL(roomID, userID string, seconds int) (*models.Room, error) {
+	if seconds < 0 || time.Duration(seconds)*time.Second > maxMessageTTL {
+		return nil, ErrInvalidMessageTTL
+	}
+
+	if _, err := s.requireModerator(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateRoomMessageTTL(roomID, seconds); err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to update message time-to-live: %w", err)
+	}
+
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":                "message_ttl_changed",
+		"room_id":             roomID,
+		"message_ttl_seconds": room.MessageTTLSeconds,
+		"user_id":             userID,
+		"timestamp":           room.UpdatedAt.Unix(),
+	})
+
+	return room, nil
+}
+
+// ExpireMessages deletes up to limit messages whose time-to-live ran out and
+// returns how many it deleted. Members are told with a message_expired event.
+// Centrifugo cannot drop single publications, and clearing a room's history
+// would lose every other message in it, so clients drop expired messages
+// they recover from history themselves, going by the event or the
+// publication's expires_at.
+func (s *chatService) ExpireMessages(limit int) (int, error) {
+	expired, err := s.repo.DeleteExpiredMessages(limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired messages: %w", err)
+	}
+
+	for _, message := range expired {
+		s.publishRoomEvent(message.RoomID, map[string]any{
+			"type":       "message_expired",
+			"room_id":    message.RoomID,
+			"message_id": message.ID,
+			"seq":        message.Seq,
+			"timestamp":  message.ExpiresAt.Unix(),
+		})
+		s.publishReferenceUnavailable(message)
+	}
+
+	return len(expired), nil
+}
+
+// messageExpiry returns when a message sent now expires: after ttl if set,
+// but never later than the room's own time-to-live allows.
+func messageExpiry(room *models.Room, ttl time.Duration) *time.Time {
+	if roomTTL := time.Duration(room.MessageTTLSeconds) * time.Second; roomTTL > 0 && (ttl <= 0 || ttl > roomTTL) {
+		ttl = roomTTL
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().Add(ttl)
+	return &expiresAt
+}
+
+// referenceTTL caps ttl so that a message forwarding or quoting original
+// expires no later than original does. It returns ErrMessageNotFound if
+// original has already expired.
+func referenceTTL(original *models.Message, ttl time.Duration) (time.Duration, error) {
+	if original.ExpiresAt == nil {
+		return ttl, nil
+	}
+	remaining := time.Until(*original.ExpiresAt)
+	if remaining <= 0 {
+		return 0, ErrMessageNotFound
+	}
+	if ttl <= 0 || ttl > remaining {
+		ttl = remaining
+	}
+	return ttl, nil
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"chat-service/internal/export"
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// CreateExport queues an export of the room's full history. Only the room
+// owner and admins may export a room. If an export in the same format is
+// already queued or running, that job is returned instead of a new one.
+func (s *chatService) CreateExport(roomID, userID, format string) (*models.ExportJob, error) {
+	if !models.IsExportFormat(format) {
+		return nil, ErrInvalidExportFormat
+	}
+	room, err := s.requireExporter(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := s.repo.GetExportJobs(room.ID, 20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export jobs: %w", err)
+	}
+	for _, job := range jobs {
+		active := job.Status == models.ExportStatusPending || job.Status == models.ExportStatusRunning
+		if active && job.Format == format {
+			return job, nil
+		}
+	}
+
+	job := &models.ExportJob{
+		RoomID:      room.ID,
+		RequestedBy: userID,
+		Format:      format,
+		Status:      models.ExportStatusPending,
+	}
+	if err := s.repo.CreateExportJob(job); err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+	return job, nil
+}
+
+func (s *chatService) GetExport(exportID, userID string) (*models.ExportJob, error) {
+	job, err := s.repo.GetExportJob(exportID)
+	if err != nil {
+		if err == repository.ErrExportJobNotFound {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	if _, err := s.requireExporter(job.RoomID, userID); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListExports returns the room's most recent exports first.
+func (s *chatService) ListExports(roomID, userID string, limit int) ([]*models.ExportJob, error) {
+	if _, err := s.requireExporter(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	jobs, err := s.repo.GetExportJobs(roomID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// OpenExport opens the file of a completed export for download. The caller
+// closes it.
+func (s *chatService) OpenExport(exportID, userID string) (*models.ExportJob, io.ReadCloser, error) {
+	job, err := s.GetExport(exportID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !job.Downloadable(time.Now()) {
+		return nil, nil, ErrExportNotReady
+	}
+
+	file, err := os.Open(export.FilePath(export.Dir(), job.ID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrExportNotReady
+		}
+		return nil, nil, fmt.Errorf("failed to open export: %w", err)
+	}
+	return job, file, nil
+}
+
+// requireExporter returns the room if the user owns it or is an admin.
+func (s *chatService) requireExporter(roomID, userID string) (*models.Room, error) {
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.CreatedBy != userID && !s.isAdmin(userID) {
+		return nil, ErrPermissionDenied
+	}
+	return room, nil
+}
\ No newline at end of file
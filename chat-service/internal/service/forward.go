@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"chat-service/internal/models"
+)
+
+// ForwardMessage posts a copy of the message to the target room, referencing
+// the original. The user must be able to read the original's room and post
+// to the target room. The copy of a disappearing message expires with it.
+func (s *chatService) ForwardMessage(messageID, targetRoomID, userID, username string, opts SendMessageOptions) (*models.Message, error) {
+	if len(opts.ClientMessageID) > 64 {
+		return nil, ErrInvalidClientMessageID
+	}
+
+	original, reference, err := s.messageReference(models.ReferenceForward, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := referenceTTL(original, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	room, member, err := s.senderRoom(targetRoomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Polls and actions are forwarded as their text
+	return s.sendMessage(room, member, outgoingMessage{
+		userID:          userID,
+		username:        username,
+		content:         original.Content,
+		messageType:     models.MessageTypeText,
+		clientMessageID: opts.ClientMessageID,
+		reference:       reference,
+		ttl:             ttl,
+	})
+}
+
+// messageReference returns the message and a reference to it, if the user
+// can read its room.
+func (s *chatService) messageReference(referenceType, messageID, userID string) (*models.Message, models.MessageReference, error) {
+	original, err := s.getMessage(messageID)
+	if err != nil {
+		return nil, models.MessageReference{}, err
+	}
+
+	readable, err := s.canReadRoom(original.RoomID, userID)
+	if err != nil {
+		return nil, models.MessageReference{}, err
+	}
+	if !readable {
+		return nil, models.MessageReference{}, ErrSourceNotReadable
+	}
+
+	return original, models.NewMessageReference(referenceType, original), nil
+}
+
+// canReadRoom reports whether the user can read the room's messages, which
+// members and admins can.
+func (s *chatService) canReadRoom(roomID, userID string) (bool, error) {
+	if s.isAdmin(userID) {
+		return true, nil
+	}
+	isMember, err := s.repo.IsRoomMember(roomID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	return isMember, nil
+}
+
+// publishReferenceUnavailable tells the rooms of messages that forward or
+// quote a deleted message that the original is gone.
+func (s *chatService) publishReferenceUnavailable(deleted *models.Message) {
+	roomIDs, err := s.repo.GetReferencingRoomIDs(deleted.ID)
+	if err != nil {
+		log.Printf("Failed to find references to message %s: %v", deleted.ID, err)
+		return
+	}
+
+	for _, roomID := range roomIDs {
+		s.publishRoomEvent(roomID, map[string]any{
+			"type":                "reference_unavailable",
+			"room_id":             roomID,
+			"original_message_id": deleted.ID,
+			"original_room_id":    deleted.RoomID,
+			"timestamp":           time.Now().Unix(),
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// incomingTokenPrefix marks incoming webhook tokens so they are easy to spot
+// in configuration and secret scanners.
+const incomingTokenPrefix = "ihk_"
+
+// CreateIncomingWebhook creates a token that posts to the room as a bot named
+// name. The token is only returned from this call.
+func (s *chatService) CreateIncomingWebhook(roomID, userID, name string) (*models.IncomingWebhook, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 80 {
+		return nil, "", fmt.Errorf("%w: name must be between 1 and 80 characters", ErrInvalidWebhook)
+	}
+
+	room, err := s.requireModerator(roomID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if room.IsArchived() {
+		return nil, "", ErrRoomArchived
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token := incomingTokenPrefix + secret
+
+	hook := &models.IncomingWebhook{
+		RoomID:    roomID,
+		Name:      name,
+		TokenHash: hashIncomingToken(token),
+		CreatedBy: userID,
+	}
+	if err := s.repo.CreateIncomingWebhook(hook); err != nil {
+		return nil, "", fmt.Errorf("failed to create incoming webhook: %w", err)
+	}
+	return hook, token, nil
+}
+
+func (s *chatService) ListIncomingWebhooks(roomID, userID string) ([]*models.IncomingWebhook, error) {
+	if _, err := s.requireModerator(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	hooks, err := s.repo.GetIncomingWebhooks(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incoming webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+func (s *chatService) RevokeIncomingWebhook(id, userID string) (*models.IncomingWebhook, error) {
+	hook, err := s.repo.GetIncomingWebhook(id)
+	if err != nil {
+		return nil, incomingWebhookError(err)
+	}
+	if _, err := s.requireModerator(hook.RoomID, userID); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.repo.RevokeIncomingWebhook(id)
+	if err != nil {
+		return nil, incomingWebhookError(err)
+	}
+	return revoked, nil
+}
+
+// PostIncomingWebhook sends content to the token's room as its bot user. Unknown
+// and revoked tokens are reported the same way. The call carries no
+// organization, so the message is sent within the room's, which keeps the
+// bot's slash commands from reaching users of other organizations.
+func (s *chatService) PostIncomingWebhook(token, content string, opts SendMessageOptions) (*models.Message, error) {
+	if !strings.HasPrefix(token, incomingTokenPrefix) {
+		return nil, ErrIncomingWebhookNotFound
+	}
+
+	hook, err := s.repo.GetIncomingWebhookByTokenHash(hashIncomingToken(token))
+	if err != nil {
+		return nil, incomingWebhookError(err)
+	}
+	if hook.IsRevoked() {
+		return nil, ErrIncomingWebhookNotFound
+	}
+
+	room, err := s.repo.GetRoomByID(hook.RoomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrIncomingWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	message, err := s.ForOrg(room.OrgID, "", "").SendMessage(hook.RoomID, hook.BotUserID(), hook.Name, content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.TouchIncomingWebhook(hook.ID, time.Now()); err != nil {
+		fmt.Printf("Failed to record incoming webhook use: %v\n", err)
+	}
+	return message, nil
+}
+
+func hashIncomingToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func incomingWebhookError(err error) error {
+	if err == repository.ErrIncomingWebhookNotFound {
+		return ErrIncomingWebhookNotFound
+	}
+	return fmt.Errorf("failed to get incoming webhook: %w", err)
+}
\ No newline at end of file
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// UserDirectory looks up users in auth-service.
+type UserDirectory interface {
+	Usernames(ctx context.Context, userIDs []string) (map[string]string, error)
+	IsOrgMember(ctx context.Context, orgID, userID string) (bool, error)
+}
+
+// requireUser checks that auth-service knows the user, before they are added
+// to a room on someone else's behalf.
+func (s *chatService) requireUser(userID string) error {
+	usernames, err := s.users.Usernames(context.Background(), []string{userID})
+	if err != nil {
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+	if _, ok := usernames[userID]; !ok {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// RoomMemberInfo is a room member with their username and presence.
+type RoomMemberInfo struct {
+	*models.RoomMember
+	Username string
+	Online   bool
+}
+
+// RoomMembersPage is one page of GetRoomMembers. NextCursor is empty on the
+// last page, and OnlineCount counts the whole room.
+type RoomMembersPage struct {
+	Members     []*RoomMemberInfo
+	NextCursor  string
+	OnlineCount int
+}
+
+// GetRoomMembers lists a room's members in the order they joined. Usernames
+// and presence are best effort: if auth-service or Centrifugo cannot be
+// reached the members are still listed, without them.
+func (s *chatService) GetRoomMembers(roomID, userID, cursor string, limit int) (*RoomMembersPage, error) {
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	if !s.isAdmin(userID) {
+		isMember, err := s.repo.IsRoomMember(room.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check room membership: %w", err)
+		}
+		if !isMember {
+			return nil, ErrNotRoomMember
+		}
+	}
+
+	var after *repository.MemberCursor
+	if cursor != "" {
+		after, err = decodeMemberCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	members, err := s.repo.ListRoomMembers(room.ID, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room members: %w", err)
+	}
+
+	now := time.Now()
+	page := &RoomMembersPage{Members: make([]*RoomMemberInfo, len(members))}
+	for i, member := range members {
+		// Only report mutes that are still in effect
+		if !member.IsMuted(now) {
+			member.MutedUntil = nil
+		}
+		page.Members[i] = &RoomMemberInfo{RoomMember: member}
+	}
+	if len(members) == limit {
+		page.NextCursor = encodeMemberCursor(members[len(members)-1])
+	}
+
+	s.addUsernames(room.ID, page.Members)
+
+	online, err := s.presence.Get("room:" + room.ID)
+	if err != nil {
+		fmt.Printf("Failed to get room presence: %v\n", err)
+	}
+	page.OnlineCount = len(online)
+	for _, member := range page.Members {
+		_, member.Online = online[member.UserID]
+	}
+
+	return page, nil
+}
+
+// addUsernames fills in usernames from auth-service, and the names of
+// incoming webhook bots from their webhooks.
+func (s *chatService) addUsernames(roomID string, members []*RoomMemberInfo) {
+	var userIDs []string
+	hasBots := false
+	for _, member := range members {
+		if models.IsBotUserID(member.UserID) {
+			hasBots = true
+			continue
+		}
+		userIDs = append(userIDs, member.UserID)
+	}
+
+	names := map[string]string{}
+	if len(userIDs) > 0 && s.users != nil {
+		usernames, err := s.users.Usernames(context.Background(), userIDs)
+		if err != nil {
+			fmt.Printf("Failed to look up usernames: %v\n", err)
+		}
+		for id, username := range usernames {
+			names[id] = username
+		}
+	}
+	if hasBots {
+		hooks, err := s.repo.GetIncomingWebhooks(roomID)
+		if err != nil {
+			fmt.Printf("Failed to look up incoming webhooks: %v\n", err)
+		}
+		for _, hook := range hooks {
+			names[hook.BotUserID()] = hook.Name
+		}
+	}
+
+	for _, member := range members {
+		member.Username = names[member.UserID]
+	}
+}
+
+// encodeMemberCursor makes an opaque cursor pointing after the member.
+func encodeMemberCursor(member *models.RoomMember) string {
+	raw := strconv.FormatInt(member.JoinedAt.UnixNano(), 10) + ":" + member.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeMemberCursor(cursor string) (*repository.MemberCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	joinedAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.MemberCursor{JoinedAt: time.Unix(0, joinedAt), ID: id}, nil
+}
\ No newline at end of file
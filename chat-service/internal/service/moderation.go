@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/moderation"
+)
+
+// moderationPipelines caches compiled pipelines per room. Entries are rebuilt
+// when the room has been updated since they were compiled.
+type moderationPipelines struct {
+	defaults []moderation.FilterConfig
+	fallback *moderation.Pipeline
+
+	mu    sync.Mutex
+	rooms map[string]cachedPipeline
+}
+
+type cachedPipeline struct {
+	updatedAt time.Time
+	pipeline  *moderation.Pipeline
+}
+
+func newModerationPipelines(defaultConfigPath string) *moderationPipelines {
+	defaults, err := moderation.LoadConfigFile(defaultConfigPath)
+	if err != nil {
+		log.Printf("Ignoring default moderation config: %v", err)
+		defaults = nil
+	}
+
+	fallback, err := moderation.Build(defaults)
+	if err != nil {
+		fallback = moderation.NewPipeline()
+	}
+
+	return &moderationPipelines{
+		defaults: defaults,
+		fallback: fallback,
+		rooms:    make(map[string]cachedPipeline),
+	}
+}
+
+func (m *moderationPipelines) run(room *models.Room, content string) (moderation.Result, error) {
+	pipeline, err := m.pipelineFor(room)
+	if err != nil {
+		return moderation.Result{}, err
+	}
+	return pipeline.Run(content), nil
+}
+
+func (m *moderationPipelines) pipelineFor(room *models.Room) (*moderation.Pipeline, error) {
+	if room.ModerationFilters == nil {
+		return m.fallback, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached, ok := m.rooms[room.ID]
+	if ok && cached.updatedAt.Equal(room.UpdatedAt) {
+		return cached.pipeline, nil
+	}
+
+	pipeline, err := moderation.Build(room.ModerationFilters)
+	if err != nil {
+		return nil, err
+	}
+	m.rooms[room.ID] = cachedPipeline{updatedAt: room.UpdatedAt, pipeline: pipeline}
+	return pipeline, nil
+}
+
+func (s *chatService) SetRoomModeration(roomID, userID string, filters []moderation.FilterConfig) (*models.Room, error) {
+	if _, err := moderation.Build(filters); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModerationConfig, err)
+	}
+
+	if _, err := s.requireOwner(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.UpdateRoomModeration(roomID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update room moderation: %w", err)
+	}
+
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	return room, nil
+}
+
+// GetRoomModeration returns the filters in effect for the room and whether
+// they are the server defaults.
+func (s *chatService) GetRoomModeration(roomID, userID string) ([]moderation.FilterConfig, bool, error) {
+	room, err := s.requireModerator(roomID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if room.ModerationFilters == nil {
+		return s.moderation.defaults, true, nil
+	}
+	return room.ModerationFilters, false, nil
+}
+
+// ListModerationQueue lists queue items of one room or, with an empty roomID,
+// of every room the user moderates. Admins see all rooms.
+func (s *chatService) ListModerationQueue(roomID, userID, status string, limit int) ([]*models.ModerationItem, error) {
+	roomIDs, err := s.moderatedRooms(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	items, err := s.repo.GetModerationItems(roomIDs, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+	return items, nil
+}
+
+// queueFlags adds flagged messages to the review queue. A failure is logged
+// rather than returned because the message has already been stored.
+func (s *chatService) queueFlags(message *models.Message, flags []moderation.Verdict) {
+	items := make([]*models.ModerationItem, len(flags))
+	for i, flag := range flags {
+		items[i] = &models.ModerationItem{
+			RoomID:    message.RoomID,
+			MessageID: &message.ID,
+			AuthorID:  message.UserID,
+			Content:   message.Content,
+			Source:    models.ModerationSourceFilter,
+			Filter:    flag.Filter,
+			Reason:    flag.Reason,
+			Detail:    flag.Detail,
+		}
+	}
+
+	if err := s.repo.CreateModerationItems(items); err != nil {
+		log.Printf("Failed to queue flagged message %s: %v", message.ID, err)
+	}
+}
\ No newline at end of file
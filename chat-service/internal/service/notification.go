@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// notificationExcerptLength is how much of a message a notification shows.
+const notificationExcerptLength = 140
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.:-]+)`)
+
+// roomMentions mention every member of the room.
+var roomMentions = map[string]bool{
+	"all":      true,
+	"channel":  true,
+	"everyone": true,
+	"here":     true,
+}
+
+// DNDSchedule is a user's do-not-disturb schedule.
+type DNDSchedule struct {
+	Enabled bool
+	// Start and End are times of day as "15:04" in TimeZone.
+	Start    string
+	End      string
+	TimeZone string
+}
+
+// GetRoomNotifications returns the user's membership with its notification
+// settings.
+func (s *chatService) GetRoomNotifications(roomID, userID string) (*models.RoomMember, error) {
+	member, err := s.repo.GetRoomMember(roomID, userID)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return nil, ErrNotRoomMember
+		}
+		return nil, fmt.Errorf("failed to get room member: %w", err)
+	}
+	return member, nil
+}
+
+// SetRoomNotifications sets which of the room's messages notify the user, and
+// optionally silences the room until the given time. A mute time in the past
+// unmutes the room.
+func (s *chatService) SetRoomNotifications(roomID, userID, level string, mutedUntil *time.Time) (*models.RoomMember, error) {
+	if !models.IsNotifyLevel(level) {
+		return nil, ErrInvalidNotifyLevel
+	}
+	if mutedUntil != nil && !mutedUntil.After(time.Now()) {
+		mutedUntil = nil
+	}
+
+	member, err := s.repo.UpdateRoomNotifications(roomID, userID, level, mutedUntil)
+	if err != nil {
+		if err == repository.ErrMemberNotFound {
+			return nil, ErrNotRoomMember
+		}
+		return nil, fmt.Errorf("failed to update notification settings: %w", err)
+	}
+
+	s.publishUserEvent(userID, map[string]any{
+		"type":                      "room_notifications_updated",
+		"room_id":                   roomID,
+		"notify_level":              member.NotifyLevel,
+		"notifications_muted_until": member.NotificationsMutedUntil,
+		"timestamp":                 time.Now().Unix(),
+	})
+
+	return member, nil
+}
+
+func (s *chatService) GetNotificationSettings(userID string) (*models.NotificationSettings, error) {
+	settings, err := s.repo.GetNotificationSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SetDNDSchedule replaces the user's do-not-disturb schedule. The time zone
+// defaults to UTC.
+func (s *chatService) SetDNDSchedule(userID string, schedule DNDSchedule) (*models.NotificationSettings, error) {
+	if schedule.TimeZone == "" {
+		schedule.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+		return nil, ErrInvalidDNDSchedule
+	}
+	if schedule.Enabled || schedule.Start != "" || schedule.End != "" {
+		start, okStart := models.ParseTimeOfDay(schedule.Start)
+		end, okEnd := models.ParseTimeOfDay(schedule.End)
+		if !okStart || !okEnd || start == end {
+			return nil, ErrInvalidDNDSchedule
+		}
+	}
+
+	err := s.repo.SaveNotificationSettings(&models.NotificationSettings{
+		UserID:     userID,
+		DNDEnabled: schedule.Enabled,
+		DNDStart:   schedule.Start,
+		DNDEnd:     schedule.End,
+		TimeZone:   schedule.TimeZone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save notification settings: %w", err)
+	}
+
+	// Read back the settings this call left alone
+	settings, err := s.repo.GetNotificationSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	s.publishUserEvent(userID, map[string]any{
+		"type":      "notification_settings_updated",
+		"settings":  settings,
+		"timestamp": settings.UpdatedAt.Unix(),
+	})
+
+	return settings, nil
+}
+
+// notifyMembers sends a notification of a new message to the personal
+// channel of every member it concerns: members notified of all messages,
+// and those notified of mentions if the message mentions them or the whole
+// room. Members who silenced the room or are in their do-not-disturb window
+// are not notified. Notified members who are offline also get a push, and
+// the message is kept for their email digest.
+func (s *chatService) notifyMembers(message *models.Message) {
+	now := time.Now()
+	recipients, err := s.repo.GetNotificationRecipients(message.RoomID, message.UserID, now)
+	if err != nil {
+		fmt.Printf("Failed to get notification recipients: %v\n", err)
+		return
+	}
+
+	text := message.PlainText
+	if text == "" {
+		text = message.Content
+	}
+	mentioned := s.mentionedUsers(text, recipients)
+
+	excerpt := text
+	if runes := []rune(excerpt); len(runes) > notificationExcerptLength {
+		excerpt = string(runes[:notificationExcerptLength]) + "…"
+	}
+
+	// Notified members, and whether the message mentions them
+	notified := make(map[string]bool)
+	digestOptOut := make(map[string]bool)
+	for _, recipient := range recipients {
+		if models.IsBotUserID(recipient.UserID) {
+			continue
+		}
+		mention := mentioned[recipient.UserID]
+		if recipient.NotifyLevel == models.NotifyMentions && !mention {
+			continue
+		}
+		if recipient.Settings.InDoNotDisturb(now) {
+			continue
+		}
+
+		data := map[string]any{
+			"type":       "notification",
+			"room_id":    message.RoomID,
+			"message_id": message.ID,
+			"seq":        message.Seq,
+			"user_id":    message.UserID,
+			"username":   message.Username,
+			"content":    excerpt,
+			"mention":    mention,
+			"timestamp":  message.CreatedAt.Unix(),
+		}
+		if err := s.centrifugo.Publish(userChannel(recipient.UserID), data); err != nil {
+			fmt.Printf("Failed to publish notification to centrifugo: %v\n", err)
+		}
+		notified[recipient.UserID] = mention
+		if recipient.Settings.DigestOptOut {
+			digestOptOut[recipient.UserID] = true
+		}
+	}
+	if len(notified) == 0 {
+		return
+	}
+
+	offline := s.offlineMembers(message.RoomID, notified)
+	if len(offline) == 0 {
+		return
+	}
+	s.queuePushes(message, excerpt, offline)
+
+	for userID := range digestOptOut {
+		delete(offline, userID)
+	}
+	s.queueDigestEntries(message, offline)
+}
+
+// offlineMembers returns the members who are not in the room according to
+// Centrifugo presence. If presence cannot be checked, everyone is taken to
+// be offline.
+func (s *chatService) offlineMembers(roomID string, members map[string]bool) map[string]bool {
+	online, err := s.presence.Get("room:" + roomID)
+	if err != nil {
+		fmt.Printf("Failed to get room presence: %v\n", err)
+	}
+
+	offline := make(map[string]bool, len(members))
+	for userID, mention := range members {
+		if _, ok := online[userID]; !ok {
+			offline[userID] = mention
+		}
+	}
+	return offline
+}
+
+// mentionedUsers returns which recipients the text mentions, by username or
+// user ID, or all of them for @all, @channel, @everyone and @here.
+func (s *chatService) mentionedUsers(text string, recipients []*repository.NotificationRecipient) map[string]bool {
+	matches := mentionPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	names := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		name := strings.ToLower(strings.TrimRight(match[1], ".:-"))
+		if roomMentions[name] {
+			mentioned := make(map[string]bool, len(recipients))
+			for _, recipient := range recipients {
+				mentioned[recipient.UserID] = true
+			}
+			return mentioned
+		}
+		names[name] = true
+	}
+
+	userIDs := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		if !models.IsBotUserID(recipient.UserID) {
+			userIDs = append(userIDs, recipient.UserID)
+		}
+	}
+	usernames := map[string]string{}
+	if len(userIDs) > 0 && s.users != nil {
+		found, err := s.users.Usernames(context.Background(), userIDs)
+		if err != nil {
+			// Mentions by user ID still work
+			fmt.Printf("Failed to look up usernames: %v\n", err)
+		}
+		if found != nil {
+			usernames = found
+		}
+	}
+
+	mentioned := make(map[string]bool)
+	for _, userID := range userIDs {
+		if names[strings.ToLower(userID)] || names[strings.ToLower(usernames[userID])] {
+			mentioned[userID] = true
+		}
+	}
+	return mentioned
+}
\ No newline at end of file
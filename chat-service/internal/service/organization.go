@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Organization roles whose members administer every room of the organization.
+const (
+	OrgRoleOwner = "owner"
+	OrgRoleAdmin = "admin"
+)
+
+var ErrNotOrgMember = errors.New("user is not a member of this organization")
+
+// ForOrg returns a service that acts within the organization: its queries
+// only see the organization's rooms. orgRole is the role of callerID in the
+// organization; owners and admins administer all of its rooms like
+// CHAT_ADMINS do, but only when acting as themselves.
+func (s *chatService) ForOrg(orgID, orgRole, callerID string) ChatService {
+	scoped := *s
+	scoped.repo = s.repo.ForOrg(orgID)
+	scoped.orgID = orgID
+	scoped.orgAdminID = ""
+	if orgRole == OrgRoleOwner || orgRole == OrgRoleAdmin {
+		scoped.orgAdminID = callerID
+	}
+	return &scoped
+}
+
+// requireOrgMember checks that the user belongs to the service's
+// organization, before they are added to one of its rooms on someone else's
+// behalf.
+func (s *chatService) requireOrgMember(userID string) error {
+	if s.orgID == "" {
+		return nil
+	}
+
+	isMember, err := s.users.IsOrgMember(context.Background(), s.orgID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check organization membership: %w", err)
+	}
+	if !isMember {
+		return ErrNotOrgMember
+	}
+	return nil
+}
\ No newline at end of file
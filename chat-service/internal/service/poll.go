@@ -0,0 +1,211 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+const (
+	minPollOptions        = 2
+	maxPollOptions        = 10
+	maxPollQuestionLength = 300
+	maxPollOptionLength   = 100
+)
+
+// PollParams describes a new poll.
+type PollParams struct {
+	Question       string
+	Options        []string
+	MultipleChoice bool
+	// Anonymous hides who voted for which option.
+	Anonymous bool
+	// ClosesAt optionally stops voting at the given time.
+	ClosesAt *time.Time
+}
+
+// CreatePoll posts a poll message. The question becomes the message content
+// so clients that do not render polls still show something sensible.
+func (s *chatService) CreatePoll(roomID, userID, username string, params PollParams) (*models.Message, error) {
+	question := strings.TrimSpace(params.Question)
+	if question == "" || len(question) > maxPollQuestionLength {
+		return nil, ErrInvalidPoll
+	}
+	if len(params.Options) < minPollOptions || len(params.Options) > maxPollOptions {
+		return nil, ErrInvalidPoll
+	}
+	if params.ClosesAt != nil && !params.ClosesAt.After(time.Now()) {
+		return nil, ErrInvalidPoll
+	}
+
+	texts := make([]string, len(params.Options))
+	for i, text := range params.Options {
+		texts[i] = strings.TrimSpace(text)
+		if texts[i] == "" || len(texts[i]) > maxPollOptionLength {
+			return nil, ErrInvalidPoll
+		}
+	}
+
+	room, member, err := s.senderRoom(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	release, err := s.acquireSendSlot(room, member)
+	if err != nil {
+		return nil, err
+	}
+
+	moderatedQuestion, err := s.moderate(room, question)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	flags := moderatedQuestion.Flags
+
+	options := make([]models.PollOption, len(texts))
+	for i, text := range texts {
+		moderatedOption, err := s.moderate(room, text)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		flags = append(flags, moderatedOption.Flags...)
+
+		options[i] = models.PollOption{Position: i, Text: moderatedOption.Content}
+	}
+
+	message := &models.Message{
+		RoomID:    roomID,
+		UserID:    userID,
+		Username:  username,
+		Content:   moderatedQuestion.Content,
+		Type:      models.MessageTypePoll,
+		ExpiresAt: messageExpiry(room, 0),
+	}
+	poll := &models.Poll{
+		Question:       moderatedQuestion.Content,
+		MultipleChoice: params.MultipleChoice,
+		Anonymous:      params.Anonymous,
+		ClosesAt:       params.ClosesAt,
+		CreatedBy:      userID,
+		Options:        options,
+	}
+
+	if err := s.repo.CreatePollMessage(message, poll); err != nil {
+		release()
+		return nil, fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	if len(flags) > 0 {
+		s.queueFlags(message, flags)
+	}
+
+	s.publishMessage(message, messageEventData(message))
+
+	return message, nil
+}
+
+// VotePoll sets the user's choice. Voting again replaces the previous choice
+// and an empty optionIDs retracts it.
+func (s *chatService) VotePoll(pollID, userID string, optionIDs []string) (*models.Poll, error) {
+	poll, err := s.getPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.repo.GetRoomByID(poll.RoomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+
+	isMember, err := s.repo.IsRoomMember(poll.RoomID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotRoomMember
+	}
+
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return nil, ErrInvalidVote
+	}
+	valid := make(map[string]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.ID] = true
+	}
+	seen := make(map[string]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if !valid[optionID] || seen[optionID] {
+			return nil, ErrInvalidVote
+		}
+		seen[optionID] = true
+	}
+
+	updated, err := s.repo.SetPollVotes(pollID, userID, optionIDs)
+	if err != nil {
+		return nil, pollError(err)
+	}
+
+	s.publishPollUpdate(updated)
+	return updated, nil
+}
+
+// ClosePoll stops voting. The poll's creator and the room's moderators may
+// close it.
+func (s *chatService) ClosePoll(pollID, userID string) (*models.Poll, error) {
+	poll, err := s.getPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.CreatedBy != userID {
+		if _, err := s.requireModerator(poll.RoomID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	closed, err := s.repo.ClosePoll(pollID)
+	if err != nil {
+		return nil, pollError(err)
+	}
+
+	s.publishPollUpdate(closed)
+	return closed, nil
+}
+
+func (s *chatService) getPoll(pollID string) (*models.Poll, error) {
+	poll, err := s.repo.GetPoll(pollID)
+	if err != nil {
+		return nil, pollError(err)
+	}
+	return poll, nil
+}
+
+// publishPollUpdate sends the current tallies to the room.
+func (s *chatService) publishPollUpdate(poll *models.Poll) {
+	s.publishRoomEvent(poll.RoomID, map[string]any{
+		"type":       "poll_updated",
+		"room_id":    poll.RoomID,
+		"poll_id":    poll.ID,
+		"message_id": poll.MessageID,
+		"poll":       poll,
+		"closed":     poll.IsClosed(time.Now()),
+		"timestamp":  time.Now().Unix(),
+	})
+}
+
+func pollError(err error) error {
+	switch err {
+	case repository.ErrPollNotFound:
+		return ErrPollNotFound
+	case repository.ErrPollClosed:
+		return ErrPollClosed
+	}
+	return fmt.Errorf("failed to update poll: %w", err)
+}
\ No newline at end of file
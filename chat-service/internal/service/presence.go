@@ -0,0 +1,82 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPresenceEntries is how many channels the cache holds before expired
+// entries are swept.
+const maxPresenceEntries = 1024
+
+// presenceCache keeps Centrifugo presence results for a short time, so
+// listing the members of a busy room does not hit Centrifugo on every page.
+// Concurrent lookups of the same channel share a single request.
+type presenceCache struct {
+	fetch func(channel string) (map[string]struct{}, error)
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*presenceEntry
+}
+
+type presenceEntry struct {
+	// ready is closed once users and err are set
+	ready     chan struct{}
+	users     map[string]struct{}
+	err       error
+	fetchedAt time.Time
+}
+
+func newPresenceCache(ttl time.Duration, fetch func(channel string) (map[string]struct{}, error)) *presenceCache {
+	return &presenceCache{
+		fetch:   fetch,
+		ttl:     ttl,
+		entries: make(map[string]*presenceEntry),
+	}
+}
+
+// Get returns the IDs of the users subscribed to the channel. Failed lookups
+// are cached too, so an unavailable Centrifugo is not retried on every call.
+func (c *presenceCache) Get(channel string) (map[string]struct{}, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[channel]; ok {
+		select {
+		case <-entry.ready:
+			if time.Since(entry.fetchedAt) < c.ttl {
+				c.mu.Unlock()
+				return entry.users, entry.err
+			}
+		default:
+			c.mu.Unlock()
+			<-entry.ready
+			return entry.users, entry.err
+		}
+	}
+
+	if len(c.entries) >= maxPresenceEntries {
+		c.sweep()
+	}
+	entry := &presenceEntry{ready: make(chan struct{})}
+	c.entries[channel] = entry
+	c.mu.Unlock()
+
+	entry.users, entry.err = c.fetch(channel)
+	entry.fetchedAt = time.Now()
+	close(entry.ready)
+
+	return entry.users, entry.err
+}
+
+// sweep drops expired entries. The caller holds mu.
+func (c *presenceCache) sweep() {
+	for channel, entry := range c.entries {
+		select {
+		case <-entry.ready:
+			if time.Since(entry.fetchedAt) >= c.ttl {
+				delete(c.entries, channel)
+			}
+		default:
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+
+	"chat-service/internal/models"
+	"chat-service/internal/push"
+	"chat-service/internal/repository"
+)
+
+// maxPushTokenLength bounds device tokens; FCM tokens are the longest at a
+// few hundred characters.
+const maxPushTokenLength = 4096
+
+// RegisterPushDevice registers a device to receive pushes for the user.
+// Registering a token again refreshes it, and moves it to the user if it
+// belonged to someone else, such as after signing in with another account.
+func (s *chatService) RegisterPushDevice(userID, platform, token string) (*models.PushDevice, error) {
+	if !push.IsPlatform(platform) || token == "" || len(token) > maxPushTokenLength {
+		return nil, ErrInvalidPushDevice
+	}
+
+	device, err := s.repo.RegisterPushDevice(&models.PushDevice{
+		UserID:   userID,
+		Platform: platform,
+		Token:    token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register push device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *chatService) ListPushDevices(userID string) ([]*models.PushDevice, error) {
+	devices, err := s.repo.GetPushDevices(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get push devices: %w", err)
+	}
+	return devices, nil
+}
+
+func (s *chatService) UnregisterPushDevice(userID, deviceID string) error {
+	if err := s.repo.DeletePushDevice(userID, deviceID); err != nil {
+		if err == repository.ErrPushDeviceNotFound {
+			return ErrPushDeviceNotFound
+		}
+		return fmt.Errorf("failed to unregister push device: %w", err)
+	}
+	return nil
+}
+
+// queuePushes queues a push of the message for the devices of offline
+// members, mapped to whether the message mentions them.
+func (s *chatService) queuePushes(message *models.Message, excerpt string, offline map[string]bool) {
+	var mentioned, others []string
+	for userID, mention := range offline {
+		if mention {
+			mentioned = append(mentioned, userID)
+		} else {
+			others = append(others, userID)
+		}
+	}
+
+	title := "New message"
+	if room, err := s.repo.GetRoomByID(message.RoomID); err == nil {
+		title = room.Name
+	}
+	job := &models.PushJob{
+		RoomID:    message.RoomID,
+		MessageID: message.ID,
+		Title:     title,
+		Body:      message.Username + ": " + excerpt,
+	}
+
+	if _, err := s.repo.EnqueuePushJobs(job, others); err != nil {
+		fmt.Printf("Failed to queue pushes: %v\n", err)
+	}
+	job.Mention = true
+	if _, err := s.repo.EnqueuePushJobs(job, mentioned); err != nil {
+		fmt.Printf("Failed to queue pushes: %v\n", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// maxSlowModeSeconds caps slow mode at six hours.
+const maxSlowModeSeconds = 6 * 60 * 60
+
+// RateLimitError is returned by SendMessage when the user has to wait before
+// sending another message.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	// SlowMode is set when the room's slow mode caused the error, as opposed
+	// to the user's overall send rate.
+	SlowMode bool
+}
+
+func (e *RateLimitError) Error() string {
+	if e.SlowMode {
+		return fmt.Sprintf("room is in slow mode, retry after %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("sending too fast, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func (s *chatService) SetSlowMode(roomID, userID string, seconds int) (*models.Room, error) {
+	if seconds < 0 || seconds > maxSlowModeSeconds {
+		return nil, ErrInvalidSlowMode
+	}
+
+	if _, err := s.requireModerator(roomID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateRoomSlowMode(roomID, seconds); err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to update slow mode: %w", err)
+	}
+
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+
+	s.publishRoomEvent(roomID, map[string]any{
+		"type":              "slow_mode_changed",
+		"room_id":           roomID,
+		"slow_mode_seconds": room.SlowModeSeconds,
+		"user_id":           userID,
+		"timestamp":         room.UpdatedAt.Unix(),
+	})
+
+	return room, nil
+}
+
+// acquireSendSlot applies the room's slow mode and the user's send rate.
+// Owners, moderators and admins are exempt from slow mode but not from the
+// rate limit. The returned release gives the slot back when the send does not
+// go through after all.
+func (s *chatService) acquireSendSlot(room *models.Room, member *models.RoomMember) (release func(), err error) {
+	limits := repository.SendLimits{
+		Burst:  s.sendRateBurst,
+		Refill: s.sendRateRefill,
+	}
+	if models.IsBotUserID(member.UserID) {
+		limits.Burst = s.botRateBurst
+		limits.Refill = s.botRateRefill
+	}
+
+	exempt := room.CreatedBy == member.UserID || member.CanModerate() || s.isAdmin(member.UserID)
+	if room.SlowModeSeconds > 0 && !exempt {
+		limits.SlowMode = time.Duration(room.SlowModeSeconds) * time.Second
+	}
+
+	if limits.SlowMode == 0 && (limits.Burst <= 0 || limits.Refill <= 0) {
+		return func() {}, nil
+	}
+
+	slot, err := s.repo.AcquireSendSlot(room.ID, member.UserID, limits, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check send rate: %w", err)
+	}
+	if !slot.Allowed {
+		return nil, &RateLimitError{RetryAfter: slot.RetryAfter, SlowMode: slot.SlowMode}
+	}
+	return func() {
+		if err := s.repo.ReleaseSendSlot(room.ID, member.UserID, limits, slot); err != nil {
+			fmt.Printf("Failed to release send slot: %v\n", err)
+		}
+	}, nil
+}
\ No newline at end of file
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+const (
+	maxReportReasonLength = 100
+	maxReportDetailLength = 1000
+
+	// maxMuteDuration caps how long a mute resolution may last.
+	maxMuteDuration = 30 * 24 * time.Hour
+)
+
+// ReportMessage queues a message for moderator review on behalf of a room
+// member.
+func (s *chatService) ReportMessage(messageID, userID, reason, detail string) (*models.ModerationItem, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > maxReportReasonLength || len(detail) > maxReportDetailLength {
+		return nil, ErrInvalidReport
+	}
+
+	message, err := s.repo.GetMessageByID(messageID)
+	if err != nil {
+		if err == repository.ErrMessageNotFound {
+			return nil, ErrMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if message.UserID == userID {
+		return nil, ErrInvalidReport
+	}
+
+	isMember, err := s.repo.IsRoomMember(message.RoomID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotRoomMember
+	}
+
+	item := &models.ModerationItem{
+		RoomID:     message.RoomID,
+		MessageID:  &message.ID,
+		AuthorID:   message.UserID,
+		Content:    message.Content,
+		Source:     models.ModerationSourceReport,
+		ReporterID: userID,
+		Reason:     reason,
+		Detail:     detail,
+	}
+
+	if err := s.repo.CreateReport(item); err != nil {
+		if err == repository.ErrAlreadyReported {
+			return nil, ErrAlreadyReported
+		}
+		return nil, fmt.Errorf("failed to report message: %w", err)
+	}
+
+	item.Message = message
+	return item, nil
+}
+
+func (s *chatService) ClaimModerationItem(itemID, userID string) (*models.ModerationItem, error) {
+	item, err := s.getModerationItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.requireModerator(item.RoomID, userID); err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.repo.ClaimModerationItem(itemID, userID)
+	if err != nil {
+		return nil, moderationItemError(err)
+	}
+
+	claimed.Message = item.Message
+	return claimed, nil
+}
+
+// ResolveModerationItem closes a queue item with one of the resolutions.
+// Items claimed by another moderator can only be resolved by the room owner
+// or an admin. Owners cannot be muted or banned, and only owners and admins
+// can mute or ban a moderator.
+func (s *chatService) ResolveModerationItem(itemID, userID, action, note string, muteFor time.Duration) (*models.ModerationItem, error) {
+	switch action {
+	case models.ResolutionDismiss, models.ResolutionDeleteMessage, models.ResolutionBan:
+	case models.ResolutionMute:
+		if muteFor <= 0 || muteFor > maxMuteDuration {
+			return nil, ErrInvalidResolution
+		}
+	default:
+		return nil, ErrInvalidResolution
+	}
+
+	item, err := s.getModerationItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.requireModerator(item.RoomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	privileged := room.CreatedBy == userID || s.isAdmin(userID)
+
+	if action == models.ResolutionMute || action == models.ResolutionBan {
+		if item.AuthorID == room.CreatedBy {
+			return nil, ErrPermissionDenied
+		}
+		if !privileged {
+			target, err := s.repo.GetRoomMember(item.RoomID, item.AuthorID)
+			if err != nil && err != repository.ErrMemberNotFound {
+				return nil, fmt.Errorf("failed to get room member: %w", err)
+			}
+			if target != nil && target.CanModerate() {
+				return nil, ErrPermissionDenied
+			}
+		}
+	}
+
+	resolved, err := s.repo.ResolveModerationItem(repository.ModerationResolution{
+		ItemID:      itemID,
+		ModeratorID: userID,
+		Action:      action,
+		Note:        note,
+		MuteFor:     muteFor,
+		Override:    privileged,
+	})
+	if err != nil {
+		return nil, moderationItemError(err)
+	}
+
+	now := time.Now()
+	switch action {
+	case models.ResolutionDeleteMessage:
+		if item.Message != nil {
+			s.publishRoomEvent(item.RoomID, map[string]any{
+				"type":       "message_deleted",
+				"room_id":    item.RoomID,
+				"message_id": item.Message.ID,
+				"seq":        item.Message.Seq,
+				"user_id":    userID,
+				"timestamp":  now.Unix(),
+			})
+			s.publishReferenceUnavailable(item.Message)
+		}
+	case models.ResolutionMute:
+		s.publishRoomEvent(item.RoomID, map[string]any{
+			"type":        "member_muted",
+			"room_id":     item.RoomID,
+			"user_id":     item.AuthorID,
+			"muted_until": now.Add(muteFor).Unix(),
+			"timestamp":   now.Unix(),
+		})
+	case models.ResolutionBan:
+		s.publishRoomEvent(item.RoomID, map[string]any{
+			"type":      "member_banned",
+			"room_id":   item.RoomID,
+			"user_id":   item.AuthorID,
+			"timestamp": now.Unix(),
+		})
+		go func() {
+			if err := s.centrifugo.Unsubscribe(item.AuthorID, "room:"+item.RoomID); err != nil {
+				fmt.Printf("Failed to unsubscribe banned user: %v\n", err)
+			}
+		}()
+	}
+
+	if action != models.ResolutionDeleteMessage {
+		resolved.Message = item.Message
+	}
+	return resolved, nil
+}
+
+// ListModerationActions returns the audit log of one room or, with an empty
+// roomID, of every room the user moderates.
+func (s *chatService) ListModerationActions(roomID, userID string, limit int) ([]*models.ModerationAction, error) {
+	roomIDs, err := s.moderatedRooms(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	actions, err := s.repo.GetModerationActions(roomIDs, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation actions: %w", err)
+	}
+	return actions, nil
+}
+
+func (s *chatService) getModerationItem(itemID string) (*models.ModerationItem, error) {
+	item, err := s.repo.GetModerationItem(itemID)
+	if err != nil {
+		if err == repository.ErrModerationItemNotFound {
+			return nil, ErrModerationItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get moderation item: %w", err)
+	}
+	return item, nil
+}
+
+// moderatedRooms resolves the rooms a queue or audit listing covers. A nil
+// result means every room.
+func (s *chatService) moderatedRooms(roomID, userID string) ([]string, error) {
+	if roomID != "" {
+		if _, err := s.requireModerator(roomID, userID); err != nil {
+			return nil, err
+		}
+		return []string{roomID}, nil
+	}
+
+	if s.isAdmin(userID) {
+		return nil, nil
+	}
+
+	roomIDs, err := s.repo.GetModeratedRoomIDs(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderated rooms: %w", err)
+	}
+	if roomIDs == nil {
+		roomIDs = []string{}
+	}
+	return roomIDs, nil
+}
+
+// isAdmin reports whether the user administers every room: as one of
+// CHAT_ADMINS, or as the caller the service was scoped for, if they own or
+// administer its organization.
+func (s *chatService) isAdmin(userID string) bool {
+	if _, ok := s.admins[userID]; ok {
+		return true
+	}
+	return s.orgAdminID != "" && userID == s.orgAdminID
+}
+
+func moderationItemError(err error) error {
+	switch err {
+	case repository.ErrModerationItemNotFound:
+		return ErrModerationItemNotFound
+	case repository.ErrItemClaimed:
+		return ErrItemClaimed
+	case repository.ErrItemResolved:
+		return ErrItemResolved
+	case repository.ErrMessageNotFound:
+		return ErrMessageNotFound
+	}
+	return fmt.Errorf("failed to update moderation item: %w", err)
+}
\ No newline at end of file
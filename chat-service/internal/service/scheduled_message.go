@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// ScheduleMessage stores a message for the scheduler to send at the given
+// time. Membership is checked now and again when the message is sent. Slash
+// commands cannot be scheduled.
+func (s *chatService) ScheduleMessage(roomID, userID, username, content string, at time.Time) (*models.ScheduledMessage, error) {
+	if content == "" {
+		return nil, ErrInvalidMessage
+	}
+	if _, _, ok := parseCommand(content); ok {
+		return nil, ErrCommandNotScheduled
+	}
+	if err := s.validateScheduleTime(at); err != nil {
+		return nil, err
+	}
+
+	room, err := s.repo.GetRoomByID(roomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return nil, ErrRoomNotFound
+		}
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+
+	isMember, err := s.repo.IsRoomMember(room.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check room membership: %w", err)
+	}
+	if !isMember {
+		return nil, ErrNotRoomMember
+	}
+
+	scheduled := &models.ScheduledMessage{
+		RoomID:      roomID,
+		UserID:      userID,
+		Username:    username,
+		Content:     content,
+		ScheduledAt: at.UTC(),
+	}
+
+	if err := s.repo.CreateScheduledMessage(scheduled); err != nil {
+		return nil, fmt.Errorf("failed to schedule message: %w", err)
+	}
+	return scheduled, nil
+}
+
+func (s *chatService) ListScheduledMessages(userID, roomID, status string) ([]*models.ScheduledMessage, error) {
+	scheduled, err := s.repo.GetScheduledMessages(userID, roomID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled messages: %w", err)
+	}
+	return scheduled, nil
+}
+
+// UpdateScheduledMessage changes the content or time of a pending message.
+func (s *chatService) UpdateScheduledMessage(id, userID string, content *string, at *time.Time) (*models.ScheduledMessage, error) {
+	if _, err := s.getOwnScheduledMessage(id, userID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]any{}
+	if content != nil {
+		if *content == "" {
+			return nil, ErrInvalidMessage
+		}
+		if _, _, ok := parseCommand(*content); ok {
+			return nil, ErrCommandNotScheduled
+		}
+		updates["content"] = *content
+	}
+	if at != nil {
+		if err := s.validateScheduleTime(*at); err != nil {
+			return nil, err
+		}
+		updates["scheduled_at"] = at.UTC()
+	}
+
+	scheduled, err := s.repo.UpdatePendingScheduledMessage(id, updates)
+	if err != nil {
+		return nil, scheduledMessageError(err)
+	}
+	return scheduled, nil
+}
+
+func (s *chatService) CancelScheduledMessage(id, userID string) (*models.ScheduledMessage, error) {
+	if _, err := s.getOwnScheduledMessage(id, userID); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := s.repo.UpdatePendingScheduledMessage(id, map[string]any{
+		"status": models.ScheduledStatusCanceled,
+	})
+	if err != nil {
+		return nil, scheduledMessageError(err)
+	}
+	return scheduled, nil
+}
+
+func (s *chatService) validateScheduleTime(at time.Time) error {
+	now := time.Now()
+	if !at.After(now) || at.After(now.Add(s.maxScheduleAhead)) {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+// getOwnScheduledMessage hides other users' scheduled messages as not found.
+func (s *chatService) getOwnScheduledMessage(id, userID string) (*models.ScheduledMessage, error) {
+	scheduled, err := s.repo.GetScheduledMessage(id)
+	if err != nil {
+		return nil, scheduledMessageError(err)
+	}
+	if scheduled.UserID != userID {
+		return nil, ErrScheduledMessageNotFound
+	}
+	return scheduled, nil
+}
+
+func scheduledMessageError(err error) error {
+	switch err {
+	case repository.ErrScheduledMessageNotFound:
+		return ErrScheduledMessageNotFound
+	case repository.ErrScheduledMessageNotPending:
+		return ErrScheduledMessageNotPending
+	}
+	return fmt.Errorf("failed to update scheduled message: %w", err)
+}
\ No newline at end of file
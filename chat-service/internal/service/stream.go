@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"chat-service/internal/events"
+	"chat-service/internal/repository"
+)
+
+// replayPageSize is the number of stored messages read per query while replaying.
+const replayPageSize = 500
+
+// StreamRoomEvents delivers room events to send until ctx is cancelled or the
+// subscription is dropped. When a room has a position above zero, stored
+// messages after that sequence number are replayed before live events.
+// Membership and room events are not stored, so only messages are replayed.
+// The user must be able to read every room streamed. An empty roomIDs
+// streams every room, within an organization including rooms created after
+// the stream starts, and is only open to admins.
+func (s *chatService) StreamRoomEvents(ctx context.Context, userID string, roomIDs []string, sinceSeq int64, roomSinceSeq map[string]int64, send func(events.Event) error) error {
+	// Subscribe before replaying so that nothing published meanwhile is lost
+	var sub *events.Subscription
+	if len(roomIDs) == 0 {
+		if !s.isAdmin(userID) {
+			return ErrPermissionDenied
+		}
+		if s.orgID != "" {
+			sub = s.events.SubscribeOrg(s.orgID)
+		} else {
+			sub = s.events.Subscribe(nil)
+		}
+	} else {
+		for _, roomID := range roomIDs {
+			if _, err := s.repo.GetRoomByID(roomID); err != nil {
+				if err == repository.ErrRoomNotFound {
+					return ErrRoomNotFound
+				}
+				return fmt.Errorf("failed to get room: %w", err)
+			}
+			readable, err := s.canReadRoom(roomID, userID)
+			if err != nil {
+				return err
+			}
+			if !readable {
+				return ErrNotRoomMember
+			}
+		}
+		sub = s.events.Subscribe(roomIDs)
+	}
+	defer sub.Close()
+
+	positions, err := s.streamPositions(roomIDs, sinceSeq, roomSinceSeq)
+	if err != nil {
+		return err
+	}
+
+	delivered := make(map[string]int64, len(positions))
+	for roomID, position := range positions {
+		delivered[roomID] = position
+		for {
+			messages, err := s.repo.GetMessagesSince(roomID, delivered[roomID], replayPageSize)
+			if err != nil {
+				return fmt.Errorf("failed to replay messages: %w", err)
+			}
+
+			for _, message := range messages {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				err := send(events.Event{
+					Type:      events.TypeMessage,
+					RoomID:    message.RoomID,
+					Seq:       message.Seq,
+					Message:   message,
+					Timestamp: message.CreatedAt,
+				})
+				if err != nil {
+					return err
+				}
+				delivered[roomID] = message.Seq
+			}
+
+			if len(messages) < replayPageSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Done():
+			return sub.Err()
+		case event := <-sub.Events():
+			// Skip live messages that the replay already covered
+			if event.Type == events.TypeMessage && event.Seq <= delivered[event.RoomID] {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// streamPositions resolves the replay position of every room that needs one.
+// Without roomIDs, positions are kept for the rooms the stream can see when
+// it starts.
+func (s *chatService) streamPositions(roomIDs []string, sinceSeq int64, roomSinceSeq map[string]int64) (map[string]int64, error) {
+	positions := make(map[string]int64)
+
+	if len(roomIDs) == 0 && (sinceSeq > 0 || len(roomSinceSeq) > 0) {
+		rooms, err := s.repo.GetAllRooms(true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rooms: %w", err)
+		}
+		for _, room := range rooms {
+			roomIDs = append(roomIDs, room.ID)
+		}
+	}
+
+	streamed := make(map[string]bool, len(roomIDs))
+	for _, roomID := range roomIDs {
+		streamed[roomID] = true
+		if sinceSeq > 0 {
+			positions[roomID] = sinceSeq
+		}
+	}
+
+	for roomID, position := range roomSinceSeq {
+		if !streamed[roomID] {
+			continue
+		}
+		if position > 0 {
+			positions[roomID] = position
+		} else {
+			delete(positions, roomID)
+		}
+	}
+
+	return positions, nil
+}
\ No newline at end of file
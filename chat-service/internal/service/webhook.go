@@ -0,0 +1,248 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+)
+
+// WebhookParams describes a new webhook. An empty RoomID subscribes to every
+// room and is reserved for admins; empty Events subscribes to every event.
+type WebhookParams struct {
+	RoomID string
+	URL    string
+	Secret string
+	Events []string
+}
+
+// WebhookUpdate holds the fields UpdateWebhook changes; nil fields are kept.
+type WebhookUpdate struct {
+	URL    *string
+	Secret *string
+	Events *[]string
+	// Active re-enables a disabled webhook or pauses an active one.
+	Active *bool
+}
+
+// CreateWebhook registers a webhook. If no secret is given one is generated;
+// either way it is only returned from this call.
+func (s *chatService) CreateWebhook(userID string, params WebhookParams) (*models.Webhook, error) {
+	if err := validateWebhookURL(params.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(params.Events); err != nil {
+		return nil, err
+	}
+
+	secret := params.Secret
+	if secret == "" {
+		generated, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	} else if len(secret) < 16 {
+		return nil, fmt.Errorf("%w: secret must be at least 16 characters", ErrInvalidWebhook)
+	}
+
+	webhook := &models.Webhook{
+		URL:       params.URL,
+		Secret:    secret,
+		Events:    params.Events,
+		Active:    true,
+		CreatedBy: userID,
+	}
+	if params.RoomID != "" {
+		if _, err := s.requireModerator(params.RoomID, userID); err != nil {
+			return nil, err
+		}
+		webhook.RoomID = &params.RoomID
+	} else if !s.isAdmin(userID) {
+		return nil, ErrPermissionDenied
+	}
+
+	if err := s.repo.CreateWebhook(webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// ListWebhooks lists a room's webhooks. Admins may leave roomID empty to list
+// every webhook.
+func (s *chatService) ListWebhooks(roomID, userID string) ([]*models.Webhook, error) {
+	if roomID != "" {
+		if _, err := s.requireModerator(roomID, userID); err != nil {
+			return nil, err
+		}
+	} else if !s.isAdmin(userID) {
+		return nil, ErrPermissionDenied
+	}
+
+	webhooks, err := s.repo.GetWebhooks(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (s *chatService) UpdateWebhook(webhookID, userID string, update WebhookUpdate) (*models.Webhook, error) {
+	if _, err := s.getManagedWebhook(webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]any{}
+	if update.URL != nil {
+		if err := validateWebhookURL(*update.URL); err != nil {
+			return nil, err
+		}
+		updates["url"] = *update.URL
+	}
+	if update.Secret != nil {
+		if len(*update.Secret) < 16 {
+			return nil, fmt.Errorf("%w: secret must be at least 16 characters", ErrInvalidWebhook)
+		}
+		updates["secret"] = *update.Secret
+	}
+	if update.Events != nil {
+		if err := validateWebhookEvents(*update.Events); err != nil {
+			return nil, err
+		}
+		events, err := json.Marshal(*update.Events)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode webhook events: %w", err)
+		}
+		updates["events"] = string(events)
+	}
+	if update.Active != nil {
+		updates["active"] = *update.Active
+		if *update.Active {
+			// Re-enabling starts over with a clean failure count
+			updates["failures"] = 0
+			updates["disabled_at"] = nil
+			updates["disabled_reason"] = ""
+		}
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("%w: nothing to update", ErrInvalidWebhook)
+	}
+
+	webhook, err := s.repo.UpdateWebhook(webhookID, updates)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	return webhook, nil
+}
+
+func (s *chatService) DeleteWebhook(webhookID, userID string) error {
+	if _, err := s.getManagedWebhook(webhookID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteWebhook(webhookID); err != nil {
+		return webhookError(err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the webhook's delivery log, newest first.
+func (s *chatService) ListWebhookDeliveries(webhookID, userID, status string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.getManagedWebhook(webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	deliveries, err := s.repo.GetWebhookDeliveries(webhookID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// getManagedWebhook returns the webhook if the user moderates its room, or is
+// an admin.
+func (s *chatService) getManagedWebhook(webhookID, userID string) (*models.Webhook, error) {
+	webhook, err := s.repo.GetWebhook(webhookID)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+
+	if s.isAdmin(userID) {
+		return webhook, nil
+	}
+	if webhook.RoomID == nil {
+		return nil, ErrPermissionDenied
+	}
+	if _, err := s.requireModerator(*webhook.RoomID, userID); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// dispatchWebhooks queues the event for every webhook subscribed to it. The
+// payload is encoded once here so every delivery and retry sends, and signs,
+// the same bytes.
+func (s *chatService) dispatchWebhooks(roomID, event string, data map[string]any) {
+	if !models.IsWebhookEvent(event) {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"event":     event,
+		"room_id":   roomID,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"data":      data,
+	})
+	if err != nil {
+		fmt.Printf("Failed to encode webhook payload: %v\n", err)
+		return
+	}
+
+	if _, err := s.repo.EnqueueWebhookDeliveries(roomID, event, string(payload)); err != nil {
+		fmt.Printf("Failed to queue webhook deliveries: %v\n", err)
+	}
+}
+
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https url", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []string) error {
+	for _, event := range events {
+		if !models.IsWebhookEvent(event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+	return nil
+}
+
+// generateToken returns 32 random bytes, hex encoded.
+func generateToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func webhookError(err error) error {
+	if err == repository.ErrWebhookNotFound {
+		return ErrWebhookNotFound
+	}
+	return fmt.Errorf("failed to update webhook: %w", err)
+}
\ No newline at end of file
@@ -0,0 +1,224 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"chat-service/internal/digest"
+	"chat-service/internal/mail"
+	"chat-service/internal/proto"
+	"chat-service/internal/repository"
+	"chat-service/pkg/centrifugo"
+	"chat-service/pkg/config"
+)
+
+const (
+	// digestList names the digests in unsubscribe tokens.
+	digestList = "digest"
+	// digestMentionsPerRoom is how many mentions a digest quotes per room.
+	digestMentionsPerRoom = 5
+	// digestExcerptLength is how many characters of a mention are quoted.
+	digestExcerptLength = 200
+)
+
+// DigestDirectory looks up the users digests are sent to, and signs their
+// unsubscribe links.
+type DigestDirectory interface {
+	Users(ctx context.Context, userIDs []string) (map[string]*proto.User, error)
+	UnsubscribeToken(ctx context.Context, userID, list string) (string, error)
+}
+
+// DigestWorker emails users a digest of the messages they missed once they
+// have been offline for DIGEST_OFFLINE_AFTER, and at most once every
+// DIGEST_PERIOD. Users count as online while they are subscribed to their
+// personal channel. A digest that fails is retried when its lease runs out.
+type DigestWorker struct {
+	repo           repository.ChatRepository
+	centrifugo     *centrifugo.CentrifugoClient
+	users          DigestDirectory
+	mailer         mail.Mailer
+	unsubscribeURL string
+	interval       time.Duration
+	offlineAfter   time.Duration
+	period         time.Duration
+	lease          time.Duration
+	batchSize      int
+}
+
+func NewDigestWorker(repo repository.ChatRepository, centrifugo *centrifugo.CentrifugoClient, users DigestDirectory, mailer mail.Mailer) *DigestWorker {
+	unsubscribeURL := os.Getenv("DIGEST_UNSUBSCRIBE_URL")
+	if unsubscribeURL == "" {
+		unsubscribeURL = "http://localhost:8080/api/v1/unsubscribe"
+	}
+
+	return &DigestWorker{
+		repo:           repo,
+		centrifugo:     centrifugo,
+		users:          users,
+		mailer:         mailer,
+		unsubscribeURL: unsubscribeURL,
+		interval:       config.Duration("DIGEST_INTERVAL", time.Minute),
+		offlineAfter:   config.Duration("DIGEST_OFFLINE_AFTER", time.Hour),
+		period:         config.Duration("DIGEST_PERIOD", 24*time.Hour),
+		lease:          config.Duration("DIGEST_LEASE", 5*time.Minute),
+		batchSize:      config.Int("DIGEST_BATCH_SIZE", 50),
+	}
+}
+
+// Run sends due digests on every tick until ctx is cancelled.
+func (w *DigestWorker) Run(ctx context.Context) {
+	log.Printf("Digest worker started (offline after: %s, period: %s)", w.offlineAfter, w.period)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Digest worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *DigestWorker) sendDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		userIDs, err := w.repo.ClaimDueDigests(time.Now(), w.offlineAfter, w.period, w.lease, w.batchSize)
+		if err != nil {
+			log.Printf("Digest: failed to claim due digests: %v", err)
+			return
+		}
+		if len(userIDs) == 0 {
+			return
+		}
+
+		users, err := w.users.Users(ctx, userIDs)
+		if err != nil {
+			// The digests are claimed again once their lease runs out
+			log.Printf("Digest: failed to look up users: %v", err)
+			return
+		}
+		for _, userID := range userIDs {
+			if ctx.Err() != nil {
+				return
+			}
+			w.send(ctx, userID, users[userID])
+		}
+
+		if len(userIDs) < w.batchSize {
+			return
+		}
+	}
+}
+
+// send mails the user's digest, unless they turn out to be online.
+func (w *DigestWorker) send(ctx context.Context, userID string, user *proto.User) {
+	now := time.Now()
+	online, err := w.centrifugo.Presence("user:#" + userID)
+	if err != nil {
+		log.Printf("Digest: failed to get presence of user %s: %v", userID, err)
+		return
+	}
+	if _, ok := online[userID]; ok {
+		if err := w.repo.MarkDigestUserSeen(userID, now); err != nil {
+			log.Printf("Digest: failed to mark user %s seen: %v", userID, err)
+		}
+		return
+	}
+
+	items, err := w.repo.GetDigestItems(userID)
+	if err != nil {
+		log.Printf("Digest: failed to get entries of user %s: %v", userID, err)
+		return
+	}
+	entryIDs := make([]string, len(items))
+	for i, item := range items {
+		entryIDs[i] = item.Entry.ID
+	}
+
+	report := buildDigest(items)
+	// Users without an address, or whose messages were all deleted, get
+	// nothing, but their entries are dropped all the same
+	if user != nil && user.Email != "" && len(report.Rooms) > 0 {
+		report.Username = user.Username
+		if err := w.mail(ctx, user, report); err != nil {
+			log.Printf("Digest: failed to mail user %s: %v", userID, err)
+			return
+		}
+	}
+
+	if err := w.repo.FinishDigest(userID, entryIDs, now); err != nil {
+		log.Printf("Digest: failed to finish digest of user %s: %v", userID, err)
+	}
+}
+
+func (w *DigestWorker) mail(ctx context.Context, user *proto.User, report *digest.Digest) error {
+	token, err := w.users.UnsubscribeToken(ctx, user.Id, digestList)
+	if err != nil {
+		return err
+	}
+	report.UnsubscribeURL = w.unsubscribeURL + "?token=" + url.QueryEscape(token)
+
+	text, html, err := report.Render()
+	if err != nil {
+		return err
+	}
+	return w.mailer.Send(ctx, &mail.Message{
+		To:      user.Email,
+		Subject: report.Subject(),
+		Text:    text,
+		HTML:    html,
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + report.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
+
+// buildDigest groups the entries by room, in the order each room first got
+// one, leaving out messages deleted since.
+func buildDigest(items []*repository.DigestItem) *digest.Digest {
+	report := &digest.Digest{}
+	rooms := make(map[string]*digest.Room)
+	for _, item := range items {
+		if item.Message == nil {
+			continue
+		}
+		room, ok := rooms[item.Entry.RoomID]
+		if !ok {
+			room = &digest.Room{Name: item.RoomName}
+			rooms[item.Entry.RoomID] = room
+			report.Rooms = append(report.Rooms, room)
+		}
+		room.Unread++
+		if !item.Entry.Mention {
+			continue
+		}
+		if len(room.Mentions) == digestMentionsPerRoom {
+			room.MoreMentions++
+			continue
+		}
+		room.Mentions = append(room.Mentions, digest.Excerpt{
+			Username: item.Message.Username,
+			Text:     excerpt(item.Message.PlainText),
+			SentAt:   item.Message.CreatedAt,
+		})
+	}
+	return report
+}
+
+func excerpt(text string) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) <= digestExcerptLength {
+		return text
+	}
+	return string(runes[:digestExcerptLength]) + "…"
+}
\ No newline at end of file
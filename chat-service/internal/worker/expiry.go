@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"chat-service/internal/service"
+	"chat-service/pkg/config"
+)
+
+// ExpiryWorker deletes disappearing messages once their time-to-live runs
+// out. Reads already hide expired messages, so the interval only bounds how
+// long they stay in the database and in Centrifugo history.
+type ExpiryWorker struct {
+	chatService service.ChatService
+	interval    time.Duration
+	batchSize   int
+}
+
+func NewExpiryWorker(chatService service.ChatService) *ExpiryWorker {
+	return &ExpiryWorker{
+		chatService: chatService,
+		interval:    config.Duration("MESSAGE_EXPIRY_INTERVAL", 5*time.Second),
+		batchSize:   config.Int("MESSAGE_EXPIRY_BATCH_SIZE", 500),
+	}
+}
+
+// Run deletes expired messages on every tick until ctx is cancelled.
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	log.Printf("Message expiry worker started (interval: %s)", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.expireDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Message expiry worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ExpiryWorker) expireDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		expired, err := w.chatService.ExpireMessages(w.batchSize)
+		if err != nil {
+			log.Printf("Message expiry: %v", err)
+			return
+		}
+
+		if expired < w.batchSize {
+			return
+		}
+	}
+}
\ No newline at end of file
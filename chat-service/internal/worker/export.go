@@ -0,0 +1,404 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"chat-service/internal/export"
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+	"chat-service/pkg/config"
+)
+
+// ExportWorker runs room export jobs one at a time per replica. The history
+// is read in seq order in batches and streamed into a temporary file, which
+// is renamed into place once it is complete. Jobs are leased like scheduled
+// messages, and the lease is extended with every progress update, so a job
+// abandoned by a stopped replica is picked up again from the start.
+type ExportWorker struct {
+	repo        repository.ChatRepository
+	dir         string
+	interval    time.Duration
+	batchSize   int
+	lease       time.Duration
+	maxAttempts int
+	ttl         time.Duration
+}
+
+func NewExportWorker(repo repository.ChatRepository) (*ExportWorker, error) {
+	dir := export.Dir()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	return &ExportWorker{
+		repo:        repo,
+		dir:         dir,
+		interval:    config.Duration("EXPORT_INTERVAL", 5*time.Second),
+		batchSize:   config.Int("EXPORT_BATCH_SIZE", 500),
+		lease:       config.Duration("EXPORT_LEASE", 2*time.Minute),
+		maxAttempts: config.Int("EXPORT_MAX_ATTEMPTS", 3),
+		ttl:         config.Duration("EXPORT_TTL", 72*time.Hour),
+	}, nil
+}
+
+// Run works through pending jobs on every tick until ctx is cancelled.
+func (w *ExportWorker) Run(ctx context.Context) {
+	log.Printf("Export worker started (dir: %s, interval: %s)", w.dir, w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.expire()
+		w.runPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Export worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ExportWorker) runPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.repo.ClaimExportJob(time.Now(), w.lease)
+		if err != nil {
+			log.Printf("Exports: failed to claim job: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		w.run(ctx, job)
+	}
+}
+
+func (w *ExportWorker) run(ctx context.Context, job *models.ExportJob) {
+	if job.Attempts > w.maxAttempts {
+		w.fail(job, errors.New("export was interrupted too many times"))
+		return
+	}
+
+	size, err := w.write(ctx, job)
+	if ctx.Err() != nil {
+		// Shutting down; the job is claimed again once its lease runs out
+		return
+	}
+	if errors.Is(err, repository.ErrExportJobNotFound) {
+		// The job or its room was deleted while it ran
+		os.Remove(export.FilePath(w.dir, job.ID))
+		return
+	}
+	if err != nil {
+		w.fail(job, err)
+		return
+	}
+
+	now := time.Now()
+	err = w.repo.UpdateRunningExportJob(job.ID, map[string]any{
+		"status":       models.ExportStatusCompleted,
+		"file_size":    size,
+		"lease_until":  nil,
+		"error":        "",
+		"completed_at": now,
+		"expires_at":   now.Add(w.ttl),
+	})
+	if err != nil {
+		log.Printf("Exports: failed to complete job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("Exports: exported room %s as %s (%d bytes)", job.RoomID, job.Format, size)
+}
+
+func (w *ExportWorker) fail(job *models.ExportJob, cause error) {
+	log.Printf("Exports: job %s failed: %v", job.ID, cause)
+	err := w.repo.UpdateRunningExportJob(job.ID, map[string]any{
+		"status":      models.ExportStatusFailed,
+		"lease_until": nil,
+		"error":       cause.Error(),
+	})
+	if err != nil && !errors.Is(err, repository.ErrExportJobNotFound) {
+		log.Printf("Exports: failed to update job %s: %v", job.ID, err)
+	}
+}
+
+// write exports the room into the job's file and returns its size.
+func (w *ExportWorker) write(ctx context.Context, job *models.ExportJob) (int64, error) {
+	room, err := w.repo.GetRoomByID(job.RoomID)
+	if err != nil {
+		if err == repository.ErrRoomNotFound {
+			return 0, repository.ErrExportJobNotFound
+		}
+		return 0, err
+	}
+
+	messages, err := w.repo.CountRoomMessages(room.ID)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := w.repo.CountDeletedMessages(room.ID)
+	if err != nil {
+		return 0, err
+	}
+	purges, err := w.repo.SummarizePurges(room.ID)
+	if err != nil {
+		return 0, err
+	}
+	exportedAt := time.Now()
+	err = w.repo.UpdateRunningExportJob(job.ID, map[string]any{
+		"total_messages":    messages + deleted,
+		"exported_messages": 0,
+		"file_name":         export.FileName(room.Name, job.Format, exportedAt),
+		"lease_until":       time.Now().Add(w.lease),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(w.dir, job.ID+"-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	writer, err := export.NewWriter(job.Format, tmp)
+	if err != nil {
+		return 0, err
+	}
+	err = writer.WriteHeader(&export.Header{
+		RoomID:      room.ID,
+		RoomName:    room.Name,
+		Description: room.Description,
+		ExportID:    job.ID,
+		RequestedBy: job.RequestedBy,
+		ExportedAt:  exportedAt,
+		Purged:      purgedSummary(purges),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	history := newHistory(w.repo, room.ID, w.batchSize)
+	var exported int64
+	for {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		records, err := history.next()
+		if err != nil {
+			return 0, err
+		}
+		if len(records) == 0 {
+			break
+		}
+		for _, record := range records {
+			if err := writer.WriteRecord(record); err != nil {
+				return 0, err
+			}
+		}
+
+		exported += int64(len(records))
+		err = w.repo.UpdateRunningExportJob(job.ID, map[string]any{
+			"exported_messages": exported,
+			"lease_until":       time.Now().Add(w.lease),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, err
+	}
+	info, err := tmp.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), export.FilePath(w.dir, job.ID)); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// expire removes the files of expired exports, and any file older than the
+// TTL, such as the exports of deleted rooms.
+func (w *ExportWorker) expire() {
+	jobs, err := w.repo.ExpireExportJobs(time.Now())
+	if err != nil {
+		log.Printf("Exports: failed to expire jobs: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		if err := os.Remove(export.FilePath(w.dir, job.ID)); err != nil && !os.IsNotExist(err) {
+			log.Printf("Exports: failed to remove file of job %s: %v", job.ID, err)
+		}
+	}
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		log.Printf("Exports: failed to list export directory: %v", err)
+		return
+	}
+	cutoff := time.Now().Add(-w.ttl - w.lease)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		os.Remove(filepath.Join(w.dir, entry.Name()))
+	}
+}
+
+// history reads a room's messages together with the records of its deleted
+// messages, merged in seq order, with the edits of each attached.
+type history struct {
+	repo      repository.ChatRepository
+	roomID    string
+	batchSize int
+
+	messages     []*models.Message
+	messagesSeq  int64
+	messagesDone bool
+	deleted      []*models.MessageRevision
+	deletedSeq   int64
+	deletedDone  bool
+}
+
+func newHistory(repo repository.ChatRepository, roomID string, batchSize int) *history {
+	// Start below zero to include messages sent before seq was assigned
+	return &history{repo: repo, roomID: roomID, batchSize: batchSize, messagesSeq: -1, deletedSeq: -1}
+}
+
+// next returns up to batchSize records, or none once the history is done.
+func (h *history) next() ([]*export.Record, error) {
+	var records []*export.Record
+	for len(records) < h.batchSize {
+		if err := h.fill(); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(h.messages) > 0 && (len(h.deleted) == 0 || h.messages[0].Seq <= h.deleted[0].Seq):
+			records = append(records, messageRecord(h.messages[0]))
+			h.messages = h.messages[1:]
+		case len(h.deleted) > 0:
+			records = append(records, deletedRecord(h.deleted[0]))
+			h.deleted = h.deleted[1:]
+		default:
+			return records, h.attachEdits(records)
+		}
+	}
+	return records, h.attachEdits(records)
+}
+
+// fill reads the next batch of whichever source ran empty.
+func (h *history) fill() error {
+	if len(h.messages) == 0 && !h.messagesDone {
+		messages, err := h.repo.GetMessagesSince(h.roomID, h.messagesSeq, h.batchSize)
+		if err != nil {
+			return err
+		}
+		h.messages = messages
+		h.messagesDone = len(messages) < h.batchSize
+		if len(messages) > 0 {
+			h.messagesSeq = messages[len(messages)-1].Seq
+		}
+	}
+	if len(h.deleted) == 0 && !h.deletedDone {
+		deleted, err := h.repo.GetDeletedMessages(h.roomID, h.deletedSeq, h.batchSize)
+		if err != nil {
+			return err
+		}
+		h.deleted = deleted
+		h.deletedDone = len(deleted) < h.batchSize
+		if len(deleted) > 0 {
+			h.deletedSeq = deleted[len(deleted)-1].Seq
+		}
+	}
+	return nil
+}
+
+func (h *history) attachEdits(records []*export.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*export.Record, len(records))
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		byID[record.MessageID] = record
+		ids = append(ids, record.MessageID)
+	}
+
+	edits, err := h.repo.GetMessageEdits(ids)
+	if err != nil {
+		return err
+	}
+	for _, edit := range edits {
+		record := byID[edit.MessageID]
+		record.Edits = append(record.Edits, export.Edit{
+			Content:    edit.Content,
+			ReplacedAt: edit.CreatedAt,
+		})
+		if record.Deleted {
+			// Deletions do not keep the message's own edit time
+			editedAt := edit.CreatedAt
+			record.EditedAt = &editedAt
+		}
+	}
+	return nil
+}
+
+func messageRecord(message *models.Message) *export.Record {
+	return &export.Record{
+		Seq:       message.Seq,
+		MessageID: message.ID,
+		Type:      message.Type,
+		UserID:    message.UserID,
+		Username:  message.Username,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+		EditedAt:  message.EditedAt,
+		Poll:      message.Poll,
+	}
+}
+
+func deletedRecord(revision *models.MessageRevision) *export.Record {
+	deletedAt := revision.CreatedAt
+	return &export.Record{
+		Seq:       revision.Seq,
+		MessageID: revision.MessageID,
+		Type:      revision.MessageType,
+		UserID:    revision.UserID,
+		Username:  revision.Username,
+		Content:   revision.Content,
+		CreatedAt: revision.MessageCreatedAt,
+		Deleted:   true,
+		DeletedAt: &deletedAt,
+		DeletedBy: revision.ActorID,
+		Expired:   revision.Action == models.MessageRevisionExpire,
+	}
+}
+
+func purgedSummary(summary *repository.PurgeSummary) *export.Purged {
+	if summary == nil {
+		return nil
+	}
+	return &export.Purged{
+		Messages:        summary.MessagesRemoved,
+		NewestRemovedAt: summary.NewestRemovedAt,
+	}
+}
\ No newline at end of file
@@ -0,0 +1,193 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"chat-service/internal/push"
+	"chat-service/internal/repository"
+	"chat-service/pkg/config"
+)
+
+// PushWorker pushes queued notifications to the devices of offline users.
+// Pushes are batched per device: once a device's oldest job has waited
+// PUSH_BATCH_WINDOW, all of its jobs go out as one notification, so a busy
+// room does not buzz a phone for every message. A batch that fails is
+// retried when its lease runs out, up to PUSH_MAX_ATTEMPTS times, and
+// devices whose tokens the push service no longer knows are removed.
+type PushWorker struct {
+	repo        repository.PushJobRepository
+	providers   map[string]push.PushProvider
+	interval    time.Duration
+	window      time.Duration
+	lease       time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+func NewPushWorker(repo repository.PushJobRepository, providers map[string]push.PushProvider) *PushWorker {
+	return &PushWorker{
+		repo:        repo,
+		providers:   providers,
+		interval:    config.Duration("PUSH_INTERVAL", 2*time.Second),
+		window:      config.Duration("PUSH_BATCH_WINDOW", 10*time.Second),
+		lease:       config.Duration("PUSH_LEASE", time.Minute),
+		batchSize:   config.Int("PUSH_BATCH_SIZE", 100),
+		maxAttempts: config.Int("PUSH_MAX_ATTEMPTS", 3),
+	}
+}
+
+// Run pushes due batches on every tick until ctx is cancelled.
+func (w *PushWorker) Run(ctx context.Context) {
+	log.Printf("Push worker started (interval: %s, batch window: %s)", w.interval, w.window)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.pushDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Push worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *PushWorker) pushDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		batches, err := w.repo.ClaimDuePushJobs(time.Now(), w.window, w.lease, w.batchSize)
+		if err != nil {
+			log.Printf("Push: failed to claim due jobs: %v", err)
+			return
+		}
+
+		byPlatform := make(map[string][]*repository.PushBatch)
+		for _, batch := range batches {
+			byPlatform[batch.Device.Platform] = append(byPlatform[batch.Device.Platform], batch)
+		}
+		for platform, platformBatches := range byPlatform {
+			w.send(ctx, platform, platformBatches)
+		}
+
+		if len(batches) < w.batchSize {
+			return
+		}
+	}
+}
+
+// send pushes one notification per device through the platform's provider.
+func (w *PushWorker) send(ctx context.Context, platform string, batches []*repository.PushBatch) {
+	provider, ok := w.providers[platform]
+	if !ok {
+		log.Printf("Push: no provider for platform %q, dropping %d batches", platform, len(batches))
+		for _, batch := range batches {
+			w.drop(batch)
+		}
+		return
+	}
+
+	messages := make([]push.Message, len(batches))
+	for i, batch := range batches {
+		messages[i] = push.Message{
+			Token:        batch.Device.Token,
+			Notification: batchNotification(batch),
+		}
+	}
+
+	results := provider.Send(ctx, messages)
+	if ctx.Err() != nil {
+		// Shutting down; the jobs are claimed again once their lease runs out
+		return
+	}
+
+	now := time.Now()
+	var unregistered []string
+	for i, result := range results {
+		batch := batches[i]
+		switch {
+		case result.Err == nil:
+			if err := w.repo.FinishPushJobs(jobIDs(batch), batch.Device.ID, now); err != nil {
+				log.Printf("Push: failed to finish jobs of device %s: %v", batch.Device.ID, err)
+			}
+		case result.Unregistered:
+			unregistered = append(unregistered, result.Token)
+		default:
+			attempts := 0
+			for _, job := range batch.Jobs {
+				attempts = max(attempts, job.Attempts)
+			}
+			if attempts >= w.maxAttempts {
+				log.Printf("Push: giving up on device %s after %d attempts: %v", batch.Device.ID, attempts, result.Err)
+				w.drop(batch)
+			} else {
+				log.Printf("Push: failed to push to device %s: %v", batch.Device.ID, result.Err)
+			}
+		}
+	}
+
+	if len(unregistered) > 0 {
+		if err := w.repo.DeletePushDevicesByToken(unregistered); err != nil {
+			log.Printf("Push: failed to remove unregistered devices: %v", err)
+		} else {
+			log.Printf("Push: removed %d unregistered %s devices", len(unregistered), platform)
+		}
+	}
+}
+
+func (w *PushWorker) drop(batch *repository.PushBatch) {
+	if err := w.repo.DropPushJobs(jobIDs(batch)); err != nil {
+		log.Printf("Push: failed to drop jobs of device %s: %v", batch.Device.ID, err)
+	}
+}
+
+// batchNotification sums up a device's jobs, which are in the order they
+// were queued. A single message is shown as it is; several show the latest
+// with a count.
+func batchNotification(batch *repository.PushBatch) push.Notification {
+	latest := batch.Jobs[len(batch.Jobs)-1]
+	count := len(batch.Jobs)
+
+	rooms := make(map[string]bool)
+	mention := false
+	for _, job := range batch.Jobs {
+		rooms[job.RoomID] = true
+		mention = mention || job.Mention
+	}
+
+	notification := push.Notification{
+		Title: latest.Title,
+		Body:  latest.Body,
+		Badge: count,
+		Data: map[string]string{
+			"room_id":    latest.RoomID,
+			"message_id": latest.MessageID,
+			"count":      strconv.Itoa(count),
+			"mention":    strconv.FormatBool(mention),
+		},
+	}
+	switch {
+	case len(rooms) > 1:
+		notification.Title = fmt.Sprintf("%d new messages in %d rooms", count, len(rooms))
+		notification.Body = latest.Title + ": " + latest.Body
+	case count > 1:
+		notification.Title = fmt.Sprintf("%s (%d new messages)", latest.Title, count)
+		notification.ThreadID = latest.RoomID
+	default:
+		notification.ThreadID = latest.RoomID
+	}
+	return notification
+}
+
+func jobIDs(batch *repository.PushBatch) []string {
+	ids := make([]string, len(batch.Jobs))
+	for i, job := range batch.Jobs {
+		ids[i] = job.ID
+	}
+	return ids
+}
\ No newline at end of file
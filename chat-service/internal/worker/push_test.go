@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/push"
+	"chat-service/internal/repository"
+)
+
+// fakePushRepo hands out its batches on the first claim and records what the
+// worker did with them.
+type fakePushRepo struct {
+	mu       sync.Mutex
+	batches  []*repository.PushBatch
+	finished []string
+	dropped  []string
+	deleted  []string
+}
+
+func (r *fakePushRepo) ClaimDuePushJobs(now time.Time, window, lease time.Duration, limit int) ([]*repository.PushBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batches := r.batches
+	r.batches = nil
+	return batches, nil
+}
+
+func (r *fakePushRepo) FinishPushJobs(jobIDs []string, deviceID string, pushedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = append(r.finished, jobIDs...)
+	return nil
+}
+
+func (r *fakePushRepo) DropPushJobs(jobIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dropped = append(r.dropped, jobIDs...)
+	return nil
+}
+
+func (r *fakePushRepo) DeletePushDevicesByToken(tokens []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, tokens...)
+	return nil
+}
+
+func testPushBatch(token string, attempts int) *repository.PushBatch {
+	return &repository.PushBatch{
+		Device: &models.PushDevice{ID: "device-" + token, UserID: "user-1", Platform: push.PlatformAPNs, Token: token},
+		Jobs: []*models.PushJob{{
+			ID:        "job-" + token,
+			DeviceID:  "device-" + token,
+			RoomID:    "room-1",
+			MessageID: "message-1",
+			Title:     "general",
+			Body:      "alice: hello",
+			Attempts:  attempts,
+			CreatedAt: time.Now().Add(-time.Minute),
+		}},
+	}
+}
+
+// apnsFailure is how the stub APNs server answers a device token.
+type apnsFailure struct {
+	status int
+	reason string
+}
+
+// newStubAPNsProvider points an APNs provider at a local stub that answers
+// the listed device tokens with their failure, and others with 200.
+func newStubAPNsProvider(t *testing.T, failures map[string]apnsFailure) push.PushProvider {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failure, ok := failures[strings.TrimPrefix(r.URL.Path, "/3/device/")]
+		if !ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(failure.status)
+		json.NewEncoder(w).Encode(map[string]string{"reason": failure.reason})
+	}))
+	t.Cleanup(server.Close)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "apns.p8")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := push.NewAPNsProvider(push.APNsConfig{
+		KeyFile:  keyFile,
+		KeyID:    "KEY123",
+		TeamID:   "TEAM456",
+		Topic:    "com.example.chat",
+		Endpoint: server.URL,
+	}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestPushWorkerPrunesUnregisteredDevices(t *testing.T) {
+	provider := newStubAPNsProvider(t, map[string]apnsFailure{
+		"gone":      {http.StatusGone, "Unregistered"},
+		"bad":       {http.StatusBadRequest, "BadDeviceToken"},
+		"down":      {http.StatusServiceUnavailable, "ServiceUnavailable"},
+		"exhausted": {http.StatusServiceUnavailable, "ServiceUnavailable"},
+	})
+	repo := &fakePushRepo{batches: []*repository.PushBatch{
+		testPushBatch("ok", 1),
+		testPushBatch("gone", 1),
+		testPushBatch("bad", 1),
+		testPushBatch("down", 1),
+		testPushBatch("exhausted", 3),
+	}}
+	worker := &PushWorker{
+		repo:        repo,
+		providers:   map[string]push.PushProvider{push.PlatformAPNs: provider},
+		window:      10 * time.Second,
+		lease:       time.Minute,
+		batchSize:   10,
+		maxAttempts: 3,
+	}
+
+	worker.pushDue(context.Background())
+
+	sort.Strings(repo.deleted)
+	if want := []string{"bad", "gone"}; !reflect.DeepEqual(repo.deleted, want) {
+		t.Errorf("deleted tokens = %v, want %v", repo.deleted, want)
+	}
+	if want := []string{"job-ok"}; !reflect.DeepEqual(repo.finished, want) {
+		t.Errorf("finished jobs = %v, want %v", repo.finished, want)
+	}
+	// A failing device keeps its jobs for a retry until it runs out of attempts
+	if want := []string{"job-exhausted"}; !reflect.DeepEqual(repo.dropped, want) {
+		t.Errorf("dropped jobs = %v, want %v", repo.dropped, want)
+	}
+}
+
+func TestPushWorkerDropsJobsWithoutProvider(t *testing.T) {
+	repo := &fakePushRepo{batches: []*repository.PushBatch{testPushBatch("ok", 1)}}
+	worker := &PushWorker{
+		repo:        repo,
+		providers:   map[string]push.PushProvider{},
+		batchSize:   10,
+		maxAttempts: 3,
+	}
+
+	worker.pushDue(context.Background())
+
+	if want := []string{"job-ok"}; !reflect.DeepEqual(repo.dropped, want) {
+		t.Errorf("dropped jobs = %v, want %v", repo.dropped, want)
+	}
+	if len(repo.deleted) != 0 {
+		t.Errorf("deleted tokens = %v, want none", repo.deleted)
+	}
+}
\ No newline at end of file
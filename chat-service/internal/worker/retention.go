@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+	"chat-service/pkg/centrifugo"
+	"chat-service/pkg/config"
+)
+
+// RetentionWorker periodically purges messages that fall outside their room's
+// retention policy. Messages are removed in small batches so that no single
+// statement holds row locks for long.
+type RetentionWorker struct {
+	repo          repository.ChatRepository
+	centrifugo    *centrifugo.CentrifugoClient
+	defaultPolicy models.RetentionPolicy
+	action        string
+	interval      time.Duration
+	batchSize     int
+	batchPause    time.Duration
+	keyWindow     time.Duration
+}
+
+func NewRetentionWorker(repo repository.ChatRepository, centrifugo *centrifugo.CentrifugoClient) (*RetentionWorker, error) {
+	defaultPolicy, err := models.ParseRetentionPolicy(os.Getenv("RETENTION_DEFAULT_POLICY"))
+	if err != nil {
+		return nil, err
+	}
+
+	action := os.Getenv("RETENTION_ACTION")
+	if action != models.PurgeActionArchive {
+		action = models.PurgeActionDelete
+	}
+
+	return &RetentionWorker{
+		repo:          repo,
+		centrifugo:    centrifugo,
+		defaultPolicy: defaultPolicy,
+		action:        action,
+		interval:      config.Duration("RETENTION_INTERVAL", time.Hour),
+		batchSize:     config.Int("RETENTION_BATCH_SIZE", 500),
+		batchPause:    config.Duration("RETENTION_BATCH_PAUSE", 100*time.Millisecond),
+		keyWindow:     config.Duration("IDEMPOTENCY_WINDOW", 24*time.Hour),
+	}, nil
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (w *RetentionWorker) Run(ctx context.Context) {
+	log.Printf("Retention worker started (default policy: %s, action: %s)", w.defaultPolicy, w.action)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.purgeAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Retention worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *RetentionWorker) purgeAll(ctx context.Context) {
+	// Idempotency keys outlive their window only to be replaced on the next retry
+	if _, err := w.repo.DeleteExpiredIdempotencyKeys(time.Now().Add(-w.keyWindow)); err != nil {
+		log.Printf("Retention: failed to delete expired idempotency keys: %v", err)
+	}
+
+	rooms, err := w.repo.GetAllRooms(true)
+	if err != nil {
+		log.Printf("Retention: failed to get rooms: %v", err)
+		return
+	}
+
+	for _, room := range rooms {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := w.purgeRoom(ctx, room); err != nil {
+			log.Printf("Retention: failed to purge room %s: %v", room.ID, err)
+		}
+	}
+}
+
+func (w *RetentionWorker) purgeRoom(ctx context.Context, room *models.Room) error {
+	policy := room.RetentionPolicy()
+	if policy.Type == models.RetentionDefault {
+		policy = w.defaultPolicy
+	}
+
+	startedAt := time.Now()
+
+	var cutoff repository.RetentionCutoff
+	switch policy.Type {
+	case models.RetentionDays:
+		cutoff.Before = startedAt.AddDate(0, 0, -policy.Value)
+	case models.RetentionMessages:
+		keepFrom, err := w.repo.GetCountCutoff(room.ID, policy.Value)
+		if err != nil {
+			return err
+		}
+		if keepFrom == 0 {
+			return nil
+		}
+		cutoff.BeforeSeq = keepFrom
+	default:
+		return nil
+	}
+
+	record := &models.PurgeRecord{
+		RoomID:    room.ID,
+		Policy:    policy.String(),
+		Action:    w.action,
+		StartedAt: startedAt,
+	}
+
+	for ctx.Err() == nil {
+		batch, err := w.repo.PurgeMessagesBefore(room.ID, cutoff, w.batchSize, w.action == models.PurgeActionArchive)
+		if err != nil {
+			return err
+		}
+
+		record.MessagesRemoved += batch.Removed
+		if batch.Oldest != nil && (record.OldestRemovedAt == nil || batch.Oldest.Before(*record.OldestRemovedAt)) {
+			record.OldestRemovedAt = batch.Oldest
+		}
+		if batch.Newest != nil && (record.NewestRemovedAt == nil || batch.Newest.After(*record.NewestRemovedAt)) {
+			record.NewestRemovedAt = batch.Newest
+		}
+
+		if batch.Removed < int64(w.batchSize) {
+			break
+		}
+		time.Sleep(w.batchPause)
+	}
+
+	// History of purged messages goes with them
+	if _, err := w.repo.PurgeMessageRevisionsBefore(room.ID, cutoff); err != nil {
+		return err
+	}
+
+	if record.MessagesRemoved == 0 {
+		return nil
+	}
+
+	remaining, err := w.repo.CountRoomMessages(room.ID)
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		record.RoomCleared = true
+		if err := w.centrifugo.HistoryRemove("room:" + room.ID); err != nil {
+			log.Printf("Retention: failed to remove history of room %s: %v", room.ID, err)
+		}
+	}
+
+	record.FinishedAt = time.Now()
+	if err := w.repo.CreatePurgeRecord(record); err != nil {
+		return err
+	}
+
+	log.Printf("Retention: %s %d messages from room %s (policy %s)", record.Action+"d", record.MessagesRemoved, room.ID, record.Policy)
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/moderation"
+	"chat-service/internal/repository"
+	"chat-service/internal/service"
+	"chat-service/pkg/config"
+	"gorm.io/gorm"
+)
+
+// SchedulerWorker sends scheduled messages once they are due. Messages are
+// leased before sending so replicas never send the same one concurrently, and
+// each is sent with a fixed client message ID so a retry after a crash is
+// deduplicated instead of posted twice.
+type SchedulerWorker struct {
+	repo        repository.ChatRepository
+	chatService service.ChatService
+	interval    time.Duration
+	batchSize   int
+	lease       time.Duration
+	maxAttempts int
+}
+
+func NewSchedulerWorker(repo repository.ChatRepository, chatService service.ChatService) *SchedulerWorker {
+	return &SchedulerWorker{
+		repo:        repo,
+		chatService: chatService,
+		interval:    config.Duration("SCHEDULER_INTERVAL", 5*time.Second),
+		batchSize:   config.Int("SCHEDULER_BATCH_SIZE", 100),
+		lease:       config.Duration("SCHEDULER_LEASE", time.Minute),
+		maxAttempts: config.Int("SCHEDULER_MAX_ATTEMPTS", 5),
+	}
+}
+
+// Run sends due messages on every tick until ctx is cancelled.
+func (w *SchedulerWorker) Run(ctx context.Context) {
+	log.Printf("Scheduler worker started (interval: %s)", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *SchedulerWorker) sendDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := w.repo.ClaimDueScheduledMessages(time.Now(), w.lease, w.batchSize)
+		if err != nil {
+			log.Printf("Scheduler: failed to claim due messages: %v", err)
+			return
+		}
+
+		for _, scheduled := range due {
+			w.send(scheduled)
+		}
+
+		if len(due) < w.batchSize {
+			return
+		}
+	}
+}
+
+func (w *SchedulerWorker) send(scheduled *models.ScheduledMessage) {
+	message, err := w.chatService.SendMessage(
+		scheduled.RoomID,
+		scheduled.UserID,
+		scheduled.Username,
+		scheduled.Content,
+		service.SendMessageOptions{ClientMessageID: scheduled.ClientMessageID(), Scheduled: true},
+	)
+
+	var updates map[string]any
+	var limited *service.RateLimitError
+	switch {
+	case err == nil:
+		updates = map[string]any{
+			"status":      models.ScheduledStatusSent,
+			"sent_at":     time.Now(),
+			"lease_until": nil,
+			"error":       "",
+			"message_id":  message.ID,
+		}
+	case errors.As(err, &limited):
+		// Try again on a later tick without using up an attempt
+		updates = map[string]any{
+			"status":      models.ScheduledStatusPending,
+			"lease_until": nil,
+			"attempts":    gorm.Expr("attempts - 1"),
+		}
+	case isPermanentSendError(err) || scheduled.Attempts >= w.maxAttempts:
+		log.Printf("Scheduler: giving up on scheduled message %s: %v", scheduled.ID, err)
+		updates = map[string]any{
+			"status":      models.ScheduledStatusFailed,
+			"lease_until": nil,
+			"error":       err.Error(),
+		}
+	default:
+		// Left in the sending state, the message is claimed again once the
+		// lease runs out
+		log.Printf("Scheduler: failed to send scheduled message %s: %v", scheduled.ID, err)
+		updates = map[string]any{"error": err.Error()}
+	}
+
+	if err := w.repo.FinishScheduledMessage(scheduled.ID, updates); err != nil {
+		log.Printf("Scheduler: failed to update scheduled message %s: %v", scheduled.ID, err)
+	}
+}
+
+// isPermanentSendError reports whether retrying the send cannot succeed.
+func isPermanentSendError(err error) bool {
+	var rejection *moderation.RejectionError
+	if errors.As(err, &rejection) {
+		return true
+	}
+
+	switch {
+	case errors.Is(err, service.ErrRoomNotFound),
+		errors.Is(err, service.ErrRoomArchived),
+		errors.Is(err, service.ErrNotRoomMember),
+		errors.Is(err, service.ErrUserMuted),
+		errors.Is(err, service.ErrInvalidMessage),
+		errors.Is(err, service.ErrCommandNotScheduled):
+		return true
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,178 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+	"chat-service/pkg/config"
+	"chat-service/pkg/signature"
+)
+
+// WebhookWorker delivers queued webhook events. Deliveries are leased like
+// scheduled messages, retried with exponential backoff, and a webhook that
+// keeps failing is disabled. Receivers should deduplicate on the
+// X-Webhook-Delivery header, since a delivery whose response was lost is
+// sent again.
+type WebhookWorker struct {
+	repo         repository.WebhookDeliveryRepository
+	client       *http.Client
+	interval     time.Duration
+	batchSize    int
+	lease        time.Duration
+	maxAttempts  int
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	disableAfter int
+}
+
+// NewWebhookWorker creates a worker that posts with client, or with a client
+// using WEBHOOK_TIMEOUT when client is nil.
+func NewWebhookWorker(repo repository.WebhookDeliveryRepository, client *http.Client) *WebhookWorker {
+	if client == nil {
+		client = &http.Client{Timeout: config.Duration("WEBHOOK_TIMEOUT", 10*time.Second)}
+	}
+
+	return &WebhookWorker{
+		repo:         repo,
+		client:       client,
+		interval:     config.Duration("WEBHOOK_INTERVAL", 2*time.Second),
+		batchSize:    config.Int("WEBHOOK_BATCH_SIZE", 50),
+		lease:        config.Duration("WEBHOOK_LEASE", time.Minute),
+		maxAttempts:  config.Int("WEBHOOK_MAX_ATTEMPTS", 8),
+		backoff:      config.Duration("WEBHOOK_RETRY_BACKOFF", 10*time.Second),
+		maxBackoff:   config.Duration("WEBHOOK_RETRY_MAX_BACKOFF", time.Hour),
+		disableAfter: config.Int("WEBHOOK_DISABLE_AFTER", 20),
+	}
+}
+
+// Run delivers due events on every tick until ctx is cancelled.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	log.Printf("Webhook worker started (interval: %s)", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Webhook worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *WebhookWorker) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		due, err := w.repo.ClaimDueWebhookDeliveries(time.Now(), w.lease, w.batchSize)
+		if err != nil {
+			log.Printf("Webhooks: failed to claim due deliveries: %v", err)
+			return
+		}
+
+		// One slow receiver should not hold up the rest of the batch
+		var wg sync.WaitGroup
+		for _, delivery := range due {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.deliver(ctx, delivery)
+			}()
+		}
+		wg.Wait()
+
+		if len(due) < w.batchSize {
+			return
+		}
+	}
+}
+
+func (w *WebhookWorker) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	attempt := repository.WebhookAttempt{
+		DeliveryID:   delivery.ID,
+		WebhookID:    delivery.WebhookID,
+		DisableAfter: w.disableAfter,
+	}
+
+	statusCode, err := w.post(ctx, delivery)
+	if ctx.Err() != nil {
+		// Shutting down; the delivery is claimed again once its lease runs out
+		return
+	}
+	attempt.ResponseStatus = statusCode
+	if err == nil {
+		attempt.Succeeded = true
+	} else {
+		attempt.Error = err.Error()
+		if delivery.Attempts < w.maxAttempts {
+			attempt.RetryAt = time.Now().Add(w.retryDelay(delivery.Attempts))
+		} else {
+			log.Printf("Webhooks: giving up on delivery %s: %v", delivery.ID, err)
+		}
+	}
+
+	disabled, err := w.repo.FinishWebhookDelivery(attempt)
+	if err != nil {
+		log.Printf("Webhooks: failed to update delivery %s: %v", delivery.ID, err)
+		return
+	}
+	if disabled {
+		log.Printf("Webhooks: disabled webhook %s after %d consecutive failures", delivery.WebhookID, w.disableAfter)
+	}
+}
+
+// post sends the delivery and returns the receiver's status code. Any status
+// outside 2xx is an error.
+func (w *WebhookWorker) post(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	webhook := delivery.Webhook
+	if webhook == nil {
+		return 0, fmt.Errorf("webhook %s not found", delivery.WebhookID)
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "chat-service-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", "sha256="+signature.Sign(webhook.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay doubles the backoff with every failed attempt, up to maxBackoff.
+func (w *WebhookWorker) retryDelay(attempts int) time.Duration {
+	delay := w.backoff
+	for i := 1; i < attempts && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.maxBackoff)
+}
\ No newline at end of file
@@ -0,0 +1,451 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-service/internal/models"
+	"chat-service/internal/repository"
+	"chat-service/pkg/signature"
+)
+
+// fakeWebhookRepo keeps deliveries in memory with the claim and finish rules
+// of the Postgres repository: due pending deliveries and sending deliveries
+// whose lease ran out are claimed, and consecutive failures disable the
+// webhook and fail its queued deliveries.
+type fakeWebhookRepo struct {
+	mu         sync.Mutex
+	webhooks   map[string]*models.Webhook
+	deliveries []*models.WebhookDelivery
+	attempts   []repository.WebhookAttempt
+}
+
+func newFakeWebhookRepo(webhook *models.Webhook) *fakeWebhookRepo {
+	return &fakeWebhookRepo{webhooks: map[string]*models.Webhook{webhook.ID: webhook}}
+}
+
+func (r *fakeWebhookRepo) enqueue(id, webhookID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, &models.WebhookDelivery{
+		ID:            id,
+		WebhookID:     webhookID,
+		RoomID:        "room-1",
+		Event:         models.WebhookEventMessageCreated,
+		Payload:       `{"event":"message_created","message":{"id":"` + id + `"}}`,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: time.Now().Add(-time.Second),
+	})
+}
+
+func (r *fakeWebhookRepo) ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		webhook := r.webhooks[delivery.WebhookID]
+		if !webhook.Active {
+			continue
+		}
+		due := delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now)
+		expired := delivery.Status == models.WebhookDeliverySending && delivery.LeaseUntil.Before(now)
+		if !due && !expired {
+			continue
+		}
+
+		leaseUntil := now.Add(lease)
+		delivery.Status = models.WebhookDeliverySending
+		delivery.LeaseUntil = &leaseUntil
+		delivery.Attempts++
+
+		copied := *delivery
+		webhookCopy := *webhook
+		copied.Webhook = &webhookCopy
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepo) FinishWebhookDelivery(attempt repository.WebhookAttempt) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts = append(r.attempts, attempt)
+
+	delivery := r.delivery(attempt.DeliveryID)
+	if delivery.Status == models.WebhookDeliverySending {
+		delivery.LeaseUntil = nil
+		delivery.ResponseStatus = attempt.ResponseStatus
+		delivery.Error = attempt.Error
+		switch {
+		case attempt.Succeeded:
+			delivery.Status = models.WebhookDeliverySucceeded
+		case !attempt.RetryAt.IsZero():
+			delivery.Status = models.WebhookDeliveryPending
+			delivery.NextAttemptAt = attempt.RetryAt
+		default:
+			delivery.Status = models.WebhookDeliveryFailed
+		}
+	}
+
+	webhook := r.webhooks[attempt.WebhookID]
+	if attempt.Succeeded {
+		webhook.Failures = 0
+		return false, nil
+	}
+
+	webhook.Failures++
+	if !webhook.Active || attempt.DisableAfter <= 0 || webhook.Failures < attempt.DisableAfter {
+		return false, nil
+	}
+
+	webhook.Active = false
+	for _, queued := range r.deliveries {
+		if queued.WebhookID == webhook.ID && queued.Status == models.WebhookDeliveryPending {
+			queued.Status = models.WebhookDeliveryFailed
+			queued.Error = "webhook disabled"
+		}
+	}
+	return true, nil
+}
+
+func (r *fakeWebhookRepo) delivery(id string) *models.WebhookDelivery {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return delivery
+		}
+	}
+	return nil
+}
+
+// snapshot returns a copy of the delivery, safe to read while the worker runs.
+func (r *fakeWebhookRepo) snapshot(id string) models.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.delivery(id)
+}
+
+func (r *fakeWebhookRepo) expireLeases() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for _, delivery := range r.deliveries {
+		if delivery.LeaseUntil != nil {
+			delivery.LeaseUntil = &past
+		}
+	}
+}
+
+func (r *fakeWebhookRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, delivery := range r.deliveries {
+		delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func testWebhook(url string) *models.Webhook {
+	return &models.Webhook{ID: "webhook-1", URL: url, Secret: "s3cret", Active: true}
+}
+
+func testWebhookWorker(repo repository.WebhookDeliveryRepository, client *http.Client) *WebhookWorker {
+	return &WebhookWorker{
+		repo:         repo,
+		client:       client,
+		batchSize:    10,
+		lease:        time.Minute,
+		maxAttempts:  3,
+		backoff:      10 * time.Second,
+		maxBackoff:   time.Minute,
+		disableAfter: 5,
+	}
+}
+
+func TestWebhookWorkerSignsDeliveries(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := testWebhook(server.URL)
+	repo := newFakeWebhookRepo(webhook)
+	repo.enqueue("delivery-1", webhook.ID)
+
+	testWebhookWorker(repo, server.Client()).deliverDue(context.Background())
+
+	var req received
+	select {
+	case req = <-requests:
+	default:
+		t.Fatal("receiver got no request")
+	}
+
+	delivery := repo.snapshot("delivery-1")
+	if string(req.body) != delivery.Payload {
+		t.Errorf("body = %s, want %s", req.body, delivery.Payload)
+	}
+	if got := req.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := req.header.Get("X-Webhook-Event"); got != models.WebhookEventMessageCreated {
+		t.Errorf("X-Webhook-Event = %q", got)
+	}
+	if got := req.header.Get("X-Webhook-Delivery"); got != "delivery-1" {
+		t.Errorf("X-Webhook-Delivery = %q", got)
+	}
+
+	timestamp, err := strconv.ParseInt(req.header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid X-Webhook-Timestamp: %v", err)
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age < -time.Second || age > time.Minute {
+		t.Errorf("X-Webhook-Timestamp is %s old", age)
+	}
+	want := "sha256=" + signature.Sign(webhook.Secret, timestamp, req.body)
+	if got := req.header.Get("X-Webhook-Signature"); got != want {
+		t.Errorf("X-Webhook-Signature = %q, want %q", got, want)
+	}
+	if got := req.header.Get("X-Webhook-Signature"); got == "sha256="+signature.Sign("other", timestamp, req.body) {
+		t.Error("signature does not depend on the secret")
+	}
+
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("delivery = %s with status %d, want succeeded with 204", delivery.Status, delivery.ResponseStatus)
+	}
+}
+
+func TestWebhookWorkerRetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook := testWebhook(server.URL)
+	repo := newFakeWebhookRepo(webhook)
+	repo.enqueue("delivery-1", webhook.ID)
+	worker := testWebhookWorker(repo, server.Client())
+
+	wantDelays := []time.Duration{10 * time.Second, 20 * time.Second}
+	for i, wantDelay := range wantDelays {
+		before := time.Now()
+		worker.deliverDue(context.Background())
+
+		delivery := repo.snapshot("delivery-1")
+		if delivery.Status != models.WebhookDeliveryPending {
+			t.Fatalf("attempt %d: status = %s, want pending", i+1, delivery.Status)
+		}
+		if delivery.ResponseStatus != http.StatusServiceUnavailable || delivery.Error == "" {
+			t.Errorf("attempt %d: response %d, error %q", i+1, delivery.ResponseStatus, delivery.Error)
+		}
+		if delay := delivery.NextAttemptAt.Sub(before); delay < wantDelay || delay > wantDelay+time.Second {
+			t.Errorf("attempt %d: retried after %s, want %s", i+1, delay, wantDelay)
+		}
+
+		// Not due yet, so nothing is sent
+		worker.deliverDue(context.Background())
+		if got := repo.snapshot("delivery-1").Attempts; got != i+1 {
+			t.Fatalf("attempts = %d before the retry was due, want %d", got, i+1)
+		}
+		repo.makeDue()
+	}
+
+	// The last allowed attempt gives up on the delivery
+	worker.deliverDue(context.Background())
+	delivery := repo.snapshot("delivery-1")
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.Attempts != worker.maxAttempts {
+		t.Errorf("delivery = %s after %d attempts, want failed after %d", delivery.Status, delivery.Attempts, worker.maxAttempts)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	worker := &WebhookWorker{backoff: 10 * time.Second, maxBackoff: time.Hour}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 6, want: 320 * time.Second},
+		{attempts: 9, want: 2560 * time.Second},
+		{attempts: 10, want: time.Hour},
+		{attempts: 100, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := worker.retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookWorkerReclaimsExpiredLeases(t *testing.T) {
+	var mu sync.Mutex
+	var deliveryIDs []string
+	first := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		deliveryIDs = append(deliveryIDs, r.Header.Get("X-Webhook-Delivery"))
+		calls := len(deliveryIDs)
+		mu.Unlock()
+
+		if calls == 1 {
+			// Hang until the worker gives up, as if it crashed mid-request
+			close(first)
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	webhook := testWebhook(server.URL)
+	repo := newFakeWebhookRepo(webhook)
+	repo.enqueue("delivery-1", webhook.ID)
+	worker := testWebhookWorker(repo, server.Client())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.deliverDue(ctx)
+		close(done)
+	}()
+	<-first
+	cancel()
+	<-done
+
+	// The interrupted attempt is not recorded and keeps its lease
+	if delivery := repo.snapshot("delivery-1"); delivery.Status != models.WebhookDeliverySending {
+		t.Fatalf("status = %s after shutdown, want sending", delivery.Status)
+	}
+	worker.deliverDue(context.Background())
+	mu.Lock()
+	calls := len(deliveryIDs)
+	mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("leased delivery was sent again before its lease ran out")
+	}
+
+	repo.expireLeases()
+	worker.deliverDue(context.Background())
+
+	delivery := repo.snapshot("delivery-1")
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 2 {
+		t.Errorf("delivery = %s after %d attempts, want succeeded after 2", delivery.Status, delivery.Attempts)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deliveryIDs) != 2 || deliveryIDs[0] != deliveryIDs[1] {
+		t.Errorf("receiver got deliveries %v, want the same delivery ID twice", deliveryIDs)
+	}
+}
+
+func TestWebhookWorkerDisablesFailingWebhook(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := testWebhook(server.URL)
+	repo := newFakeWebhookRepo(webhook)
+	for _, id := range []string{"delivery-1", "delivery-2", "delivery-3"} {
+		repo.enqueue(id, webhook.ID)
+	}
+	worker := testWebhookWorker(repo, server.Client())
+	worker.batchSize = 1
+	worker.maxAttempts = 10
+	worker.disableAfter = 4
+
+	for range 10 {
+		worker.deliverDue(context.Background())
+		repo.makeDue()
+	}
+
+	if webhook.Active {
+		t.Fatal("webhook still active after repeated failures")
+	}
+	if webhook.Failures != worker.disableAfter {
+		t.Errorf("failures = %d, want %d", webhook.Failures, worker.disableAfter)
+	}
+	mu.Lock()
+	if calls != worker.disableAfter {
+		t.Errorf("receiver got %d requests, want %d", calls, worker.disableAfter)
+	}
+	mu.Unlock()
+	for _, id := range []string{"delivery-1", "delivery-2", "delivery-3"} {
+		if delivery := repo.snapshot(id); delivery.Status != models.WebhookDeliveryFailed {
+			t.Errorf("%s = %s, want failed", id, delivery.Status)
+		}
+	}
+	for _, attempt := range repo.attempts {
+		if attempt.DisableAfter != worker.disableAfter {
+			t.Errorf("attempt passed DisableAfter %d, want %d", attempt.DisableAfter, worker.disableAfter)
+		}
+	}
+}
+
+func TestWebhookWorkerSuccessResetsFailures(t *testing.T) {
+	var mu sync.Mutex
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := testWebhook(server.URL)
+	repo := newFakeWebhookRepo(webhook)
+	repo.enqueue("delivery-1", webhook.ID)
+	worker := testWebhookWorker(repo, server.Client())
+	worker.disableAfter = 3
+
+	worker.deliverDue(context.Background())
+	repo.makeDue()
+	worker.deliverDue(context.Background())
+	if webhook.Failures != 2 {
+		t.Fatalf("failures = %d, want 2", webhook.Failures)
+	}
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	repo.makeDue()
+	worker.deliverDue(context.Background())
+
+	if webhook.Failures != 0 || !webhook.Active {
+		t.Errorf("webhook has %d failures, active %t, want 0 and active", webhook.Failures, webhook.Active)
+	}
+}
\ No newline at end of file
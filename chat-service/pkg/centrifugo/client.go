@@ -1,12 +1,14 @@
 package centrifugo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/centrifugal/centrifuge-go"
@@ -55,11 +57,22 @@ func NewCentrifugoClient() (*CentrifugoClient, error) {
 
 	return &CentrifugoClient{
 		client: client,
-		apiURL: centrifugoURL,
+		apiURL: httpURL(centrifugoURL),
 		apiKey: apiKey,
 	}, nil
 }
 
+// httpURL converts the WebSocket endpoint into the base URL of the HTTP API.
+func httpURL(centrifugoURL string) string {
+	if rest, ok := strings.CutPrefix(centrifugoURL, "wss://"); ok {
+		return "https://" + rest
+	}
+	if rest, ok := strings.CutPrefix(centrifugoURL, "ws://"); ok {
+		return "http://" + rest
+	}
+	return centrifugoURL
+}
+
 func (c *CentrifugoClient) Publish(channel string, data any) error {
 	// Using HTTP API for publishing
 	apiURL := c.apiURL + "/api/publish"
@@ -101,6 +114,91 @@ func (c *CentrifugoClient) Publish(channel string, data any) error {
 	return nil
 }
 
+// HistoryRemove clears the publication history of a channel.
+func (c *CentrifugoClient) HistoryRemove(channel string) error {
+	if err := c.call("history_remove", map[string]any{"channel": channel}, nil); err != nil {
+		return err
+	}
+
+	log.Printf("Removed history of channel: %s", channel)
+	return nil
+}
+
+// Unsubscribe removes the user's subscription to the channel on all of their
+// connections.
+func (c *CentrifugoClient) Unsubscribe(userID, channel string) error {
+	params := map[string]any{"user": userID, "channel": channel}
+	if err := c.call("unsubscribe", params, nil); err != nil {
+		return err
+	}
+
+	log.Printf("Unsubscribed user %s from channel: %s", userID, channel)
+	return nil
+}
+
+// Presence returns the IDs of the users currently subscribed to the channel.
+func (c *CentrifugoClient) Presence(channel string) (map[string]struct{}, error) {
+	var result struct {
+		Presence map[string]struct {
+			User string `json:"user"`
+		} `json:"presence"`
+	}
+	if err := c.call("presence", map[string]any{"channel": channel}, &result); err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]struct{}, len(result.Presence))
+	for _, client := range result.Presence {
+		users[client.User] = struct{}{}
+	}
+	return users, nil
+}
+
+// call invokes a Centrifugo server API method and decodes its result into result.
+func (c *CentrifugoClient) call(method string, params any, result any) error {
+	jsonData, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequest("POST", c.apiURL+"/api/"+method, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed with status: %d", method, resp.StatusCode)
+	}
+
+	var apiResp struct {
+		Error  *centrifuge.Error `json:"error,omitempty"`
+		Result json.RawMessage   `json:"result,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if apiResp.Error != nil {
+		return fmt.Errorf("%s failed: %s", method, apiResp.Error.Message)
+	}
+
+	if result != nil && len(apiResp.Result) > 0 {
+		if err := json.Unmarshal(apiResp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
 func (c *CentrifugoClient) Close() {
 	c.client.Close()
 }
@@ -0,0 +1,138 @@
+package clients
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"chat-service/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// maxUsersPerLookup matches the most users auth-service returns per call.
+const maxUsersPerLookup = 500
+
+type AuthClient struct {
+	conn    *grpc.ClientConn
+	service proto.AuthServiceClient
+}
+
+func NewAuthClient() (*AuthClient, error) {
+	authServiceURL := os.Getenv("AUTH_SERVICE_URL")
+	if authServiceURL == "" {
+		authServiceURL = "localhost:50051"
+	}
+
+	conn, err := grpc.Dial(authServiceURL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTimeout(10*time.Second),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	service := proto.NewAuthServiceClient(conn)
+	log.Printf("Connected to Auth Service at %s", authServiceURL)
+
+	return &AuthClient{
+		conn:    conn,
+		service: service,
+	}, nil
+}
+
+func (c *AuthClient) Close() error {
+	return c.conn.Close()
+}
+
+// Usernames maps the given user IDs to usernames. Users auth-service does not
+// know are left out.
+func (c *AuthClient) Usernames(ctx context.Context, userIDs []string) (map[string]string, error) {
+	users, err := c.Users(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usernames := make(map[string]string, len(users))
+	for id, user := range users {
+		usernames[id] = user.Username
+	}
+	return usernames, nil
+}
+
+// Users looks up the given users by ID. Users auth-service does not know are
+// left out.
+func (c *AuthClient) Users(ctx context.Context, userIDs []string) (map[string]*proto.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	users := make(map[string]*proto.User, len(userIDs))
+	for start := 0; start < len(userIDs); start += maxUsersPerLookup {
+		end := min(start+maxUsersPerLookup, len(userIDs))
+		resp, err := c.service.GetUsers(ctx, &proto.GetUsersRequest{
+			UserIds: userIDs[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range resp.Users {
+			users[user.Id] = user
+		}
+	}
+	return users, nil
+}
+
+// IsOrgMember reports whether the user belongs to the organization.
+func (c *AuthClient) IsOrgMember(ctx context.Context, orgID, userID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := c.service.GetOrganizationMember(ctx, &proto.GetOrganizationMemberRequest{
+		OrgId:  orgID,
+		UserId: userID,
+	})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnsubscribeToken returns a token, signed by auth-service, for a link that
+// unsubscribes the user from the mailing.
+func (c *AuthClient) UnsubscribeToken(ctx context.Context, userID, list string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, err := c.service.CreateUnsubscribeToken(ctx, &proto.CreateUnsubscribeTokenRequest{
+		UserId: userID,
+		List:   list,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+// ImportUsers maps users of another chat system to auth-service accounts in
+// the organization, creating missing ones unless dryRun is set. Results are
+// in the order of users. auth-service takes at most maxUsersPerLookup users
+// per call; the importer batches larger archives.
+func (c *AuthClient) ImportUsers(ctx context.Context, orgID string, users []*proto.ImportUser, dryRun bool) ([]*proto.ImportedUser, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	resp, err := c.service.ImportUsers(ctx, &proto.ImportUsersRequest{
+		Users:  users,
+		DryRun: dryRun,
+		OrgId:  orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}
\ No newline at end of file
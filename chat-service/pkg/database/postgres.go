@@ -60,12 +60,127 @@ func AutoMigrate(db *gorm.DB) error {
 	err = db.AutoMigrate(
 		&models.Room{},
 		&models.Message{},
+		&models.MessageIdempotencyKey{},
 		&models.RoomMember{},
+		&models.PinnedMessage{},
+		&models.ArchivedMessage{},
+		&models.PurgeRecord{},
+		&models.ModerationItem{},
+		&models.SendRateBucket{},
+		&models.SlowModeEntry{},
+		&models.ModerationAction{},
+		&models.RoomBan{},
+		&models.ScheduledMessage{},
+		&models.Poll{},
+		&models.PollOption{},
+		&models.PollVote{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.IncomingWebhook{},
+		&models.MessageRevision{},
+		&models.ExportJob{},
+		&models.Reaction{},
+		&models.Bookmark{},
+		&models.NotificationSettings{},
+		&models.PushDevice{},
+		&models.PushJob{},
+		&models.DigestEntry{},
+		&models.DigestState{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to auto migrate models: %w", err)
 	}
 
+	// A user can report a given message only once
+	err = db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_moderation_items_report
+		ON moderation_items (message_id, reporter_id) WHERE source = 'report'`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create report index: %w", err)
+	}
+
+	err = backfillOrganizations(db)
+	if err != nil {
+		return fmt.Errorf("failed to backfill organizations: %w", err)
+	}
+
+	// Imported rooms and messages are found again by their external IDs.
+	// Organizations may import the same workspace.
+	err = db.Exec("DROP INDEX IF EXISTS idx_rooms_external_id").Error
+	if err != nil {
+		return fmt.Errorf("failed to drop room external ID index: %w", err)
+	}
+	err = db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_rooms_org_external_id
+		ON rooms (org_id, external_id) WHERE external_id <> ''`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create room external ID index: %w", err)
+	}
+	err = db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_external_id
+		ON messages (room_id, external_id) WHERE external_id <> ''`).Error
+	if err != nil {
+		return fmt.Errorf("failed to create message external ID index: %w", err)
+	}
+
+	err = backfillMessageSeq(db)
+	if err != nil {
+		return fmt.Errorf("failed to backfill message sequence numbers: %w", err)
+	}
+
 	log.Println("Chat Service database migration completed successfully")
 	return nil
+}
+
+// backfillMessageSeq numbers messages created before per-room sequence numbers
+// existed, and only then adds the unique index that the numbering relies on.
+func backfillMessageSeq(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var pending int64
+		err := tx.Raw("SELECT COUNT(*) FROM (SELECT 1 FROM messages WHERE seq = 0 LIMIT 1) AS pending").Scan(&pending).Error
+		if err != nil {
+			return err
+		}
+		if pending == 0 {
+			return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_room_seq ON messages (room_id, seq)").Error
+		}
+
+		err = tx.Exec(`
+			UPDATE messages SET seq = numbered.seq
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY room_id ORDER BY created_at, id) AS seq
+				FROM messages
+			) AS numbered
+			WHERE messages.id = numbered.id AND messages.seq = 0`).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`
+			UPDATE rooms SET last_seq = latest.seq
+			FROM (SELECT room_id, MAX(seq) AS seq FROM messages GROUP BY room_id) AS latest
+			WHERE rooms.id::text = latest.room_id AND rooms.last_seq < latest.seq`).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_room_seq ON messages (room_id, seq)").Error
+	})
+}
+
+// backfillOrganizations moves rooms created before organizations existed into
+// the default organization, along with webhooks, which take their room's
+// organization.
+func backfillOrganizations(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE rooms SET org_id = ? WHERE org_id IS NULL", models.DefaultOrganizationID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`
+			UPDATE webhooks SET org_id = rooms.org_id
+			FROM rooms
+			WHERE webhooks.org_id IS NULL AND rooms.id::text = webhooks.room_id`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE webhooks SET org_id = ? WHERE org_id IS NULL", models.DefaultOrganizationID).Error
+	})
 }
\ No newline at end of file
@@ -0,0 +1,21 @@
+// Package signature signs outgoing HTTP requests so receivers can verify they
+// came from chat-service.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" under secret.
+// Receivers recompute it to verify the signature header, and check the
+// timestamp to reject replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
\ No newline at end of file